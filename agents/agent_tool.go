@@ -0,0 +1,229 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sayerxofficial/langchaingo/callbacks"
+	"github.com/sayerxofficial/langchaingo/chains"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/tools"
+)
+
+// _defaultSupervisorMessage is the system message of a supervisor. It is
+// followed by the descriptions of its agents.
+const _defaultSupervisorMessage = `You are a supervisor managing a team of agents. ` +
+	`Send each part of the task to the agent best suited for it, ` +
+	`then combine their answers into the final answer. Do not do the work of the agents yourself.
+
+The agents of your team are:
+%s`
+
+// AgentTool exposes an agent, or any chain with a single input and a single
+// output, as a tool that other agents can call. Agents that call each other
+// through agent tools form a team: a supervisor sends parts of a task to
+// specialist agents, and agents can hand off to each other.
+//
+// An agent tool refuses to call an agent that is already running in the same
+// call chain, so agents that keep sending work back and forth are told about
+// the loop instead of running forever. Runs given a trace with WithTrace
+// record which agent produced each message.
+type AgentTool struct {
+	// AgentName is the name of the tool, and of the agent in the trace.
+	AgentName string
+	// AgentDescription tells the calling agent what the agent can do.
+	AgentDescription string
+	// Chain is the agent, usually an *Executor.
+	Chain chains.Chain
+	// Memory, if set, replaces the memory of the chain. Give several agents
+	// the same memory to share a conversation between them. If nil, the
+	// agent uses its own memory.
+	Memory schema.Memory
+	// Handoff makes the agent take over: its answer ends the run of the
+	// calling agent and becomes its final answer.
+	Handoff bool
+}
+
+var _ tools.Tool = (*AgentTool)(nil)
+
+// NewAgentTool creates a tool that runs the given agent. WithMemory sets a
+// memory shared with the other agents, and WithHandoff makes the agent answer
+// in place of the calling agent.
+func NewAgentTool(name, description string, chain chains.Chain, opts ...Option) *AgentTool {
+	options := Options{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &AgentTool{
+		AgentName:        name,
+		AgentDescription: description,
+		Chain:            chain,
+		Memory:           options.memory,
+		Handoff:          options.handoff,
+	}
+}
+
+// Name returns the name of the agent.
+func (t *AgentTool) Name() string {
+	return t.AgentName
+}
+
+// Description returns the description of the agent.
+func (t *AgentTool) Description() string {
+	return t.AgentDescription
+}
+
+// ReturnDirect reports whether the answer of the agent ends the run of the
+// calling agent.
+func (t *AgentTool) ReturnDirect() bool {
+	return t.Handoff
+}
+
+// Call runs the agent with the input and returns its answer. It returns a
+// *HandoffLoopError if the agent is already running in the call chain.
+func (t *AgentTool) Call(ctx context.Context, input string) (string, error) {
+	path := handoffPath(ctx)
+	if slices.Contains(path, t.AgentName) {
+		loopErr := &HandoffLoopError{Agent: t.AgentName, Path: append(slices.Clone(path), t.AgentName)}
+		recordTrace(ctx, TraceEntry{Agent: t.AgentName, Path: path, Input: input, Err: loopErr})
+		return "", loopErr
+	}
+
+	var chain chains.Chain = t.Chain
+	if t.Memory != nil {
+		chain = memoryChain{Chain: t.Chain, memory: t.Memory}
+	}
+
+	// The agent runs as a nested run, with its own checkpoint, budget and
+	// events.
+	callCtx := context.WithValue(nestedRunContext(ctx), handoffPathKey{}, append(slices.Clone(path), t.AgentName))
+	output, err := chains.Run(callCtx, chain, input)
+	recordTrace(ctx, TraceEntry{Agent: t.AgentName, Path: path, Input: input, Output: output, Err: err})
+	if err != nil {
+		return "", fmt.Errorf("agent %s: %w", t.AgentName, err)
+	}
+	return output, nil
+}
+
+// NewSupervisor creates an executor whose agent sends the task to the given
+// agents, usually agent tools, and combines their answers. The supervisor
+// calls the agents through the native tool calling of the model. Use
+// NewOpenAIOption().WithSystemMessage to replace the default instructions.
+func NewSupervisor(llm llms.Model, agents []tools.Tool, opts ...Option) *Executor {
+	var team strings.Builder
+	for _, agent := range agents {
+		fmt.Fprintf(&team, "- %s: %s\n", agent.Name(), agent.Description())
+	}
+	opts = append([]Option{
+		NewOpenAIOption().WithSystemMessage(fmt.Sprintf(_defaultSupervisorMessage, team.String())),
+	}, opts...)
+
+	return NewExecutor(NewToolCallingAgent(llm, agents, opts...), opts...)
+}
+
+// HandoffLoopError is returned by an agent tool that refuses to call an agent
+// already running in the same call chain. The executor gives its message to
+// the calling agent as the observation of the action.
+type HandoffLoopError struct {
+	// Agent is the agent that was not called.
+	Agent string
+	// Path lists the agents of the loop, outermost first, ending with Agent.
+	Path []string
+}
+
+func (e *HandoffLoopError) Error() string {
+	return fmt.Sprintf("%s is already working on this task (%s). "+
+		"Do not send the task back: answer with what you have.", e.Agent, strings.Join(e.Path, " -> "))
+}
+
+func (e *HandoffLoopError) Unwrap() error {
+	return ErrHandoffLoop
+}
+
+type handoffPathKey struct{}
+
+// handoffPath returns the agents that are running in the call chain of ctx,
+// outermost first.
+func handoffPath(ctx context.Context) []string {
+	path, _ := ctx.Value(handoffPathKey{}).([]string)
+	return path
+}
+
+// memoryChain replaces the memory of a chain.
+type memoryChain struct {
+	chains.Chain
+	memory schema.Memory
+}
+
+func (c memoryChain) GetMemory() schema.Memory { //nolint:ireturn
+	return c.memory
+}
+
+func (c memoryChain) GetCallbackHandler() callbacks.Handler { //nolint:ireturn
+	if h, ok := c.Chain.(callbacks.HandlerHaver); ok {
+		return h.GetCallbackHandler()
+	}
+	return nil
+}
+
+// TraceEntry records a call to an agent tool: the input the calling agent
+// sent and the output the agent produced.
+type TraceEntry struct {
+	// Agent is the agent that was called, which produced Output.
+	Agent string
+	// Path lists the agents that were running when the agent was called,
+	// outermost first. The last one sent Input. It is empty when the agent
+	// was called by the top-level agent.
+	Path []string
+	// Input is the message sent to the agent.
+	Input string
+	// Output is the answer of the agent.
+	Output string
+	// Err is the error of the agent, or a *HandoffLoopError if the call was
+	// refused.
+	Err error
+}
+
+// Trace collects the calls made to agent tools during a run. It is safe for
+// concurrent use.
+type Trace struct {
+	mu      sync.Mutex
+	entries []TraceEntry
+}
+
+// Entries returns the recorded calls, in the order they completed.
+func (t *Trace) Entries() []TraceEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return slices.Clone(t.entries)
+}
+
+type traceKey struct{}
+
+// WithTrace returns a context that records the calls to agent tools in the
+// returned trace.
+func WithTrace(ctx context.Context) (context.Context, *Trace) {
+	trace := &Trace{}
+	return context.WithValue(ctx, traceKey{}, trace), trace
+}
+
+func recordTrace(ctx context.Context, entry TraceEntry) {
+	trace, ok := ctx.Value(traceKey{}).(*Trace)
+	if !ok {
+		return
+	}
+	trace.mu.Lock()
+	defer trace.mu.Unlock()
+	trace.entries = append(trace.entries, entry)
+}
+
+// returnsDirect reports whether the answer of the tool ends the run.
+func returnsDirect(tool tools.Tool) bool {
+	rd, ok := tool.(interface{ ReturnDirect() bool })
+	return ok && rd.ReturnDirect()
+}
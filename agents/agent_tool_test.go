@@ -0,0 +1,173 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/agents"
+	"github.com/sayerxofficial/langchaingo/chains"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/memory"
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/tools"
+
+	"github.com/stretchr/testify/require"
+)
+
+// answeringAgent returns an executor whose model answers with the given
+// text.
+func answeringAgent(answers ...string) *agents.Executor {
+	turns := make([]*llms.ContentChoice, len(answers))
+	for i, answer := range answers {
+		turns[i] = &llms.ContentChoice{Content: answer}
+	}
+	return agents.NewExecutor(agents.NewToolCallingAgent(&scriptedLLM{turns: turns}, nil))
+}
+
+func TestSupervisor(t *testing.T) {
+	t.Parallel()
+
+	research := agents.NewAgentTool("research", "Finds facts.", answeringAgent("Go 1.0 was released in 2012."))
+	writer := agents.NewAgentTool("writer", "Writes prose.", answeringAgent("A short poem about Go."))
+
+	llm := &scriptedLLM{turns: []*llms.ContentChoice{
+		{ToolCalls: []llms.ToolCall{
+			toolCall("call_1", "research", `{"__arg1":"When was Go 1.0 released?"}`),
+			toolCall("call_2", "writer", `{"__arg1":"Write a poem about Go."}`),
+		}},
+		{Content: "Go 1.0 was released in 2012. A short poem about Go."},
+	}}
+	supervisor := agents.NewSupervisor(llm, []tools.Tool{research, writer})
+
+	ctx, trace := agents.WithTrace(t.Context())
+	result, err := chains.Run(ctx, supervisor, "Tell me about Go in verse.")
+	require.NoError(t, err)
+	require.Equal(t, "Go 1.0 was released in 2012. A short poem about Go.", result)
+
+	system := llm.messages[0][0]
+	require.Equal(t, llms.ChatMessageTypeSystem, system.Role)
+	require.Contains(t, system.Parts[0].(llms.TextContent).Text, "- research: Finds facts.")
+
+	require.Equal(t, []agents.TraceEntry{
+		{Agent: "research", Input: "When was Go 1.0 released?", Output: "Go 1.0 was released in 2012."},
+		{Agent: "writer", Input: "Write a poem about Go.", Output: "A short poem about Go."},
+	}, trace.Entries())
+}
+
+func TestAgentToolLoopDetection(t *testing.T) {
+	t.Parallel()
+
+	toolA := &agents.AgentTool{AgentName: "a", AgentDescription: "Agent a."}
+	toolB := &agents.AgentTool{AgentName: "b", AgentDescription: "Agent b."}
+
+	llmA := &scriptedLLM{turns: []*llms.ContentChoice{
+		{ToolCalls: []llms.ToolCall{toolCall("call_1", "b", `{"__arg1":"help"}`)}},
+		{Content: "a done"},
+	}}
+	llmB := &scriptedLLM{turns: []*llms.ContentChoice{
+		{ToolCalls: []llms.ToolCall{toolCall("call_2", "a", `{"__arg1":"back to you"}`)}},
+		{Content: "b done"},
+	}}
+	toolA.Chain = agents.NewExecutor(agents.NewToolCallingAgent(llmA, []tools.Tool{toolB}))
+	toolB.Chain = agents.NewExecutor(agents.NewToolCallingAgent(llmB, []tools.Tool{toolA}))
+
+	ctx, trace := agents.WithTrace(t.Context())
+	output, err := toolA.Call(ctx, "start")
+	require.NoError(t, err)
+	require.Equal(t, "a done", output)
+
+	entries := trace.Entries()
+	require.Len(t, entries, 3)
+	require.Equal(t, "a", entries[0].Agent)
+	require.Equal(t, []string{"a", "b"}, entries[0].Path)
+	require.ErrorIs(t, entries[0].Err, agents.ErrHandoffLoop)
+	require.Equal(t, agents.TraceEntry{Agent: "b", Path: []string{"a"}, Input: "help", Output: "b done"}, entries[1])
+	require.Equal(t, agents.TraceEntry{Agent: "a", Input: "start", Output: "a done"}, entries[2])
+
+	// Agent b was told about the loop.
+	loopResult := llmB.messages[1][len(llmB.messages[1])-1].Parts[0].(llms.ToolCallResponse)
+	require.Contains(t, loopResult.Content, "a -> b -> a")
+}
+
+func TestAgentToolHandoffWithSharedMemory(t *testing.T) {
+	t.Parallel()
+
+	shared := memory.NewConversationBuffer()
+	billing := agents.NewAgentTool("billing", "Handles invoices.",
+		answeringAgent("Your invoice was sent again."),
+		agents.WithMemory(shared), agents.WithHandoff())
+
+	llm := &scriptedLLM{turns: []*llms.ContentChoice{
+		{ToolCalls: []llms.ToolCall{toolCall("call_1", "billing", `{"__arg1":"Resend my invoice."}`)}},
+	}}
+	supervisor := agents.NewSupervisor(llm, []tools.Tool{billing})
+
+	result, err := chains.Run(t.Context(), supervisor, "Resend my invoice.")
+	require.NoError(t, err)
+	require.Equal(t, "Your invoice was sent again.", result)
+	require.Len(t, llm.messages, 1)
+
+	messages, err := shared.ChatHistory.Messages(t.Context())
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	require.Equal(t, "Resend my invoice.", messages[0].GetContent())
+	require.Equal(t, "Your invoice was sent again.", messages[1].GetContent())
+}
+
+func TestAgentToolHandoffOnlyOnSuccess(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejected", func(t *testing.T) {
+		t.Parallel()
+
+		billing := agents.NewAgentTool("billing", "Handles invoices.", answeringAgent("Sent."), agents.WithHandoff())
+		llm := &scriptedLLM{turns: []*llms.ContentChoice{
+			{ToolCalls: []llms.ToolCall{toolCall("call_1", "billing", `{"__arg1":"Resend my invoice."}`)}},
+			{Content: "I am not allowed to resend invoices."},
+		}}
+		supervisor := agents.NewSupervisor(llm, []tools.Tool{billing}, agents.WithApprovalHandler(agents.ApprovalFunc(
+			func(context.Context, schema.AgentAction) (agents.ApprovalDecision, error) {
+				return agents.Reject("Not allowed."), nil
+			})))
+
+		result, err := chains.Run(t.Context(), supervisor, "Resend my invoice.")
+		require.NoError(t, err)
+		require.Equal(t, "I am not allowed to resend invoices.", result)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		t.Parallel()
+
+		billing := agents.NewAgentTool("billing", "Handles invoices.",
+			agents.NewExecutor(&testAgent{err: errors.New("billing unavailable")}), agents.WithHandoff())
+		llm := &scriptedLLM{turns: []*llms.ContentChoice{
+			{ToolCalls: []llms.ToolCall{toolCall("call_1", "billing", `{"__arg1":"Resend my invoice."}`)}},
+			{Content: "Billing is unavailable, try again later."},
+		}}
+		supervisor := agents.NewSupervisor(llm, []tools.Tool{billing},
+			agents.WithToolErrorHandler(agents.NewToolErrorObservationHandler(nil)))
+
+		result, err := chains.Run(t.Context(), supervisor, "Resend my invoice.")
+		require.NoError(t, err)
+		require.Equal(t, "Billing is unavailable, try again later.", result)
+	})
+
+	t.Run("loop", func(t *testing.T) {
+		t.Parallel()
+
+		billing := &agents.AgentTool{AgentName: "billing", AgentDescription: "Handles invoices.", Handoff: true}
+		llm := &scriptedLLM{turns: []*llms.ContentChoice{
+			{ToolCalls: []llms.ToolCall{toolCall("call_1", "billing", `{"__arg1":"Over to you."}`)}},
+			{Content: "Your invoice was sent again."},
+		}}
+		billing.Chain = agents.NewExecutor(agents.NewToolCallingAgent(llm, []tools.Tool{billing}))
+
+		// The refusal is given to the agent instead of ending its run.
+		output, err := billing.Call(t.Context(), "Resend my invoice.")
+		require.NoError(t, err)
+		require.Equal(t, "Your invoice was sent again.", output)
+		refusal := llm.messages[1][len(llm.messages[1])-1].Parts[0].(llms.ToolCallResponse)
+		require.Contains(t, refusal.Content, "billing -> billing")
+	})
+}
@@ -0,0 +1,86 @@
+package agents
+
+import (
+	"context"
+
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/tools"
+)
+
+// _defaultRejectionObservation is the observation given to the agent when an
+// action is rejected without a reason.
+const _defaultRejectionObservation = "The action was rejected by the user."
+
+// ApprovalDecision is the answer of an ApprovalHandler to an action.
+type ApprovalDecision struct {
+	// Approved reports whether the tool of the action can run.
+	Approved bool
+	// Reason is given to the agent as the observation of a rejected action.
+	Reason string
+	// ToolInput, if not empty, replaces the input of an approved action.
+	ToolInput string
+}
+
+// Approve returns a decision that lets the action run as planned.
+func Approve() ApprovalDecision {
+	return ApprovalDecision{Approved: true}
+}
+
+// ApproveWithInput returns a decision that lets the action run with the given
+// tool input instead of the planned one.
+func ApproveWithInput(input string) ApprovalDecision {
+	return ApprovalDecision{Approved: true, ToolInput: input}
+}
+
+// Reject returns a decision that stops the action from running. The reason is
+// given to the agent as the observation of the action.
+func Reject(reason string) ApprovalDecision {
+	return ApprovalDecision{Reason: reason}
+}
+
+// ApprovalHandler is given every action planned by the agent before its tool
+// runs. It can approve the action, change its input, or reject it. A changed
+// input is validated like the planned one. An error stops the run with a
+// ToolError.
+type ApprovalHandler interface {
+	ApproveAction(ctx context.Context, action schema.AgentAction) (ApprovalDecision, error)
+}
+
+// ApprovalFunc is an adapter that allows the use of a function as an
+// ApprovalHandler.
+type ApprovalFunc func(ctx context.Context, action schema.AgentAction) (ApprovalDecision, error)
+
+// ApproveAction calls f(ctx, action).
+func (f ApprovalFunc) ApproveAction(ctx context.Context, action schema.AgentAction) (ApprovalDecision, error) {
+	return f(ctx, action)
+}
+
+// approve asks the approval handler of the executor about the action. It
+// returns the action to run, or the observation to give the agent if the
+// action was rejected. The steps of a plan are not asked about: the step
+// executor asks about the tools they call.
+func (e *Executor) approve(
+	ctx context.Context,
+	tool tools.Tool,
+	action schema.AgentAction,
+) (schema.AgentAction, string, bool, error) {
+	if _, ok := tool.(planStepTool); ok || e.ApprovalHandler == nil {
+		return action, "", true, nil
+	}
+
+	decision, err := e.ApprovalHandler.ApproveAction(ctx, action)
+	if err != nil {
+		return action, "", false, err
+	}
+	if !decision.Approved {
+		if decision.Reason == "" {
+			return action, _defaultRejectionObservation, false, nil
+		}
+		return action, decision.Reason, false, nil
+	}
+	if decision.ToolInput != "" {
+		action.ToolInput = decision.ToolInput
+	}
+
+	return action, "", true, nil
+}
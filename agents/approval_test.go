@@ -0,0 +1,193 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/agents"
+	"github.com/sayerxofficial/langchaingo/chains"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/tools"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutorApprovalWithMRKLAgent(t *testing.T) {
+	t.Parallel()
+
+	var ran []string
+	shell := funcTool{name: "shell", call: func(_ context.Context, input string) (string, error) {
+		ran = append(ran, input)
+		return "done: " + input, nil
+	}}
+
+	tests := []struct {
+		name            string
+		decision        agents.ApprovalDecision
+		wantRan         []string
+		wantObservation string
+	}{
+		{
+			name:            "approve",
+			decision:        agents.Approve(),
+			wantRan:         []string{"rm -rf /tmp/cache"},
+			wantObservation: "done: rm -rf /tmp/cache",
+		},
+		{
+			name:            "edit",
+			decision:        agents.ApproveWithInput("ls /tmp/cache"),
+			wantRan:         []string{"ls /tmp/cache"},
+			wantObservation: "done: ls /tmp/cache",
+		},
+		{
+			name:            "reject",
+			decision:        agents.Reject("deleting files is not allowed"),
+			wantObservation: "deleting files is not allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran = nil
+			llm := &scriptedLLM{turns: textTurns(
+				"Thought: I should clear the cache.\nAction: shell\nAction Input: rm -rf /tmp/cache",
+				"Thought: I know the answer.\nFinal Answer: finished",
+			)}
+			a := agents.NewOneShotAgent(llm, []tools.Tool{shell})
+
+			var asked []schema.AgentAction
+			executor := agents.NewExecutor(a, agents.WithApprovalHandler(
+				agents.ApprovalFunc(func(_ context.Context, action schema.AgentAction) (agents.ApprovalDecision, error) {
+					asked = append(asked, action)
+					return tt.decision, nil
+				}),
+			))
+
+			result, err := chains.Run(t.Context(), executor, "clear the cache")
+			require.NoError(t, err)
+			require.Equal(t, "finished", strings.TrimSpace(result))
+			require.Len(t, asked, 1)
+			require.Equal(t, "shell", asked[0].Tool)
+			require.Equal(t, tt.wantRan, ran)
+			require.Contains(t, llm.prompts()[1], "Observation: "+tt.wantObservation)
+		})
+	}
+}
+
+func TestExecutorApprovalWithOpenAIFunctionsAgent(t *testing.T) {
+	t.Parallel()
+
+	var ran []string
+	weather := funcTool{name: "weather", call: func(_ context.Context, input string) (string, error) {
+		ran = append(ran, input)
+		return "sunny", nil
+	}}
+	llm := &scriptedLLM{turns: []*llms.ContentChoice{
+		{ToolCalls: []llms.ToolCall{toolCall("call_1", "weather", `{"__arg1":"Paris"}`)}},
+		{Content: "It is sunny"},
+	}}
+
+	executor := agents.NewExecutor(
+		agents.NewOpenAIFunctionsAgent(llm, []tools.Tool{weather}),
+		agents.WithReturnIntermediateSteps(),
+		agents.WithApprovalHandler(agents.ApprovalFunc(
+			func(_ context.Context, action schema.AgentAction) (agents.ApprovalDecision, error) {
+				require.Equal(t, "call_1", action.ToolID)
+				return agents.ApproveWithInput("Lyon"), nil
+			},
+		)),
+	)
+
+	result, err := chains.Call(t.Context(), executor, map[string]any{"input": "What is the weather?"})
+	require.NoError(t, err)
+	require.Equal(t, "It is sunny", result["output"])
+	require.Equal(t, []string{"Lyon"}, ran)
+
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 1)
+	require.Equal(t, "Lyon", steps[0].Action.ToolInput)
+}
+
+func TestExecutorApprovalError(t *testing.T) {
+	t.Parallel()
+
+	errDenied := errors.New("approval service unavailable")
+	a := &testAgent{
+		actions: []schema.AgentAction{{Tool: "shell", ToolInput: "ls"}},
+		tools: []tools.Tool{funcTool{name: "shell", call: func(context.Context, string) (string, error) {
+			t.Error("tool must not run")
+			return "", nil
+		}}},
+	}
+	executor := agents.NewExecutor(a, agents.WithApprovalHandler(agents.ApprovalFunc(
+		func(context.Context, schema.AgentAction) (agents.ApprovalDecision, error) {
+			return agents.ApprovalDecision{}, errDenied
+		},
+	)))
+
+	_, err := chains.Call(t.Context(), executor, nil)
+	require.ErrorIs(t, err, errDenied)
+
+	// The steps completed before the error are kept, as for tool errors.
+	a.actions = []schema.AgentAction{{Tool: "echo", ToolInput: "hi"}, {Tool: "shell", ToolInput: "ls"}}
+	a.tools = append(a.tools, funcTool{name: "echo", call: func(_ context.Context, input string) (string, error) {
+		return input, nil
+	}})
+	executor = agents.NewExecutor(a, agents.WithApprovalHandler(agents.ApprovalFunc(
+		func(_ context.Context, action schema.AgentAction) (agents.ApprovalDecision, error) {
+			if action.Tool == "shell" {
+				return agents.ApprovalDecision{}, errDenied
+			}
+			return agents.Approve(), nil
+		},
+	)))
+
+	_, err = chains.Call(t.Context(), executor, nil)
+	require.ErrorIs(t, err, errDenied)
+	var toolErr *agents.ToolError
+	require.ErrorAs(t, err, &toolErr)
+	require.Equal(t, "shell", toolErr.Action.Tool)
+	require.Len(t, toolErr.Steps, 1)
+	require.Equal(t, "hi", toolErr.Steps[0].Observation)
+}
+
+func TestExecutorApprovalInputValidated(t *testing.T) {
+	t.Parallel()
+
+	llm := &scriptedLLM{turns: []*llms.ContentChoice{
+		{ToolCalls: []llms.ToolCall{toolCall("call_1", "weather", `{"city":"Paris"}`)}},
+		{ToolCalls: []llms.ToolCall{toolCall("call_2", "weather", `{"city":"Lyon"}`)}},
+		{Content: "It is sunny in Lyon."},
+	}}
+
+	// The input given by the approval handler is checked like the planned one.
+	var asked int
+	executor := agents.NewExecutor(
+		agents.NewToolCallingAgent(llm, []tools.Tool{newWeatherTool(t)}),
+		agents.WithReturnIntermediateSteps(),
+		agents.WithApprovalHandler(agents.ApprovalFunc(
+			func(context.Context, schema.AgentAction) (agents.ApprovalDecision, error) {
+				asked++
+				if asked == 1 {
+					return agents.ApproveWithInput(`{"town":"Lyon"}`), nil
+				}
+				return agents.Approve(), nil
+			},
+		)),
+	)
+
+	result, err := chains.Call(t.Context(), executor, map[string]any{"input": "What is the weather?"})
+	require.NoError(t, err)
+	require.Equal(t, "It is sunny in Lyon.", result["output"])
+
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 2)
+	require.Equal(t, `{"town":"Lyon"}`, steps[0].Action.ToolInput)
+	require.Contains(t, steps[0].Observation, "The arguments for weather are invalid")
+	require.Equal(t, "sunny in Lyon", steps[1].Observation)
+}
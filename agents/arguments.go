@@ -0,0 +1,149 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sayerxofficial/langchaingo/jsonschema"
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/tools"
+)
+
+// _defaultArgumentAttempts is the default number of invalid calls in a row
+// to a tool that are sent back to the agent for correction.
+const _defaultArgumentAttempts = 3
+
+// ToolArgumentStats describes the validation of the arguments of a tool
+// call.
+type ToolArgumentStats struct {
+	// Tool is the name of the tool.
+	Tool string
+	// Err is the validation error, or nil if the arguments are valid.
+	Err error
+	// Failures is the number of invalid calls in a row to the tool,
+	// including this one.
+	Failures int
+	// Corrected reports whether valid arguments followed invalid ones.
+	Corrected bool
+}
+
+// ToolArgumentsHandler is an optional extension of callbacks.Handler.
+// Handlers that implement it are told about every validation of the
+// arguments of a tool call.
+type ToolArgumentsHandler interface {
+	HandleToolArguments(ctx context.Context, stats ToolArgumentStats)
+}
+
+type argumentsKey struct{}
+
+// argumentTracker counts the invalid calls in a row to each tool of a run.
+type argumentTracker struct {
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+// callsFunctions reports whether the agent calls tools natively, with the
+// arguments of the function definition of the tool. Other agents, such as
+// the zero shot react agent, give tools a raw string, so their arguments are
+// not validated.
+func callsFunctions(agent Agent) bool {
+	switch agent.(type) {
+	case *ToolCallingAgent, *OpenAIFunctionsAgent:
+		return true
+	}
+	return false
+}
+
+// validateArguments checks the input of an action against the parameters of
+// its tool, for tools that describe them. It returns the observation that
+// asks the agent to correct invalid arguments, or an error once the agent
+// has run out of attempts. Tools taking a single string are not validated,
+// since they also accept the raw string.
+func (e *Executor) validateArguments(
+	ctx context.Context,
+	tool tools.Tool,
+	action schema.AgentAction,
+) (string, error) {
+	tracker, ok := ctx.Value(argumentsKey{}).(*argumentTracker)
+	if !ok {
+		return "", nil
+	}
+	ft, ok := tool.(tools.FunctionTool)
+	if !ok {
+		return "", nil
+	}
+	def, ok := parametersDefinition(ft.FunctionDefinition().Parameters)
+	if !ok || tools.IsSingleStringInput(def) {
+		return "", nil
+	}
+
+	input := action.ToolInput
+	if input == "" {
+		input = "{}"
+	}
+	validationErr := def.Validate([]byte(input))
+
+	// Tools are looked up regardless of case, so are their failures.
+	key := strings.ToUpper(action.Tool)
+	tracker.mu.Lock()
+	stats := ToolArgumentStats{Tool: action.Tool, Err: validationErr}
+	if validationErr != nil {
+		tracker.failures[key]++
+		stats.Failures = tracker.failures[key]
+	} else {
+		stats.Corrected = tracker.failures[key] > 0
+		delete(tracker.failures, key)
+	}
+	tracker.mu.Unlock()
+
+	if h, ok := e.CallbacksHandler.(ToolArgumentsHandler); ok {
+		h.HandleToolArguments(ctx, stats)
+	}
+
+	if validationErr == nil {
+		return "", nil
+	}
+	attempts := e.ToolArgumentAttempts
+	if attempts == 0 {
+		attempts = _defaultArgumentAttempts
+	}
+	if stats.Failures > attempts {
+		return "", fmt.Errorf("%w: %w", ErrInvalidToolArguments, validationErr)
+	}
+
+	schemaJSON, err := json.Marshal(def)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("The arguments for %s are invalid: %v. "+
+		"Call the tool again with arguments matching this JSON schema: %s", action.Tool, validationErr, schemaJSON), nil
+}
+
+// parametersDefinition returns the parameters of a function as a schema
+// definition. It reports false if the parameters are not a JSON schema.
+func parametersDefinition(parameters any) (jsonschema.Definition, bool) {
+	switch p := parameters.(type) {
+	case nil:
+		return jsonschema.Definition{}, false
+	case jsonschema.Definition:
+		return p, true
+	case *jsonschema.Definition:
+		if p == nil {
+			return jsonschema.Definition{}, false
+		}
+		return *p, true
+	}
+
+	data, err := json.Marshal(parameters)
+	if err != nil {
+		return jsonschema.Definition{}, false
+	}
+	var def jsonschema.Definition
+	if err := json.Unmarshal(data, &def); err != nil || def.Type == "" {
+		return jsonschema.Definition{}, false
+	}
+	return def, true
+}
@@ -0,0 +1,197 @@
+package agents_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/agents"
+	"github.com/sayerxofficial/langchaingo/callbacks"
+	"github.com/sayerxofficial/langchaingo/chains"
+	"github.com/sayerxofficial/langchaingo/jsonschema"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/memory"
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/tools"
+
+	"github.com/stretchr/testify/require"
+)
+
+type argumentsHandler struct {
+	callbacks.SimpleHandler
+
+	mu    sync.Mutex
+	stats []agents.ToolArgumentStats
+}
+
+func (h *argumentsHandler) HandleToolArguments(_ context.Context, stats agents.ToolArgumentStats) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stats = append(h.stats, stats)
+}
+
+func newWeatherTool(t *testing.T) tools.Tool {
+	t.Helper()
+
+	type weatherInput struct {
+		City string `json:"city"`
+	}
+	weather, err := tools.NewTyped("weather", "Returns the weather in a city.",
+		func(_ context.Context, in weatherInput) (string, error) {
+			return "sunny in " + in.City, nil
+		})
+	require.NoError(t, err)
+	return weather
+}
+
+func TestExecutorToolArgumentCorrection(t *testing.T) {
+	t.Parallel()
+
+	llm := &scriptedLLM{turns: []*llms.ContentChoice{
+		{ToolCalls: []llms.ToolCall{toolCall("call_1", "weather", `{"town":"Paris"}`)}},
+		{ToolCalls: []llms.ToolCall{toolCall("call_2", "weather", `{"city":`)}},
+		{ToolCalls: []llms.ToolCall{toolCall("call_3", "weather", `{"city":"Paris"}`)}},
+		{Content: "It is sunny in Paris."},
+	}}
+	handler := &argumentsHandler{}
+	executor := agents.NewExecutor(
+		agents.NewToolCallingAgent(llm, []tools.Tool{newWeatherTool(t)}),
+		agents.WithCallbacksHandler(handler),
+		agents.WithReturnIntermediateSteps(),
+	)
+
+	result, err := chains.Call(t.Context(), executor, map[string]any{"input": "Weather in Paris?"})
+	require.NoError(t, err)
+	require.Equal(t, "It is sunny in Paris.", result["output"])
+
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 3)
+	require.Contains(t, steps[0].Observation, `The arguments for weather are invalid: $: missing required property "city"`)
+	require.Contains(t, steps[0].Observation, `"required":["city"]`)
+	require.Contains(t, steps[1].Observation, "The arguments for weather are invalid")
+	require.Equal(t, "sunny in Paris", steps[2].Observation)
+
+	require.Len(t, handler.stats, 3)
+	require.Error(t, handler.stats[0].Err)
+	require.Equal(t, 1, handler.stats[0].Failures)
+	require.Equal(t, 2, handler.stats[1].Failures)
+	require.Equal(t, agents.ToolArgumentStats{Tool: "weather", Corrected: true}, handler.stats[2])
+}
+
+func TestExecutorToolArgumentAttempts(t *testing.T) {
+	t.Parallel()
+
+	invalid := &llms.ContentChoice{ToolCalls: []llms.ToolCall{toolCall("call_1", "weather", `{}`)}}
+	llm := &scriptedLLM{turns: []*llms.ContentChoice{invalid, invalid}}
+	executor := agents.NewExecutor(
+		agents.NewToolCallingAgent(llm, []tools.Tool{newWeatherTool(t)}),
+		agents.WithToolArgumentAttempts(1),
+	)
+
+	_, err := chains.Call(t.Context(), executor, map[string]any{"input": "Weather?"})
+	require.ErrorIs(t, err, agents.ErrInvalidToolArguments)
+
+	var toolErr *agents.ToolError
+	require.ErrorAs(t, err, &toolErr)
+	require.Len(t, toolErr.Steps, 1)
+}
+
+// nilParametersTool is a function tool whose parameters are a nil schema.
+type nilParametersTool struct {
+	funcTool
+}
+
+func (t nilParametersTool) FunctionDefinition() llms.FunctionDefinition {
+	return llms.FunctionDefinition{Name: t.name, Parameters: (*jsonschema.Definition)(nil)}
+}
+
+func TestExecutorToolArgumentNilSchema(t *testing.T) {
+	t.Parallel()
+
+	echo := nilParametersTool{funcTool{name: "echo", call: func(_ context.Context, input string) (string, error) {
+		return input, nil
+	}}}
+	llm := &scriptedLLM{turns: []*llms.ContentChoice{
+		{ToolCalls: []llms.ToolCall{toolCall("call_1", "echo", `{"text":"hi"}`)}},
+		{Content: "done"},
+	}}
+	executor := agents.NewExecutor(
+		agents.NewToolCallingAgent(llm, []tools.Tool{echo}),
+		agents.WithReturnIntermediateSteps(),
+	)
+
+	// Arguments without a schema are not validated.
+	result, err := chains.Call(t.Context(), executor, map[string]any{"input": "Echo."})
+	require.NoError(t, err)
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 1)
+	require.Equal(t, `{"text":"hi"}`, steps[0].Observation)
+}
+
+func TestExecutorToolArgumentValidationOff(t *testing.T) {
+	t.Parallel()
+
+	llm := &scriptedLLM{turns: []*llms.ContentChoice{
+		{ToolCalls: []llms.ToolCall{toolCall("call_1", "weather", `{}`)}},
+	}}
+	executor := agents.NewExecutor(
+		agents.NewToolCallingAgent(llm, []tools.Tool{newWeatherTool(t)}),
+		agents.WithToolArgumentAttempts(-1),
+	)
+
+	// The tool gets the arguments as they are, and rejects them itself.
+	_, err := chains.Call(t.Context(), executor, map[string]any{"input": "Weather?"})
+	require.ErrorIs(t, err, tools.ErrInvalidArguments)
+}
+
+func TestExecutorToolArgumentAttemptsDefault(t *testing.T) {
+	t.Parallel()
+
+	// The failures are counted per tool regardless of the case of its name.
+	turns := make([]*llms.ContentChoice, 0, 4)
+	for _, name := range []string{"weather", "Weather", "WEATHER", "weather"} {
+		turns = append(turns, &llms.ContentChoice{ToolCalls: []llms.ToolCall{toolCall("call_1", name, `{}`)}})
+	}
+	llm := &scriptedLLM{turns: turns}
+	executor := &agents.Executor{
+		Agent:         agents.NewToolCallingAgent(llm, []tools.Tool{newWeatherTool(t)}),
+		Memory:        memory.NewSimple(),
+		MaxIterations: 5,
+	}
+
+	_, err := chains.Call(t.Context(), executor, map[string]any{"input": "Weather?"})
+	require.ErrorIs(t, err, agents.ErrInvalidToolArguments)
+
+	var toolErr *agents.ToolError
+	require.ErrorAs(t, err, &toolErr)
+	require.Len(t, toolErr.Steps, 3)
+}
+
+func TestMRKLTypedStringTool(t *testing.T) {
+	t.Parallel()
+
+	search, err := tools.NewTyped("search", "Searches the web.", func(_ context.Context, query string) (string, error) {
+		return "results for " + query, nil
+	})
+	require.NoError(t, err)
+
+	llm := &scriptedLLM{turns: textTurns(
+		"Thought: I should search.\nAction: search\nAction Input: go generics",
+		"Thought: I now know the final answer\nFinal Answer: Go has generics.",
+	)}
+	executor, err := agents.Initialize(llm, []tools.Tool{search}, agents.ZeroShotReactDescription,
+		agents.WithReturnIntermediateSteps())
+	require.NoError(t, err)
+
+	// The agent gives the tool a raw string, which is not validated as JSON.
+	result, err := chains.Call(t.Context(), executor, map[string]any{"input": "Does Go have generics?"})
+	require.NoError(t, err)
+	require.Contains(t, result["output"], "Go has generics.")
+
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 1)
+	require.Equal(t, "results for go generics", steps[0].Observation)
+}
@@ -0,0 +1,237 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/schema"
+)
+
+// BudgetLimit names the limit of a Budget that was exceeded.
+type BudgetLimit string
+
+const (
+	// BudgetLimitDuration is the limit on the wall-clock time of a run.
+	BudgetLimitDuration BudgetLimit = "duration"
+	// BudgetLimitTokens is the limit on the tokens used by a run.
+	BudgetLimitTokens BudgetLimit = "tokens"
+	// BudgetLimitCost is the limit on the estimated cost of a run.
+	BudgetLimitCost BudgetLimit = "cost"
+	// BudgetLimitToolCalls is the limit on the calls to a tool.
+	BudgetLimitToolCalls BudgetLimit = "tool calls"
+)
+
+// Budget limits the resources an executor run can use, in addition to
+// MaxIterations. The zero value of a field means no limit. Limits are checked
+// before every iteration and before every tool call. A call that is already
+// running is only interrupted by MaxDuration, which is the deadline of the
+// context of the run.
+//
+// Tokens are counted from the usage the model reports. The agents of this
+// package report it, as do chains calling the model through
+// llms.GenerateFromSinglePrompt; other agents can report it with
+// RecordUsage.
+type Budget struct {
+	// MaxDuration limits the wall-clock time of a run. Model and tool calls
+	// still running when it is reached are canceled.
+	MaxDuration time.Duration
+	// MaxTokens limits the input and output tokens used by a run.
+	MaxTokens int
+	// MaxCost limits the estimated cost of a run, computed by Cost.
+	MaxCost float64
+	// Cost estimates the cost of the tokens used by one model call. See
+	// TokenCost.
+	Cost func(usage llms.Usage) float64
+	// MaxToolCalls limits the number of calls to each tool, by tool name.
+	MaxToolCalls map[string]int
+	// FinalAnswer makes the executor ask the agent for a final answer from
+	// what it has gathered so far once the budget is exceeded. The run still
+	// fails with ErrBudgetExceeded if the agent does not give one.
+	FinalAnswer bool
+}
+
+// TokenCost returns a Budget.Cost function for a model priced per million
+// input and output tokens.
+func TokenCost(inputPerMillion, outputPerMillion float64) func(llms.Usage) float64 {
+	return func(usage llms.Usage) float64 {
+		return (float64(usage.InputTokens)*inputPerMillion + float64(usage.OutputTokens)*outputPerMillion) / 1e6
+	}
+}
+
+// BudgetExceededError is returned by the executor when a run exceeds its
+// budget. It holds the intermediate steps taken before the budget was
+// exceeded.
+type BudgetExceededError struct {
+	// Limit is the limit that was exceeded.
+	Limit BudgetLimit
+	// Tool is the tool whose calls exceeded BudgetLimitToolCalls.
+	Tool string
+	// Steps are the intermediate steps taken before the budget was exceeded.
+	Steps []schema.AgentStep
+}
+
+func (e *BudgetExceededError) Error() string {
+	if e.Tool != "" {
+		return fmt.Sprintf("%s: %s of %s", ErrBudgetExceeded, e.Limit, e.Tool)
+	}
+	return fmt.Sprintf("%s: %s", ErrBudgetExceeded, e.Limit)
+}
+
+func (e *BudgetExceededError) Unwrap() error {
+	return ErrBudgetExceeded
+}
+
+// BudgetUsage is what a run has used of its budget. It is kept in the
+// checkpoints of the run, so that a resumed run goes on from it.
+type BudgetUsage struct {
+	// Duration is the wall-clock time the run has taken.
+	Duration time.Duration `json:"duration"`
+	// Tokens are the input and output tokens used by the run.
+	Tokens int `json:"tokens"`
+	// Cost is the estimated cost of the run.
+	Cost float64 `json:"cost"`
+}
+
+type budgetKey struct{}
+
+// budgetTracker keeps track of the resources used by a run.
+type budgetTracker struct {
+	budget  Budget
+	started time.Time
+
+	mu        sync.Mutex
+	tokens    int
+	cost      float64
+	toolCalls map[string]int
+}
+
+func newBudgetTracker(budget Budget, steps []schema.AgentStep, used BudgetUsage) *budgetTracker {
+	// A resumed run goes on from what it used before its checkpoint.
+	t := &budgetTracker{
+		budget:    budget,
+		started:   time.Now().Add(-used.Duration),
+		tokens:    used.Tokens,
+		cost:      used.Cost,
+		toolCalls: make(map[string]int),
+	}
+	// Its tool calls are counted from its steps.
+	for _, step := range steps {
+		if step.Action.Tool != "" {
+			t.toolCalls[step.Action.Tool]++
+		}
+	}
+	return t
+}
+
+// usage returns what the run has used so far.
+func (t *budgetTracker) usage() BudgetUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return BudgetUsage{Duration: time.Since(t.started), Tokens: t.tokens, Cost: t.cost}
+}
+
+// RecordUsage adds the tokens used by a model call to the budget of the run
+// of ctx, if the run has one. Agents that call a model should report the
+// usage of every response.
+func RecordUsage(ctx context.Context, usage *llms.Usage) {
+	t, ok := ctx.Value(budgetKey{}).(*budgetTracker)
+	if !ok || usage == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tokens += usage.TotalTokens()
+	if t.budget.Cost != nil {
+		t.cost += t.budget.Cost(*usage)
+	}
+}
+
+type usageRecordingKey struct{}
+
+// withUsageRecording makes the models called through
+// llms.GenerateFromSinglePrompt, as chains do, report their usage with
+// RecordUsage. The hook reads the budget from the context of each call, so it
+// is only set once for nested runs.
+func withUsageRecording(ctx context.Context) context.Context {
+	if ctx.Value(usageRecordingKey{}) != nil {
+		return ctx
+	}
+	ctx = llms.WithUsageHook(ctx, RecordUsage)
+	return context.WithValue(ctx, usageRecordingKey{}, true)
+}
+
+// check returns an error if the run has exceeded its budget.
+func (t *budgetTracker) check() *BudgetExceededError {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.checkLocked()
+}
+
+func (t *budgetTracker) checkLocked() *BudgetExceededError {
+	switch {
+	case t.budget.MaxDuration > 0 && time.Since(t.started) >= t.budget.MaxDuration:
+		return &BudgetExceededError{Limit: BudgetLimitDuration}
+	case t.budget.MaxTokens > 0 && t.tokens >= t.budget.MaxTokens:
+		return &BudgetExceededError{Limit: BudgetLimitTokens}
+	case t.budget.MaxCost > 0 && t.cost >= t.budget.MaxCost:
+		return &BudgetExceededError{Limit: BudgetLimitCost}
+	}
+	return nil
+}
+
+// useTool checks the budget before a call to the tool and counts the call.
+func (t *budgetTracker) useTool(tool string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if budgetErr := t.checkLocked(); budgetErr != nil {
+		return budgetErr
+	}
+	if limit, ok := t.budget.MaxToolCalls[tool]; ok && t.toolCalls[tool] >= limit {
+		return &BudgetExceededError{Limit: BudgetLimitToolCalls, Tool: tool}
+	}
+	t.toolCalls[tool]++
+	return nil
+}
+
+// useTool checks the budget of the run of ctx before a call to the tool.
+func useTool(ctx context.Context, tool string) error {
+	t, ok := ctx.Value(budgetKey{}).(*budgetTracker)
+	if !ok {
+		return nil
+	}
+	return t.useTool(tool)
+}
+
+// finishOverBudget ends a run that exceeded its budget, asking the agent for
+// a final answer if the budget allows it. A run using the budget of the run
+// that started it leaves the final answer to that run.
+func (e *Executor) finishOverBudget(
+	ctx context.Context,
+	inputs map[string]string,
+	steps []schema.AgentStep,
+	budgetErr *BudgetExceededError,
+) (map[string]any, error) {
+	if e.Budget != nil && e.Budget.FinalAnswer {
+		steps = append(steps, schema.AgentStep{Observation: fmt.Sprintf(
+			"The budget of this task is exhausted (%s). Do not use any more tools: give your final answer now, "+
+				"based on what you have found so far.", budgetErr.Limit)})
+
+		_, finish, err := e.Agent.Plan(ctx, steps, inputs)
+		if err == nil && finish != nil {
+			if e.CallbacksHandler != nil {
+				e.CallbacksHandler.HandleAgentFinish(ctx, *finish)
+			}
+			return e.getReturn(finish, steps), nil
+		}
+	}
+
+	budgetErr.Steps = steps
+	return e.getReturn(
+		&schema.AgentFinish{ReturnValues: make(map[string]any)},
+		steps,
+	), budgetErr
+}
@@ -0,0 +1,158 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sayerxofficial/langchaingo/agents"
+	"github.com/sayerxofficial/langchaingo/chains"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/tools"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutorBudgetToolCalls(t *testing.T) {
+	t.Parallel()
+
+	a := &testAgent{
+		actions: []schema.AgentAction{{Tool: "search", ToolInput: "go"}},
+		tools: []tools.Tool{funcTool{name: "search", call: func(_ context.Context, input string) (string, error) {
+			return "found " + input, nil
+		}}},
+	}
+	executor := agents.NewExecutor(a, agents.WithBudget(agents.Budget{
+		MaxToolCalls: map[string]int{"search": 2},
+	}))
+
+	_, err := executor.Call(t.Context(), nil)
+	require.ErrorIs(t, err, agents.ErrBudgetExceeded)
+
+	var budgetErr *agents.BudgetExceededError
+	require.ErrorAs(t, err, &budgetErr)
+	require.Equal(t, agents.BudgetLimitToolCalls, budgetErr.Limit)
+	require.Equal(t, "search", budgetErr.Tool)
+	require.Len(t, budgetErr.Steps, 2)
+}
+
+func TestExecutorBudgetDuration(t *testing.T) {
+	t.Parallel()
+
+	a := &testAgent{actions: []schema.AgentAction{{Tool: "search"}}}
+	executor := agents.NewExecutor(a, agents.WithBudget(agents.Budget{MaxDuration: time.Nanosecond}))
+
+	_, err := executor.Call(t.Context(), nil)
+	var budgetErr *agents.BudgetExceededError
+	require.ErrorAs(t, err, &budgetErr)
+	require.Equal(t, agents.BudgetLimitDuration, budgetErr.Limit)
+	require.Empty(t, budgetErr.Steps)
+	require.Zero(t, a.numPlanCalls)
+}
+
+func TestExecutorBudgetTokensWithFinalAnswer(t *testing.T) {
+	t.Parallel()
+
+	search := funcTool{name: "search", call: func(_ context.Context, input string) (string, error) {
+		return "found " + input, nil
+	}}
+	llm := &scriptedLLM{
+		usage: &llms.Usage{InputTokens: 80, OutputTokens: 20},
+		turns: []*llms.ContentChoice{
+			{ToolCalls: []llms.ToolCall{toolCall("call_1", "search", `{"__arg1":"a"}`)}},
+			{ToolCalls: []llms.ToolCall{toolCall("call_2", "search", `{"__arg1":"b"}`)}},
+			{Content: "Best answer from a and b."},
+		},
+	}
+	executor := agents.NewExecutor(
+		agents.NewToolCallingAgent(llm, []tools.Tool{search}),
+		agents.WithBudget(agents.Budget{MaxTokens: 150, FinalAnswer: true}),
+		agents.WithReturnIntermediateSteps(),
+	)
+
+	result, err := executor.Call(t.Context(), map[string]any{"input": "research"})
+	require.NoError(t, err)
+	require.Equal(t, "Best answer from a and b.", result["output"])
+
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	// The second search is not run: the budget is exceeded once the model
+	// asks for it.
+	require.Len(t, steps, 2)
+	require.Equal(t, "found a", steps[0].Observation)
+	require.Contains(t, steps[1].Observation, "budget")
+
+	// The model is told about the budget in a human message.
+	last := llm.messages[2]
+	require.Equal(t, llms.ChatMessageTypeHuman, last[len(last)-1].Role)
+}
+
+func TestExecutorBudgetCost(t *testing.T) {
+	t.Parallel()
+
+	llm := &scriptedLLM{
+		usage: &llms.Usage{InputTokens: 1_000_000, OutputTokens: 1_000_000},
+		turns: []*llms.ContentChoice{
+			{ToolCalls: []llms.ToolCall{toolCall("call_1", "search", `{"__arg1":"a"}`)}},
+		},
+	}
+	search := funcTool{name: "search", call: func(context.Context, string) (string, error) {
+		return "found", nil
+	}}
+	executor := agents.NewExecutor(
+		agents.NewToolCallingAgent(llm, []tools.Tool{search}),
+		agents.WithBudget(agents.Budget{MaxCost: 10, Cost: agents.TokenCost(3, 15)}),
+	)
+
+	_, err := executor.Call(t.Context(), map[string]any{"input": "research"})
+	var budgetErr *agents.BudgetExceededError
+	require.ErrorAs(t, err, &budgetErr)
+	require.Equal(t, agents.BudgetLimitCost, budgetErr.Limit)
+	require.Empty(t, budgetErr.Steps)
+}
+
+func TestExecutorBudgetTokensMRKL(t *testing.T) {
+	t.Parallel()
+
+	search := funcTool{name: "search", call: func(_ context.Context, input string) (string, error) {
+		return "found " + input, nil
+	}}
+	llm := &scriptedLLM{
+		usage: &llms.Usage{InputTokens: 80, OutputTokens: 20},
+		turns: textTurns(
+			"Thought: I should search.\nAction: search\nAction Input: a",
+			"Thought: I should search again.\nAction: search\nAction Input: b",
+		),
+	}
+	a := agents.NewOneShotAgent(llm, []tools.Tool{search})
+	executor := agents.NewExecutor(a, agents.WithBudget(agents.Budget{MaxTokens: 150}))
+
+	// The chain of the agent calls the model itself, not a wrapper.
+	require.Same(t, llm, a.Chain.(*chains.LLMChain).LLM)
+
+	_, err := executor.Call(t.Context(), map[string]any{"input": "research"})
+	var budgetErr *agents.BudgetExceededError
+	require.ErrorAs(t, err, &budgetErr)
+	require.Equal(t, agents.BudgetLimitTokens, budgetErr.Limit)
+	require.Len(t, budgetErr.Steps, 1)
+}
+
+func TestExecutorBudgetDurationCancelsTool(t *testing.T) {
+	t.Parallel()
+
+	a := &testAgent{
+		actions: []schema.AgentAction{{Tool: "wait"}},
+		tools: []tools.Tool{funcTool{name: "wait", call: func(ctx context.Context, _ string) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}}},
+	}
+	executor := agents.NewExecutor(a, agents.WithBudget(agents.Budget{MaxDuration: 50 * time.Millisecond}))
+
+	_, err := executor.Call(t.Context(), nil)
+	var budgetErr *agents.BudgetExceededError
+	require.ErrorAs(t, err, &budgetErr)
+	require.Equal(t, agents.BudgetLimitDuration, budgetErr.Limit)
+	require.Equal(t, 1, a.numPlanCalls)
+}
@@ -0,0 +1,108 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/sayerxofficial/langchaingo/schema"
+)
+
+// ErrCheckpointNotFound is returned by a CheckpointStore when there is no
+// checkpoint for a run.
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// Checkpoint is the state of an agent run after an iteration of the executor.
+type Checkpoint struct {
+	// RunID identifies the run.
+	RunID string `json:"run_id"`
+	// Inputs are the inputs the run was started with.
+	Inputs map[string]string `json:"inputs"`
+	// Steps are the intermediate steps taken so far.
+	Steps []schema.AgentStep `json:"steps"`
+	// Iteration is the number of iterations completed.
+	Iteration int `json:"iteration"`
+	// Usage is what the run has used of the Budget of the executor, if it
+	// has one.
+	Usage BudgetUsage `json:"usage"`
+}
+
+// CheckpointStore persists the checkpoints of agent runs. Only the latest
+// checkpoint of a run is kept.
+type CheckpointStore interface {
+	// SaveCheckpoint stores the checkpoint, replacing the previous one of the
+	// same run.
+	SaveCheckpoint(ctx context.Context, checkpoint Checkpoint) error
+	// LoadCheckpoint returns the checkpoint of a run, or ErrCheckpointNotFound.
+	LoadCheckpoint(ctx context.Context, runID string) (Checkpoint, error)
+	// DeleteCheckpoint removes the checkpoint of a run. Deleting a missing
+	// checkpoint is not an error.
+	DeleteCheckpoint(ctx context.Context, runID string) error
+}
+
+type runIDKey struct{}
+
+// WithRunID returns a context that makes the executor checkpoint its run
+// under the given ID. Runs without an ID are not checkpointed.
+func WithRunID(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runIDKey{}, runID)
+}
+
+// RunIDFromContext returns the run ID set with WithRunID.
+func RunIDFromContext(ctx context.Context) (string, bool) {
+	runID, ok := ctx.Value(runIDKey{}).(string)
+	return runID, ok && runID != ""
+}
+
+// Resume continues the run with the given ID from its last checkpoint. The
+// iterations of the run before the checkpoint count towards MaxIterations,
+// and what it used of its Budget counts towards the limits of the budget.
+// The checkpoint is removed once the run finishes.
+func (e *Executor) Resume(ctx context.Context, runID string) (map[string]any, error) {
+	if e.CheckpointStore == nil {
+		return nil, ErrNoCheckpointStore
+	}
+	checkpoint, err := e.CheckpointStore.LoadCheckpoint(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.run(WithRunID(ctx, runID), checkpoint.Inputs, checkpoint.Steps, checkpoint.Iteration, checkpoint.Usage)
+}
+
+// saveCheckpoint stores the state of the run after the given number of
+// iterations and its usage of the budget, if the run is checkpointed.
+func (e *Executor) saveCheckpoint(
+	ctx context.Context,
+	inputs map[string]string,
+	steps []schema.AgentStep,
+	iteration int,
+	usage BudgetUsage,
+) error {
+	runID, ok := RunIDFromContext(ctx)
+	if e.CheckpointStore == nil || !ok {
+		return nil
+	}
+
+	return e.CheckpointStore.SaveCheckpoint(ctx, Checkpoint{
+		RunID:     runID,
+		Inputs:    inputs,
+		Steps:     steps,
+		Iteration: iteration,
+		Usage:     usage,
+	})
+}
+
+// deleteCheckpoint removes the checkpoint of a finished run. The run has
+// succeeded by then, so a failure is only logged: the stale checkpoint can
+// still be resumed, which finishes the run again.
+func (e *Executor) deleteCheckpoint(ctx context.Context) {
+	runID, ok := RunIDFromContext(ctx)
+	if e.CheckpointStore == nil || !ok {
+		return
+	}
+
+	if err := e.CheckpointStore.DeleteCheckpoint(ctx, runID); err != nil {
+		log.Printf("agents: deleting the checkpoint of run %s: %v", runID, err)
+	}
+}
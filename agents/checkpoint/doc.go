@@ -0,0 +1,8 @@
+// Package checkpoint groups the implementations of agents.CheckpointStore,
+// which let an agents.Executor resume a run from its last iteration:
+//
+//   - inmemory keeps checkpoints in memory, for a single process.
+//   - filesystem writes every checkpoint to a JSON file in a directory.
+//   - sqlite3 stores checkpoints in a SQLite table that several processes
+//     can share.
+package checkpoint
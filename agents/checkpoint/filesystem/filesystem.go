@@ -0,0 +1,87 @@
+// Package filesystem provides an agents.CheckpointStore that writes every
+// checkpoint to a JSON file in a directory.
+package filesystem
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/sayerxofficial/langchaingo/agents"
+)
+
+// Store is an agents.CheckpointStore that keeps one JSON file per run. Files
+// are replaced atomically, so a crash never leaves a partial checkpoint.
+type Store struct {
+	dir string
+}
+
+var _ agents.CheckpointStore = (*Store)(nil)
+
+// New creates a checkpoint store in dir, creating the directory if needed.
+func New(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir}, nil
+}
+
+// SaveCheckpoint writes the checkpoint to the file of its run.
+func (s *Store) SaveCheckpoint(_ context.Context, checkpoint agents.Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(checkpoint.RunID))
+}
+
+// LoadCheckpoint reads the checkpoint of a run.
+func (s *Store) LoadCheckpoint(_ context.Context, runID string) (agents.Checkpoint, error) {
+	data, err := os.ReadFile(s.path(runID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return agents.Checkpoint{}, agents.ErrCheckpointNotFound
+	}
+	if err != nil {
+		return agents.Checkpoint{}, err
+	}
+
+	var checkpoint agents.Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return agents.Checkpoint{}, err
+	}
+	return checkpoint, nil
+}
+
+// DeleteCheckpoint removes the file of a run.
+func (s *Store) DeleteCheckpoint(_ context.Context, runID string) error {
+	err := os.Remove(s.path(runID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path returns the file of a run. Run IDs are hashed so that any string can
+// be used as an ID.
+func (s *Store) path(runID string) string {
+	h := sha256.Sum256([]byte(runID))
+	return filepath.Join(s.dir, hex.EncodeToString(h[:])+".json")
+}
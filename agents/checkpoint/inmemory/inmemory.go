@@ -0,0 +1,61 @@
+// Package inmemory provides an agents.CheckpointStore that keeps checkpoints
+// in memory.
+package inmemory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/sayerxofficial/langchaingo/agents"
+)
+
+// Store is an in-memory agents.CheckpointStore. It is safe for concurrent
+// use.
+type Store struct {
+	mu          sync.Mutex
+	checkpoints map[string]agents.Checkpoint
+}
+
+var _ agents.CheckpointStore = (*Store)(nil)
+
+// New creates an empty in-memory checkpoint store.
+func New() *Store {
+	return &Store{checkpoints: make(map[string]agents.Checkpoint)}
+}
+
+// SaveCheckpoint stores a copy of the checkpoint.
+func (s *Store) SaveCheckpoint(_ context.Context, checkpoint agents.Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[checkpoint.RunID] = clone(checkpoint)
+	return nil
+}
+
+// LoadCheckpoint returns a copy of the checkpoint of a run.
+func (s *Store) LoadCheckpoint(_ context.Context, runID string) (agents.Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkpoint, ok := s.checkpoints[runID]
+	if !ok {
+		return agents.Checkpoint{}, agents.ErrCheckpointNotFound
+	}
+	return clone(checkpoint), nil
+}
+
+// DeleteCheckpoint removes the checkpoint of a run.
+func (s *Store) DeleteCheckpoint(_ context.Context, runID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.checkpoints, runID)
+	return nil
+}
+
+// clone copies the checkpoint so that the executor and the store never share
+// the inputs map or the steps slice.
+func clone(checkpoint agents.Checkpoint) agents.Checkpoint {
+	checkpoint.Inputs = maps.Clone(checkpoint.Inputs)
+	checkpoint.Steps = slices.Clone(checkpoint.Steps)
+	return checkpoint
+}
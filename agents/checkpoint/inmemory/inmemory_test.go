@@ -0,0 +1,40 @@
+package inmemory
+
+import (
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/agents"
+	"github.com/sayerxofficial/langchaingo/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	s := New()
+	_, err := s.LoadCheckpoint(ctx, "run")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+
+	steps := []schema.AgentStep{{Action: schema.AgentAction{Tool: "search"}, Observation: "found"}}
+	checkpoint := agents.Checkpoint{RunID: "run", Inputs: map[string]string{"input": "q"}, Steps: steps, Iteration: 1}
+	require.NoError(t, s.SaveCheckpoint(ctx, checkpoint))
+
+	// The store keeps its own copy.
+	steps[0].Observation = "changed"
+	got, err := s.LoadCheckpoint(ctx, "run")
+	require.NoError(t, err)
+	require.Equal(t, "found", got.Steps[0].Observation)
+
+	checkpoint.Iteration = 2
+	require.NoError(t, s.SaveCheckpoint(ctx, checkpoint))
+	got, err = s.LoadCheckpoint(ctx, "run")
+	require.NoError(t, err)
+	require.Equal(t, 2, got.Iteration)
+
+	require.NoError(t, s.DeleteCheckpoint(ctx, "run"))
+	require.NoError(t, s.DeleteCheckpoint(ctx, "run"))
+	_, err = s.LoadCheckpoint(ctx, "run")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+}
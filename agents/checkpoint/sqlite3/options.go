@@ -0,0 +1,66 @@
+package sqlite3
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// DefaultTableName is the name of the table checkpoints are stored in.
+const DefaultTableName = "langchaingo_agent_checkpoints"
+
+// ErrInvalidOption is returned by New when an option has an invalid value.
+var ErrInvalidOption = errors.New("sqlite3: invalid option")
+
+// Option is a functional argument that configures the Options.
+type Option func(*Options) error
+
+// Options is a set of options for the SQLite checkpoint store.
+type Options struct {
+	// DB is the database connection. If nil, a connection to DBAddress is
+	// opened and closed by Close.
+	DB *sql.DB
+	// DBAddress is the file path of the database. Defaults to ":memory:".
+	DBAddress string
+	// TableName is the name of the table checkpoints are stored in.
+	TableName string
+}
+
+// WithDB specifies an existing database connection to use.
+func WithDB(db *sql.DB) Option {
+	return func(o *Options) error {
+		o.DB = db
+		return nil
+	}
+}
+
+// WithDBAddress specifies the file path of the database to open.
+func WithDBAddress(addr string) Option {
+	return func(o *Options) error {
+		o.DBAddress = addr
+		return nil
+	}
+}
+
+// WithTableName specifies the name of the table checkpoints are stored in.
+func WithTableName(name string) Option {
+	return func(o *Options) error {
+		if name == "" {
+			return ErrInvalidOption
+		}
+		o.TableName = name
+		return nil
+	}
+}
+
+func applyOptions(opts ...Option) (*Options, error) {
+	o := &Options{
+		DBAddress: ":memory:",
+		TableName: DefaultTableName,
+	}
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
@@ -0,0 +1,128 @@
+// Package sqlite3 provides an agents.CheckpointStore that stores checkpoints
+// in a SQLite database. The database can be shared by several processes, so a
+// run can be resumed by a different process than the one that started it.
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sayerxofficial/langchaingo/agents"
+
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver.
+)
+
+const tableSchema = `CREATE TABLE IF NOT EXISTS %s (
+	run_id TEXT PRIMARY KEY,
+	checkpoint TEXT NOT NULL,
+	updated INTEGER NOT NULL
+);`
+
+// busyTimeout is how long, in milliseconds, a connection waits for a lock
+// held by another connection or process.
+const busyTimeout = 5000
+
+// Store is an agents.CheckpointStore backed by a SQLite table.
+type Store struct {
+	Options Options
+	db      *sql.DB
+	ownsDB  bool
+}
+
+var _ agents.CheckpointStore = (*Store)(nil)
+
+// New creates a new SQLite checkpoint store and creates its table if needed.
+func New(ctx context.Context, opts ...Option) (*Store, error) {
+	options, err := applyOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		Options: *options,
+		db:      options.DB,
+	}
+	if s.db == nil {
+		if s.db, err = open(options.DBAddress); err != nil {
+			return nil, err
+		}
+		s.ownsDB = true
+	}
+
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf(tableSchema, options.TableName)); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func open(addr string) (*sql.DB, error) {
+	if addr == ":memory:" {
+		db, err := sql.Open("sqlite3", addr)
+		if err != nil {
+			return nil, err
+		}
+		// Every connection to ":memory:" opens a different database.
+		db.SetMaxOpenConns(1)
+		return db, nil
+	}
+	sep := "?"
+	if strings.Contains(addr, "?") {
+		sep = "&"
+	}
+	return sql.Open("sqlite3", fmt.Sprintf("%s%s_busy_timeout=%d&_journal_mode=WAL", addr, sep, busyTimeout))
+}
+
+// Close closes the database connection if it was opened by New.
+func (s *Store) Close() error {
+	if !s.ownsDB {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// SaveCheckpoint stores the checkpoint, replacing the previous one of the
+// same run.
+func (s *Store) SaveCheckpoint(ctx context.Context, checkpoint agents.Checkpoint) error {
+	encoded, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (run_id, checkpoint, updated) VALUES (?, ?, ?)
+ON CONFLICT (run_id) DO UPDATE SET checkpoint = excluded.checkpoint, updated = excluded.updated`,
+		s.Options.TableName)
+	_, err = s.db.ExecContext(ctx, query, checkpoint.RunID, string(encoded), time.Now().UnixNano())
+	return err
+}
+
+// LoadCheckpoint returns the checkpoint of a run.
+func (s *Store) LoadCheckpoint(ctx context.Context, runID string) (agents.Checkpoint, error) {
+	var encoded string
+	query := fmt.Sprintf("SELECT checkpoint FROM %s WHERE run_id = ?", s.Options.TableName)
+	err := s.db.QueryRowContext(ctx, query, runID).Scan(&encoded)
+	if errors.Is(err, sql.ErrNoRows) {
+		return agents.Checkpoint{}, agents.ErrCheckpointNotFound
+	}
+	if err != nil {
+		return agents.Checkpoint{}, err
+	}
+
+	var checkpoint agents.Checkpoint
+	if err := json.Unmarshal([]byte(encoded), &checkpoint); err != nil {
+		return agents.Checkpoint{}, err
+	}
+	return checkpoint, nil
+}
+
+// DeleteCheckpoint removes the checkpoint of a run.
+func (s *Store) DeleteCheckpoint(ctx context.Context, runID string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE run_id = ?", s.Options.TableName)
+	_, err := s.db.ExecContext(ctx, query, runID)
+	return err
+}
@@ -0,0 +1,51 @@
+package sqlite3
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/agents"
+	"github.com/sayerxofficial/langchaingo/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	s, err := New(ctx, WithDBAddress(filepath.Join(t.TempDir(), "checkpoints.db")))
+	require.NoError(t, err)
+
+	_, err = s.LoadCheckpoint(ctx, "run/1")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+
+	checkpoint := agents.Checkpoint{
+		RunID:     "run/1",
+		Inputs:    map[string]string{"input": "q"},
+		Steps:     []schema.AgentStep{{Action: schema.AgentAction{Tool: "search", ToolInput: "q"}, Observation: "found"}},
+		Iteration: 1,
+	}
+	require.NoError(t, s.SaveCheckpoint(ctx, checkpoint))
+	got, err := s.LoadCheckpoint(ctx, "run/1")
+	require.NoError(t, err)
+	require.Equal(t, checkpoint, got)
+
+	checkpoint.Iteration = 2
+	require.NoError(t, s.SaveCheckpoint(ctx, checkpoint))
+	got, err = s.LoadCheckpoint(ctx, "run/1")
+	require.NoError(t, err)
+	require.Equal(t, 2, got.Iteration)
+
+	require.NoError(t, s.DeleteCheckpoint(ctx, "run/1"))
+	require.NoError(t, s.DeleteCheckpoint(ctx, "run/1"))
+	_, err = s.LoadCheckpoint(ctx, "run/1")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+}
+
+func TestNewInvalidTableName(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(t.Context(), WithTableName(""))
+	require.ErrorIs(t, err, ErrInvalidOption)
+}
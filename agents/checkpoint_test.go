@@ -0,0 +1,212 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/agents"
+	"github.com/sayerxofficial/langchaingo/agents/checkpoint/inmemory"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/tools"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errPlan = errors.New("plan failed")
+
+// countingAgent calls the count tool until it has done so finishAfter times.
+// The plan fails on the call given by failOn.
+type countingAgent struct {
+	finishAfter int
+	failOn      int
+	planCalls   int
+}
+
+func (a *countingAgent) Plan(
+	_ context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	a.planCalls++
+	if a.planCalls == a.failOn {
+		return nil, nil, errPlan
+	}
+	if len(intermediateSteps) == a.finishAfter {
+		return nil, &schema.AgentFinish{ReturnValues: map[string]any{
+			"output": fmt.Sprintf("%s: %d", inputs["input"], len(intermediateSteps)),
+		}}, nil
+	}
+	return []schema.AgentAction{{Tool: "count", ToolInput: fmt.Sprint(len(intermediateSteps))}}, nil, nil
+}
+
+func (a *countingAgent) GetInputKeys() []string  { return []string{"input"} }
+func (a *countingAgent) GetOutputKeys() []string { return []string{"output"} }
+
+func (a *countingAgent) GetTools() []tools.Tool {
+	return []tools.Tool{funcTool{name: "count", call: func(_ context.Context, input string) (string, error) {
+		return input, nil
+	}}}
+}
+
+func TestExecutorResume(t *testing.T) {
+	t.Parallel()
+
+	store := inmemory.New()
+	ctx := agents.WithRunID(t.Context(), "run-1")
+	a := &countingAgent{finishAfter: 3, failOn: 3}
+	executor := agents.NewExecutor(a, agents.WithCheckpointStore(store))
+
+	_, err := executor.Call(ctx, map[string]any{"input": "counted"})
+	require.ErrorIs(t, err, errPlan)
+
+	checkpoint, err := store.LoadCheckpoint(ctx, "run-1")
+	require.NoError(t, err)
+	require.Equal(t, 2, checkpoint.Iteration)
+	require.Equal(t, map[string]string{"input": "counted"}, checkpoint.Inputs)
+	require.Len(t, checkpoint.Steps, 2)
+
+	// A new executor, as in another process, picks the run up.
+	resumed := agents.NewExecutor(&countingAgent{finishAfter: 3}, agents.WithCheckpointStore(store))
+	result, err := resumed.Resume(t.Context(), "run-1")
+	require.NoError(t, err)
+	require.Equal(t, "counted: 3", result["output"])
+
+	_, err = store.LoadCheckpoint(ctx, "run-1")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+}
+
+func TestExecutorResumeCountsIterations(t *testing.T) {
+	t.Parallel()
+
+	store := inmemory.New()
+	ctx := agents.WithRunID(t.Context(), "run-1")
+	executor := agents.NewExecutor(
+		&countingAgent{finishAfter: 5},
+		agents.WithCheckpointStore(store),
+		agents.WithMaxIterations(4),
+	)
+
+	_, err := executor.Call(ctx, map[string]any{"input": "counted"})
+	require.ErrorIs(t, err, agents.ErrNotFinished)
+
+	executor.MaxIterations = 6
+	result, err := executor.Resume(t.Context(), "run-1")
+	require.NoError(t, err)
+	require.Equal(t, "counted: 5", result["output"])
+}
+
+func TestExecutorResumeErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := agents.NewExecutor(&countingAgent{}).Resume(t.Context(), "run-1")
+	require.ErrorIs(t, err, agents.ErrNoCheckpointStore)
+
+	executor := agents.NewExecutor(&countingAgent{}, agents.WithCheckpointStore(inmemory.New()))
+	_, err = executor.Resume(t.Context(), "missing")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+}
+
+// recordingStore records the checkpoints saved and deleted, and fails to
+// delete them if deleteErr is set.
+type recordingStore struct {
+	agents.CheckpointStore
+	saved     []agents.Checkpoint
+	deleted   []string
+	deleteErr error
+}
+
+func (s *recordingStore) SaveCheckpoint(ctx context.Context, checkpoint agents.Checkpoint) error {
+	s.saved = append(s.saved, checkpoint)
+	return s.CheckpointStore.SaveCheckpoint(ctx, checkpoint)
+}
+
+func (s *recordingStore) DeleteCheckpoint(ctx context.Context, runID string) error {
+	s.deleted = append(s.deleted, runID)
+	if s.deleteErr != nil {
+		return s.deleteErr
+	}
+	return s.CheckpointStore.DeleteCheckpoint(ctx, runID)
+}
+
+func TestExecutorDeleteCheckpointError(t *testing.T) {
+	t.Parallel()
+
+	store := &recordingStore{CheckpointStore: inmemory.New(), deleteErr: errors.New("store unavailable")}
+	executor := agents.NewExecutor(&countingAgent{finishAfter: 1}, agents.WithCheckpointStore(store))
+
+	// The run succeeded, so the failure to clean up does not fail it.
+	result, err := executor.Call(agents.WithRunID(t.Context(), "run-1"), map[string]any{"input": "counted"})
+	require.NoError(t, err)
+	require.Equal(t, "counted: 1", result["output"])
+	require.Equal(t, []string{"run-1"}, store.deleted)
+}
+
+func TestExecutorNestedRunCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	store := &recordingStore{CheckpointStore: inmemory.New()}
+	counter := agents.NewExecutor(&countingAgent{finishAfter: 2}, agents.WithCheckpointStore(store))
+	countTool := agents.NewAgentTool("counter", "Counts.", counter)
+
+	llm := &scriptedLLM{turns: []*llms.ContentChoice{
+		{ToolCalls: []llms.ToolCall{toolCall("call_1", "counter", `{"__arg1":"counted"}`)}},
+		{Content: "Done."},
+	}}
+	executor := agents.NewExecutor(
+		agents.NewToolCallingAgent(llm, []tools.Tool{countTool}),
+		agents.WithCheckpointStore(store),
+	)
+
+	_, err := executor.Call(agents.WithRunID(t.Context(), "run-1"), map[string]any{"input": "Count."})
+	require.NoError(t, err)
+
+	// Only the outer run is checkpointed: the nested run neither replaced
+	// nor deleted its checkpoint.
+	require.Len(t, store.saved, 1)
+	require.Equal(t, map[string]string{"input": "Count."}, store.saved[0].Inputs)
+	require.Equal(t, []string{"run-1"}, store.deleted)
+}
+
+func TestExecutorResumeKeepsBudgetUsage(t *testing.T) {
+	t.Parallel()
+
+	store := inmemory.New()
+	search := funcTool{name: "search", call: func(_ context.Context, input string) (string, error) {
+		return "found " + input, nil
+	}}
+	newExecutor := func(turns ...*llms.ContentChoice) *agents.Executor {
+		llm := &scriptedLLM{usage: &llms.Usage{InputTokens: 10, OutputTokens: 5}, turns: turns}
+		return agents.NewExecutor(
+			agents.NewToolCallingAgent(llm, []tools.Tool{search}),
+			agents.WithCheckpointStore(store),
+			agents.WithBudget(agents.Budget{MaxTokens: 20}),
+		)
+	}
+
+	// The model fails on its second call.
+	executor := newExecutor(&llms.ContentChoice{
+		ToolCalls: []llms.ToolCall{toolCall("call_1", "search", `{"__arg1":"a"}`)},
+	})
+	_, err := executor.Call(agents.WithRunID(t.Context(), "run-1"), map[string]any{"input": "research"})
+	require.Error(t, err)
+
+	checkpoint, err := store.LoadCheckpoint(t.Context(), "run-1")
+	require.NoError(t, err)
+	require.Equal(t, 15, checkpoint.Usage.Tokens)
+	require.Positive(t, checkpoint.Usage.Duration)
+
+	// The tokens used before the checkpoint count: the budget is exceeded
+	// by the next call of the model, before its search runs.
+	resumed := newExecutor(
+		&llms.ContentChoice{ToolCalls: []llms.ToolCall{toolCall("call_2", "search", `{"__arg1":"b"}`)}},
+		&llms.ContentChoice{Content: "Found a and b."},
+	)
+	_, err = resumed.Resume(t.Context(), "run-1")
+	var budgetErr *agents.BudgetExceededError
+	require.ErrorAs(t, err, &budgetErr)
+	require.Equal(t, agents.BudgetLimitTokens, budgetErr.Limit)
+	require.Len(t, budgetErr.Steps, 1)
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/sayerxofficial/langchaingo/callbacks"
 	"github.com/sayerxofficial/langchaingo/chains"
 	"github.com/sayerxofficial/langchaingo/llms"
-	"github.com/sayerxofficial/langchaingo/llms/streaming"
 	"github.com/sayerxofficial/langchaingo/prompts"
 	"github.com/sayerxofficial/langchaingo/schema"
 	"github.com/sayerxofficial/langchaingo/tools"
@@ -72,14 +71,7 @@ func (a *ConversationalAgent) Plan(
 
 	fullInputs["agent_scratchpad"] = constructScratchPad(intermediateSteps)
 
-	var stream streaming.Callback
-
-	if a.CallbacksHandler != nil {
-		stream = func(ctx context.Context, chunk streaming.Chunk) error {
-			a.CallbacksHandler.HandleStreamingFunc(ctx, chunk)
-			return nil
-		}
-	}
+	stream := streamingFunc(ctx, a.CallbacksHandler)
 
 	output, err := chains.Predict(
 		ctx,
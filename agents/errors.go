@@ -1,6 +1,11 @@
 package agents
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sayerxofficial/langchaingo/schema"
+)
 
 var (
 	// ErrExecutorInputNotString is returned if an input to the executor call function is not a string.
@@ -12,6 +17,18 @@ var (
 	ErrNotFinished = errors.New("agent not finished before max iterations")
 	// ErrUnknownAgentType is returned if the type given to the initializer is invalid.
 	ErrUnknownAgentType = errors.New("unknown agent type")
+	// ErrBudgetExceeded is returned if a run exceeds the budget of the executor. The error is a
+	// *BudgetExceededError holding the steps taken.
+	ErrBudgetExceeded = errors.New("agent budget exceeded")
+	// ErrHandoffLoop is returned by an agent tool that refuses to call an agent that is already
+	// running. The error is a *HandoffLoopError.
+	ErrHandoffLoop = errors.New("agent handoff loop")
+	// ErrInvalidToolArguments is returned if the agent keeps calling a tool with arguments that do
+	// not match the parameters of the tool.
+	ErrInvalidToolArguments = errors.New("invalid tool arguments")
+	// ErrNoCheckpointStore is returned if a run is resumed by an executor without a checkpoint
+	// store.
+	ErrNoCheckpointStore = errors.New("executor has no checkpoint store")
 	// ErrInvalidOptions is returned if the options given to the initializer is invalid.
 	ErrInvalidOptions = errors.New("invalid options")
 
@@ -38,3 +55,71 @@ func NewParserErrorHandler(formatFunc func(string) string) *ParserErrorHandler {
 		Formatter: formatFunc,
 	}
 }
+
+// ToolErrorMode selects what the executor does when a tool call returns an
+// error.
+type ToolErrorMode int
+
+const (
+	// ToolErrorAbort stops the run and returns the error. It is the default.
+	ToolErrorAbort ToolErrorMode = iota
+	// ToolErrorObservation gives the error to the agent as the observation of
+	// the action, so the agent can try something else.
+	ToolErrorObservation
+	// ToolErrorRetry calls the tool again, up to MaxRetries times, and stops
+	// the run if the tool still fails.
+	ToolErrorRetry
+)
+
+// ToolErrorHandler is the struct used to handle errors returned by tools in
+// the executor. Without a ToolErrorHandler the first tool error stops the run.
+type ToolErrorHandler struct {
+	// Mode selects how tool errors are handled.
+	Mode ToolErrorMode
+	// MaxRetries is the number of times a failing tool is called again when
+	// Mode is ToolErrorRetry.
+	MaxRetries int
+	// The formatter function can be used to format the error when Mode is
+	// ToolErrorObservation. If nil the error will be given as an observation
+	// directly.
+	Formatter func(action schema.AgentAction, err error) string
+}
+
+// NewToolErrorObservationHandler creates a tool error handler that gives tool
+// errors to the agent as observations.
+func NewToolErrorObservationHandler(formatFunc func(schema.AgentAction, error) string) *ToolErrorHandler {
+	return &ToolErrorHandler{
+		Mode:      ToolErrorObservation,
+		Formatter: formatFunc,
+	}
+}
+
+// NewToolErrorRetryHandler creates a tool error handler that calls a failing
+// tool again up to maxRetries times before stopping the run.
+func NewToolErrorRetryHandler(maxRetries int) *ToolErrorHandler {
+	return &ToolErrorHandler{
+		Mode:       ToolErrorRetry,
+		MaxRetries: maxRetries,
+	}
+}
+
+// ToolError is returned by the executor when a tool error stops the run,
+// including errors of the approval handler and tool arguments that are still
+// invalid after the allowed corrections. It holds the intermediate steps
+// completed before the error.
+type ToolError struct {
+	// Action is the action whose tool failed.
+	Action schema.AgentAction
+	// Steps are the intermediate steps completed before the error.
+	Steps []schema.AgentStep
+	// Err is the error returned by the tool or by the approval handler.
+	Err error
+}
+
+func (e *ToolError) Error() string {
+	return fmt.Sprintf("tool %s: %v", e.Action.Tool, e.Err)
+}
+
+func (e *ToolError) Unwrap() error {
+	return e.Err
+}
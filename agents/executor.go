@@ -4,25 +4,41 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/sayerxofficial/langchaingo/callbacks"
 	"github.com/sayerxofficial/langchaingo/chains"
 	"github.com/sayerxofficial/langchaingo/schema"
 	"github.com/sayerxofficial/langchaingo/tools"
+
+	"golang.org/x/sync/errgroup"
 )
 
 const _intermediateStepsOutputKey = "intermediateSteps"
 
-// Executor is the chain responsible for running agents.
+// Executor is the chain responsible for running agents. Tools with a
+// ReturnDirect method that reports true, such as agent tools handing off, end
+// the run with their output.
 type Executor struct {
 	Agent            Agent
 	Memory           schema.Memory
 	CallbacksHandler callbacks.Handler
 	ErrorHandler     *ParserErrorHandler
+	ToolErrorHandler *ToolErrorHandler
+	ApprovalHandler  ApprovalHandler
+	CheckpointStore  CheckpointStore
+	Budget           *Budget
 
 	MaxIterations           int
 	ReturnIntermediateSteps bool
+	// MaxParallelTools is the number of actions from a single plan that can
+	// run at the same time. Values below 2 run the actions one after another.
+	MaxParallelTools int
+	// ToolArgumentAttempts is the number of invalid calls in a row to a tool
+	// that are sent back to the agent for correction. Zero means the default
+	// of 3, and a negative value turns the validation of tool arguments off.
+	ToolArgumentAttempts int
 }
 
 var (
@@ -44,6 +60,12 @@ func NewExecutor(agent Agent, opts ...Option) *Executor {
 		ReturnIntermediateSteps: options.returnIntermediateSteps,
 		CallbacksHandler:        options.callbacksHandler,
 		ErrorHandler:            options.errorHandler,
+		ToolErrorHandler:        options.toolErrorHandler,
+		ApprovalHandler:         options.approvalHandler,
+		CheckpointStore:         options.checkpointStore,
+		Budget:                  options.budget,
+		MaxParallelTools:        options.maxParallelTools,
+		ToolArgumentAttempts:    options.toolArgumentAttempts,
 	}
 }
 
@@ -52,14 +74,82 @@ func (e *Executor) Call(ctx context.Context, inputValues map[string]any, _ ...ch
 	if err != nil {
 		return nil, err
 	}
+
+	return e.run(ctx, inputs, make([]schema.AgentStep, 0), 0, BudgetUsage{})
+}
+
+// run runs the agent from the given intermediate steps, iteration and usage
+// of the budget until it finishes, fails or reaches MaxIterations.
+func (e *Executor) run(
+	ctx context.Context,
+	inputs map[string]string,
+	steps []schema.AgentStep,
+	start int,
+	used BudgetUsage,
+) (map[string]any, error) {
 	nameToTool := getNameToTool(e.Agent.GetTools())
 
-	steps := make([]schema.AgentStep, 0)
-	for i := 0; i < e.MaxIterations; i++ {
+	if e.ToolArgumentAttempts >= 0 && callsFunctions(e.Agent) {
+		ctx = context.WithValue(ctx, argumentsKey{}, &argumentTracker{failures: make(map[string]int)})
+	}
+
+	// A run without a budget of its own, such as a step of a plan, uses the
+	// budget of the run that started it, if any.
+	budget, _ := ctx.Value(budgetKey{}).(*budgetTracker)
+	// runCtx is canceled once the run exceeds Budget.MaxDuration, while ctx
+	// is kept to finish the run over budget.
+	runCtx := ctx
+	if e.Budget != nil {
+		budget = newBudgetTracker(*e.Budget, steps, used)
+		ctx = withUsageRecording(context.WithValue(ctx, budgetKey{}, budget))
+		runCtx = ctx
+		if e.Budget.MaxDuration > 0 {
+			var cancel context.CancelFunc
+			runCtx, cancel = context.WithDeadline(ctx, budget.started.Add(e.Budget.MaxDuration))
+			defer cancel()
+		}
+	}
+
+	for i := start; i < e.MaxIterations; i++ {
+		if budget != nil {
+			if budgetErr := budget.check(); budgetErr != nil {
+				return e.finishOverBudget(ctx, inputs, steps, budgetErr)
+			}
+		}
+
 		var finish map[string]any
-		steps, finish, err = e.doIteration(ctx, steps, nameToTool, inputs)
-		if finish != nil || err != nil {
-			return finish, err
+		var err error
+		stepCtx := withStep(runCtx, i)
+		previous := len(steps)
+		steps, finish, err = e.doIteration(stepCtx, steps, nameToTool, inputs)
+		if err != nil && ctx.Err() == nil && errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+			return e.finishOverBudget(ctx, inputs, steps, &BudgetExceededError{Limit: BudgetLimitDuration})
+		}
+		var budgetErr *BudgetExceededError
+		if errors.As(err, &budgetErr) {
+			return e.finishOverBudget(ctx, inputs, budgetErr.Steps, budgetErr)
+		}
+		var toolErr *ToolError
+		if errors.As(err, &toolErr) {
+			return e.getReturn(
+				&schema.AgentFinish{ReturnValues: make(map[string]any)},
+				toolErr.Steps,
+			), err
+		}
+		if err != nil {
+			return nil, err
+		}
+		emit(stepCtx, Event{Type: EventStepFinished, Steps: steps[previous:]})
+		if finish != nil {
+			e.deleteCheckpoint(ctx)
+			return finish, nil
+		}
+		var usage BudgetUsage
+		if e.Budget != nil {
+			usage = budget.usage()
+		}
+		if err := e.saveCheckpoint(ctx, inputs, steps, i+1, usage); err != nil {
+			return nil, err
 		}
 	}
 
@@ -106,43 +196,240 @@ func (e *Executor) doIteration( // nolint
 		return steps, e.getReturn(finish, steps), nil
 	}
 
-	for _, action := range actions {
-		steps, err = e.doAction(ctx, steps, nameToTool, action)
+	if e.MaxParallelTools > 1 && len(actions) > 1 {
+		newSteps, direct, err := e.doActionsParallel(ctx, nameToTool, actions)
+		steps = append(steps, newSteps...)
 		if err != nil {
-			return steps, nil, err
+			return steps, nil, withSteps(err, steps)
 		}
+		return steps, e.directReturn(ctx, steps, direct), nil
 	}
 
+	var direct *schema.AgentStep
+	for i, action := range actions {
+		step, ends, err := e.doAction(ctx, nameToTool, action, i)
+		if err != nil {
+			return steps, nil, withSteps(err, steps)
+		}
+		steps = append(steps, step)
+		if ends && direct == nil {
+			direct = &step
+		}
+	}
+
+	return steps, e.directReturn(ctx, steps, direct), nil
+}
+
+// directReturn ends the run with the observation of the step of a tool that
+// returns directly, such as an agent tool handing off. It returns nil if there
+// is no such step.
+func (e *Executor) directReturn(
+	ctx context.Context,
+	steps []schema.AgentStep,
+	direct *schema.AgentStep,
+) map[string]any {
+	if direct == nil {
+		return nil
+	}
+
+	outputKey := _defaultOutputKey
+	if keys := e.Agent.GetOutputKeys(); len(keys) > 0 {
+		outputKey = keys[0]
+	}
+	finish := &schema.AgentFinish{
+		ReturnValues: map[string]any{outputKey: direct.Observation},
+		Log:          direct.Observation,
+	}
+	if e.CallbacksHandler != nil {
+		e.CallbacksHandler.HandleAgentFinish(ctx, *finish)
+	}
+	return e.getReturn(finish, steps)
+}
+
+// doActionsParallel runs the actions with at most MaxParallelTools tools
+// running at once. The steps are returned in the order of the actions, with
+// the first one whose tool returns directly. The first tool error cancels the
+// context of the tools that are still running, and only the steps of the
+// actions that completed are returned with it.
+func (e *Executor) doActionsParallel(
+	ctx context.Context,
+	nameToTool map[string]tools.Tool,
+	actions []schema.AgentAction,
+) ([]schema.AgentStep, *schema.AgentStep, error) {
+	steps := make([]schema.AgentStep, len(actions))
+	done := make([]bool, len(actions))
+	direct := make([]bool, len(actions))
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(e.MaxParallelTools)
+	for i, action := range actions {
+		g.Go(func() error {
+			step, ends, err := e.doAction(ctx, nameToTool, action, i)
+			if err != nil {
+				return err
+			}
+			steps[i], done[i], direct[i] = step, true, ends
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		completed := make([]schema.AgentStep, 0, len(actions))
+		for i, step := range steps {
+			if done[i] {
+				completed = append(completed, step)
+			}
+		}
+		return completed, nil, err
+	}
+
+	if i := slices.Index(direct, true); i >= 0 {
+		return steps, &steps[i], nil
+	}
 	return steps, nil, nil
 }
 
+// doAction runs the action at the given index of the plan, and sends its
+// events if the run is streamed. It reports whether the tool ran, succeeded
+// and returns directly.
 func (e *Executor) doAction(
 	ctx context.Context,
-	steps []schema.AgentStep,
 	nameToTool map[string]tools.Tool,
 	action schema.AgentAction,
-) ([]schema.AgentStep, error) {
+	index int,
+) (schema.AgentStep, bool, error) {
+	id := toolCallID(ctx, action, index)
+	ctx = withToolCall(ctx, id)
+	emit(ctx, Event{Type: EventToolCallStarted, ToolCallID: id, Action: &action})
+
+	step, ends, err := e.runAction(ctx, nameToTool, action)
+	if err != nil {
+		return step, false, err
+	}
+
+	emit(ctx, Event{Type: EventToolResult, ToolCallID: id, Steps: []schema.AgentStep{step}})
+	return step, ends, nil
+}
+
+// runAction runs the tool of the action. It reports whether the tool ran,
+// succeeded and returns directly: actions that are rejected, corrected or
+// refused, and tool errors given as observations, never end the run.
+func (e *Executor) runAction(
+	ctx context.Context,
+	nameToTool map[string]tools.Tool,
+	action schema.AgentAction,
+) (schema.AgentStep, bool, error) {
 	if e.CallbacksHandler != nil {
 		e.CallbacksHandler.HandleAgentAction(ctx, action)
 	}
 
 	tool, ok := nameToTool[strings.ToUpper(action.Tool)]
 	if !ok {
-		return append(steps, schema.AgentStep{
+		return schema.AgentStep{
 			Action:      action,
 			Observation: fmt.Sprintf("%s is not a valid tool, try another one", action.Tool),
-		}), nil
+		}, false, nil
 	}
 
-	observation, err := tool.Call(ctx, action.ToolInput)
+	action, rejection, approved, err := e.approve(ctx, tool, action)
 	if err != nil {
-		return nil, err
+		return schema.AgentStep{}, false, &ToolError{Action: action, Err: err}
+	}
+	if !approved {
+		return schema.AgentStep{
+			Action:      action,
+			Observation: rejection,
+		}, false, nil
+	}
+
+	// The arguments are validated once approved, since the approval handler
+	// can change them.
+	correction, err := e.validateArguments(ctx, tool, action)
+	if err != nil {
+		return schema.AgentStep{}, false, &ToolError{Action: action, Err: err}
+	}
+	if correction != "" {
+		return schema.AgentStep{
+			Action:      action,
+			Observation: correction,
+		}, false, nil
+	}
+
+	if err := useTool(ctx, action.Tool); err != nil {
+		return schema.AgentStep{}, false, err
 	}
 
-	return append(steps, schema.AgentStep{
+	observation, err := e.callTool(ctx, tool, action)
+	var budgetErr *BudgetExceededError
+	if errors.As(err, &budgetErr) {
+		return schema.AgentStep{}, false, budgetErr
+	}
+	var loopErr *HandoffLoopError
+	if errors.As(err, &loopErr) {
+		return schema.AgentStep{
+			Action:      action,
+			Observation: loopErr.Error(),
+		}, false, nil
+	}
+	if err != nil {
+		if e.ToolErrorHandler == nil || e.ToolErrorHandler.Mode != ToolErrorObservation {
+			return schema.AgentStep{}, false, &ToolError{Action: action, Err: err}
+		}
+		observation = err.Error()
+		if e.ToolErrorHandler.Formatter != nil {
+			observation = e.ToolErrorHandler.Formatter(action, err)
+		}
+		return schema.AgentStep{
+			Action:      action,
+			Observation: observation,
+		}, false, nil
+	}
+
+	return schema.AgentStep{
 		Action:      action,
 		Observation: observation,
-	}), nil
+	}, returnsDirect(tool), nil
+}
+
+// callTool calls the tool of the action, calling it again on failure if the
+// tool error handler asks for retries.
+func (e *Executor) callTool(ctx context.Context, tool tools.Tool, action schema.AgentAction) (string, error) {
+	retries := 0
+	if e.ToolErrorHandler != nil && e.ToolErrorHandler.Mode == ToolErrorRetry {
+		retries = e.ToolErrorHandler.MaxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		observation, err := tool.Call(ctx, action.ToolInput)
+		if err == nil || attempt >= retries || ctx.Err() != nil ||
+			errors.Is(err, ErrHandoffLoop) || errors.Is(err, ErrBudgetExceeded) {
+			return observation, err
+		}
+	}
+}
+
+// nestedRunContext returns the context of a run started by a tool of another
+// run, such as an agent tool or a step of a plan. The nested run does not
+// share the checkpoint, budget or argument corrections of the run that started
+// it, and its events are tagged with the action that started it.
+func nestedRunContext(ctx context.Context) context.Context {
+	for _, key := range []any{runIDKey{}, budgetKey{}, argumentsKey{}} {
+		ctx = context.WithValue(ctx, key, nil)
+	}
+	return withNestedRun(ctx)
+}
+
+// withSteps records the intermediate steps completed before a tool error or
+// before the budget was exceeded.
+func withSteps(err error, steps []schema.AgentStep) error {
+	var toolErr *ToolError
+	if errors.As(err, &toolErr) {
+		toolErr.Steps = steps
+	}
+	var budgetErr *BudgetExceededError
+	if errors.As(err, &budgetErr) {
+		budgetErr.Steps = steps
+	}
+	return err
 }
 
 func (e *Executor) getReturn(finish *schema.AgentFinish, steps []schema.AgentStep) map[string]any {
@@ -2,8 +2,10 @@ package agents_test
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/sayerxofficial/langchaingo/agents"
@@ -24,6 +26,7 @@ type testAgent struct {
 	err        error
 	inputKeys  []string
 	outputKeys []string
+	tools      []tools.Tool
 
 	recordedIntermediateSteps []schema.AgentStep
 	recordedInputs            map[string]string
@@ -51,7 +54,7 @@ func (a testAgent) GetOutputKeys() []string {
 }
 
 func (a *testAgent) GetTools() []tools.Tool {
-	return nil
+	return a.tools
 }
 
 func TestExecutorWithErrorHandler(t *testing.T) {
@@ -173,3 +176,170 @@ func TestExecutorWithOpenAIFunctionAgent(t *testing.T) {
 	require.True(t, strings.Contains(result, "2012") || strings.Contains(result, "March"),
 		"correct answer 2012 or March not in response")
 }
+
+type funcTool struct {
+	name string
+	call func(ctx context.Context, input string) (string, error)
+}
+
+func (t funcTool) Name() string        { return t.name }
+func (t funcTool) Description() string { return t.name }
+func (t funcTool) Call(ctx context.Context, input string) (string, error) {
+	return t.call(ctx, input)
+}
+
+func TestExecutorWithParallelTools(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	// Every call waits for the others to start, so the test only finishes if
+	// the tools run concurrently.
+	var started sync.WaitGroup
+	started.Add(3)
+	echo := funcTool{name: "echo", call: func(ctx context.Context, input string) (string, error) {
+		started.Done()
+		started.Wait()
+		return "echo " + input, nil
+	}}
+
+	actions := []schema.AgentAction{
+		{Tool: "echo", ToolInput: "a", ToolID: "1"},
+		{Tool: "echo", ToolInput: "b", ToolID: "2"},
+		{Tool: "echo", ToolInput: "c", ToolID: "3"},
+	}
+	a := &testAgent{actions: actions, tools: []tools.Tool{echo}}
+	executor := agents.NewExecutor(
+		a,
+		agents.WithMaxIterations(1),
+		agents.WithParallelTools(3),
+		agents.WithReturnIntermediateSteps(),
+	)
+
+	result, err := executor.Call(ctx, nil)
+	require.ErrorIs(t, err, agents.ErrNotFinished)
+	require.Equal(t, []schema.AgentStep{
+		{Action: actions[0], Observation: "echo a"},
+		{Action: actions[1], Observation: "echo b"},
+		{Action: actions[2], Observation: "echo c"},
+	}, result["intermediateSteps"])
+}
+
+func TestExecutorWithParallelToolsCancelsOnError(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	errTool := errors.New("tool failed")
+	failing := funcTool{name: "fail", call: func(context.Context, string) (string, error) {
+		return "", errTool
+	}}
+	blocking := funcTool{name: "block", call: func(ctx context.Context, _ string) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}}
+
+	a := &testAgent{
+		actions: []schema.AgentAction{
+			{Tool: "block", ToolInput: "x"},
+			{Tool: "fail", ToolInput: "y"},
+		},
+		tools: []tools.Tool{failing, blocking},
+	}
+	executor := agents.NewExecutor(a, agents.WithParallelTools(2))
+
+	_, err := chains.Call(ctx, executor, nil)
+	require.ErrorIs(t, err, errTool)
+	require.Equal(t, 1, a.numPlanCalls)
+}
+
+func TestExecutorToolErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	errTool := errors.New("tool failed")
+	newFlaky := func(failures int) funcTool {
+		calls := 0
+		return funcTool{name: "flaky", call: func(context.Context, string) (string, error) {
+			calls++
+			if calls <= failures {
+				return "", errTool
+			}
+			return "ok", nil
+		}}
+	}
+	echo := funcTool{name: "echo", call: func(_ context.Context, input string) (string, error) {
+		return input, nil
+	}}
+	actions := []schema.AgentAction{
+		{Tool: "echo", ToolInput: "first"},
+		{Tool: "flaky", ToolInput: "second"},
+	}
+
+	tests := []struct {
+		name      string
+		handler   *agents.ToolErrorHandler
+		failures  int
+		wantErr   bool
+		wantSteps []schema.AgentStep
+	}{
+		{
+			name:      "abort by default",
+			failures:  1,
+			wantErr:   true,
+			wantSteps: []schema.AgentStep{{Action: actions[0], Observation: "first"}},
+		},
+		{
+			name: "observation",
+			handler: agents.NewToolErrorObservationHandler(func(a schema.AgentAction, err error) string {
+				return a.Tool + ": " + err.Error()
+			}),
+			failures: 1,
+			wantSteps: []schema.AgentStep{
+				{Action: actions[0], Observation: "first"},
+				{Action: actions[1], Observation: "flaky: tool failed"},
+			},
+		},
+		{
+			name:     "retry succeeds",
+			handler:  agents.NewToolErrorRetryHandler(2),
+			failures: 2,
+			wantSteps: []schema.AgentStep{
+				{Action: actions[0], Observation: "first"},
+				{Action: actions[1], Observation: "ok"},
+			},
+		},
+		{
+			name:      "retry exhausted",
+			handler:   agents.NewToolErrorRetryHandler(2),
+			failures:  3,
+			wantErr:   true,
+			wantSteps: []schema.AgentStep{{Action: actions[0], Observation: "first"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := &testAgent{actions: actions, tools: []tools.Tool{echo, newFlaky(tt.failures)}}
+			executor := agents.NewExecutor(
+				a,
+				agents.WithMaxIterations(1),
+				agents.WithReturnIntermediateSteps(),
+				agents.WithToolErrorHandler(tt.handler),
+			)
+
+			result, err := executor.Call(t.Context(), nil)
+			if !tt.wantErr {
+				require.ErrorIs(t, err, agents.ErrNotFinished)
+				require.Equal(t, tt.wantSteps, result["intermediateSteps"])
+				return
+			}
+
+			require.ErrorIs(t, err, errTool)
+			var toolErr *agents.ToolError
+			require.ErrorAs(t, err, &toolErr)
+			require.Equal(t, actions[1], toolErr.Action)
+			require.Equal(t, tt.wantSteps, toolErr.Steps)
+			require.Equal(t, tt.wantSteps, result["intermediateSteps"])
+		})
+	}
+}
@@ -17,6 +17,9 @@ const (
 	// ConversationalReactDescription is an AgentType constant that represents
 	// the "conversationalReactDescription" agent type.
 	ConversationalReactDescription AgentType = "conversationalReactDescription"
+	// PlanAndExecute is an AgentType constant that represents the
+	// "planAndExecute" agent type.
+	PlanAndExecute AgentType = "planAndExecute"
 )
 
 // Deprecated: This may be removed in the future; please use NewExecutor instead.
@@ -35,6 +38,8 @@ func Initialize(
 		agent = NewOneShotAgent(llm, tools, opts...)
 	case ConversationalReactDescription:
 		agent = NewConversationalAgent(llm, tools, opts...)
+	case PlanAndExecute:
+		agent = NewPlanAndExecuteAgent(llm, tools, opts...)
 	default:
 		return &Executor{}, ErrUnknownAgentType
 	}
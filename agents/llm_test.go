@@ -0,0 +1,79 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/llms/streaming"
+)
+
+// scriptedLLM answers with the given choices in order, streaming their
+// reasoning and text if asked to, and records the messages and tools of
+// every call.
+type scriptedLLM struct {
+	turns    []*llms.ContentChoice
+	usage    *llms.Usage
+	messages [][]llms.MessageContent
+	tools    [][]llms.Tool
+}
+
+// textTurns returns choices answering with the given texts.
+func textTurns(texts ...string) []*llms.ContentChoice {
+	turns := make([]*llms.ContentChoice, 0, len(texts))
+	for _, text := range texts {
+		turns = append(turns, &llms.ContentChoice{Content: text})
+	}
+	return turns
+}
+
+func toolCall(id, name, args string) llms.ToolCall {
+	return llms.ToolCall{ID: id, Type: "function", FunctionCall: &llms.FunctionCall{Name: name, Arguments: args}}
+}
+
+func (m *scriptedLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *scriptedLLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	m.messages = append(m.messages, messages)
+	m.tools = append(m.tools, opts.Tools)
+	if len(m.turns) == 0 {
+		return nil, errors.New("no more turns")
+	}
+	choice := m.turns[0]
+	m.turns = m.turns[1:]
+
+	if opts.StreamingFunc != nil {
+		if err := streaming.CallWithReasoning(ctx, opts.StreamingFunc, choice.ReasoningContent); err != nil {
+			return nil, err
+		}
+		if err := streaming.CallWithText(ctx, opts.StreamingFunc, choice.Content); err != nil {
+			return nil, err
+		}
+	}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{choice}, Usage: m.usage}, nil
+}
+
+// prompts returns the text of the messages of every call.
+func (m *scriptedLLM) prompts() []string {
+	prompts := make([]string, 0, len(m.messages))
+	for _, messages := range m.messages {
+		var prompt strings.Builder
+		for _, msg := range messages {
+			for _, part := range msg.Parts {
+				if text, ok := part.(llms.TextContent); ok {
+					prompt.WriteString(text.Text)
+				}
+			}
+		}
+		prompts = append(prompts, prompt.String())
+	}
+	return prompts
+}
@@ -1,6 +1,7 @@
 package agents
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -59,8 +60,31 @@ func toolNames(tools []tools.Tool) string {
 func toolDescriptions(tools []tools.Tool) string {
 	var ts strings.Builder
 	for _, tool := range tools {
-		ts.WriteString(fmt.Sprintf("- %s: %s\n", tool.Name(), tool.Description()))
+		ts.WriteString(fmt.Sprintf("- %s: %s", tool.Name(), tool.Description()))
+		if parameters := toolParameters(tool); parameters != "" {
+			ts.WriteString(" The input must be a JSON object matching this schema: ")
+			ts.WriteString(parameters)
+		}
+		ts.WriteString("\n")
 	}
 
 	return ts.String()
 }
+
+// toolParameters returns the JSON schema of the input of a tool that
+// describes its input, or an empty string.
+func toolParameters(tool tools.Tool) string {
+	ft, ok := tool.(tools.FunctionTool)
+	if !ok {
+		return ""
+	}
+	parameters := ft.FunctionDefinition().Parameters
+	if parameters == nil {
+		return ""
+	}
+	data, err := json.Marshal(parameters)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/sayerxofficial/langchaingo/callbacks"
 	"github.com/sayerxofficial/langchaingo/llms"
-	"github.com/sayerxofficial/langchaingo/llms/streaming"
 	"github.com/sayerxofficial/langchaingo/prompts"
 	"github.com/sayerxofficial/langchaingo/schema"
 	"github.com/sayerxofficial/langchaingo/tools"
@@ -53,24 +52,7 @@ func NewOpenAIFunctionsAgent(llm llms.Model, tools []tools.Tool, opts ...Option)
 }
 
 func (o *OpenAIFunctionsAgent) tools() []llms.Tool {
-	res := make([]llms.Tool, 0)
-	for _, tool := range o.Tools {
-		res = append(res, llms.Tool{
-			Type: "function",
-			Function: &llms.FunctionDefinition{
-				Name:        tool.Name(),
-				Description: tool.Description(),
-				Parameters: map[string]any{
-					"properties": map[string]any{
-						"__arg1": map[string]string{"title": "__arg1", "type": "string"},
-					},
-					"required": []string{"__arg1"},
-					"type":     "object",
-				},
-			},
-		})
-	}
-	return res
+	return toolDefinitions(o.Tools)
 }
 
 // Plan decides what action to take or returns the final result of the input.
@@ -85,14 +67,7 @@ func (o *OpenAIFunctionsAgent) Plan(
 	}
 	fullInputs[agentScratchpad] = o.constructScratchPad(intermediateSteps)
 
-	var stream streaming.Callback
-
-	if o.CallbacksHandler != nil {
-		stream = func(ctx context.Context, chunk streaming.Chunk) error {
-			o.CallbacksHandler.HandleStreamingFunc(ctx, chunk)
-			return nil
-		}
-	}
+	stream := streamingFunc(ctx, o.CallbacksHandler)
 
 	prompt, err := o.Prompt.FormatPrompt(fullInputs)
 	if err != nil {
@@ -158,6 +133,7 @@ func (o *OpenAIFunctionsAgent) Plan(
 	if err != nil {
 		return nil, nil, err
 	}
+	RecordUsage(ctx, result.Usage)
 
 	return o.ParseOutput(result)
 }
@@ -204,6 +180,13 @@ func (o *OpenAIFunctionsAgent) constructScratchPad(steps []schema.AgentStep) []l
 
 	messages := make([]llms.ChatMessage, 0)
 	for _, step := range steps {
+		// Steps without an action, such as parsing errors, are given as a
+		// human message.
+		if step.Action.Tool == "" {
+			messages = append(messages, llms.HumanChatMessage{Content: step.Observation})
+			continue
+		}
+
 		// First add the AI message with the tool call
 		messages = append(messages, llms.AIChatMessage{
 			Content: step.Action.Log,
@@ -291,7 +274,7 @@ func (o *OpenAIFunctionsAgent) parseToolCalls(id, name, args, content string) (s
 	}
 
 	// extract the first argument from the tool call if it exists
-	if arg1, ok := argsMap["__arg1"]; ok {
+	if arg1, ok := argsMap[_toolArgument]; ok {
 		argCheck, ok := arg1.(string)
 		if ok {
 			args = argCheck
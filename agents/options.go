@@ -13,8 +13,16 @@ type Options struct {
 	memory                  schema.Memory
 	callbacksHandler        callbacks.Handler
 	errorHandler            *ParserErrorHandler
+	toolErrorHandler        *ToolErrorHandler
+	approvalHandler         ApprovalHandler
+	checkpointStore         CheckpointStore
+	budget                  *Budget
+	handoff                 bool
+	toolArgumentAttempts    int
 	maxIterations           int
 	returnIntermediateSteps bool
+	maxParallelTools        int
+	stepExecutorOptions     []Option
 	outputKey               string
 	promptPrefix            string
 	formatInstructions      string
@@ -31,9 +39,10 @@ type Option func(*Options)
 
 func executorDefaultOptions() Options {
 	return Options{
-		maxIterations: _defaultMaxIterations,
-		outputKey:     _defaultOutputKey,
-		memory:        memory.NewSimple(),
+		maxIterations:        _defaultMaxIterations,
+		outputKey:            _defaultOutputKey,
+		memory:               memory.NewSimple(),
+		toolArgumentAttempts: _defaultArgumentAttempts,
 	}
 }
 
@@ -55,6 +64,12 @@ func conversationalDefaultOptions() Options {
 	}
 }
 
+func planAndExecuteDefaultOptions() Options {
+	return Options{
+		outputKey: _defaultOutputKey,
+	}
+}
+
 func openAIFunctionsDefaultOptions() Options {
 	return Options{
 		systemMessage: "You are a helpful AI assistant.",
@@ -161,6 +176,83 @@ func WithParserErrorHandler(errorHandler *ParserErrorHandler) Option {
 	}
 }
 
+// WithToolErrorHandler is an option for setting how an executor handles errors
+// returned by tools.
+func WithToolErrorHandler(errorHandler *ToolErrorHandler) Option {
+	return func(co *Options) {
+		co.toolErrorHandler = errorHandler
+	}
+}
+
+// WithApprovalHandler is an option for setting a handler that must approve
+// every action before the executor runs its tool. Rejected actions are not
+// run, and the reason of the rejection is given to the agent as the
+// observation. Approved actions run with the tool input chosen by the
+// handler, which is also the input recorded in the intermediate steps.
+func WithApprovalHandler(handler ApprovalHandler) Option {
+	return func(co *Options) {
+		co.approvalHandler = handler
+	}
+}
+
+// WithCheckpointStore is an option for setting the store an executor saves a
+// checkpoint to after every iteration of a run. Only runs given an ID with
+// WithRunID are checkpointed, and they can be continued with Executor.Resume.
+func WithCheckpointStore(store CheckpointStore) Option {
+	return func(co *Options) {
+		co.checkpointStore = store
+	}
+}
+
+// WithBudget is an option for limiting the time, tokens, cost and tool calls
+// of every run of an executor.
+func WithBudget(budget Budget) Option {
+	return func(co *Options) {
+		co.budget = &budget
+	}
+}
+
+// WithHandoff is an option for making an agent tool end the run of the
+// calling agent with the answer of its agent.
+func WithHandoff() Option {
+	return func(co *Options) {
+		co.handoff = true
+	}
+}
+
+// WithToolArgumentAttempts is an option for setting how many invalid calls
+// in a row to a tool the executor sends back to the agent for correction.
+// The arguments that agents calling tools natively give to tools that
+// implement tools.FunctionTool are validated against their parameters before
+// the tool runs, and the run fails with ErrInvalidToolArguments once the
+// attempts are used up. A negative value turns the validation off. Defaults
+// to 3.
+func WithToolArgumentAttempts(attempts int) Option {
+	return func(co *Options) {
+		co.toolArgumentAttempts = attempts
+	}
+}
+
+// WithParallelTools is an option for running the actions the agent plans in a
+// single step concurrently, with at most n tools running at once. The
+// intermediate steps keep the order of the actions. If a tool fails, the
+// context passed to the other running tools is canceled. The callbacks
+// handler of the executor must be safe for concurrent use.
+func WithParallelTools(n int) Option {
+	return func(co *Options) {
+		co.maxParallelTools = n
+	}
+}
+
+// WithStepExecutorOptions is an option for setting the options of the
+// agent and executor that run the steps of a PlanAndExecuteAgent. The other
+// options given to NewPlanAndExecuteAgent do not apply to them.
+func WithStepExecutorOptions(opts ...Option) Option {
+	return func(co *Options) {
+		co.stepExecutorOptions = opts
+	}
+}
+
 type OpenAIOption struct{}
 
 func NewOpenAIOption() OpenAIOption {
@@ -0,0 +1,274 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sayerxofficial/langchaingo/callbacks"
+	"github.com/sayerxofficial/langchaingo/chains"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/memory"
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/tools"
+)
+
+const (
+	// PlanStepTool is the name of the tool a PlanAndExecuteAgent runs the
+	// steps of its plan with.
+	PlanStepTool = "execute_plan_step"
+
+	_planLogPrefix      = "Plan:\n"
+	_stepFailedPrefix   = "The step failed: "
+	_noStepsPlaceholder = "None."
+)
+
+var _planStepPattern = regexp.MustCompile(`^\s*\d+[.)]\s*(.+)$`)
+
+// PlanAndExecuteAgent is an Agent that first asks the model for a plan, a
+// list of steps, then runs every step with a tool-using executor. After each
+// step the model revises the remaining plan from the results so far, which
+// lets it recover from a failed step, or gives the final answer.
+//
+// Every step is an action of the PlanStepTool tool: the log of the action
+// holds the plan, starting with the step, the input of the action is the step
+// with the results of the previous ones, and the observation is the result of
+// the step.
+type PlanAndExecuteAgent struct {
+	// Planner is the chain that writes the first plan.
+	Planner chains.Chain
+	// Replanner is the chain that revises the plan after every step.
+	Replanner chains.Chain
+	// StepExecutor runs a single step of the plan. It is usually an Executor
+	// with a tool-using agent.
+	StepExecutor chains.Chain
+	// Tools is a list of the tools the steps can use.
+	Tools []tools.Tool
+	// Output key is the key where the final output is placed.
+	OutputKey string
+	// CallbacksHandler is the handler for callbacks.
+	CallbacksHandler callbacks.Handler
+}
+
+var _ Agent = (*PlanAndExecuteAgent)(nil)
+
+// NewPlanAndExecuteAgent creates a new PlanAndExecuteAgent. The steps of the
+// plan are run by an executor with a zero shot react agent using the tools,
+// both created with the options given by WithStepExecutorOptions only. The
+// approval handler given by WithApprovalHandler approves the tool calls of
+// the steps unless those options set one.
+func NewPlanAndExecuteAgent(llm llms.Model, tools []tools.Tool, opts ...Option) *PlanAndExecuteAgent {
+	options := planAndExecuteDefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	stepExecutor := NewExecutor(
+		NewOneShotAgent(llm, tools, options.stepExecutorOptions...),
+		options.stepExecutorOptions...,
+	)
+	// The step executor runs inside the run of the agent, so it has no state
+	// of its own, and it uses the budget of that run.
+	stepExecutor.Memory = memory.NewSimple()
+	stepExecutor.ReturnIntermediateSteps = false
+	stepExecutor.CheckpointStore = nil
+	stepExecutor.Budget = nil
+	if stepExecutor.ApprovalHandler == nil {
+		stepExecutor.ApprovalHandler = options.approvalHandler
+	}
+
+	return &PlanAndExecuteAgent{
+		Planner: chains.NewLLMChain(
+			llm,
+			createPlannerPrompt(tools),
+			chains.WithCallback(options.callbacksHandler),
+		),
+		Replanner: chains.NewLLMChain(
+			llm,
+			createReplannerPrompt(tools),
+			chains.WithCallback(options.callbacksHandler),
+		),
+		StepExecutor:     stepExecutor,
+		Tools:            tools,
+		OutputKey:        options.outputKey,
+		CallbacksHandler: options.callbacksHandler,
+	}
+}
+
+// Plan returns the next step of the plan, writing or revising the plan first,
+// or the final answer.
+func (a *PlanAndExecuteAgent) Plan(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	completed := completedPlanSteps(intermediateSteps)
+
+	var output string
+	var err error
+	if len(completed) == 0 {
+		output, err = chains.Predict(ctx, a.Planner, map[string]any{"input": inputs["input"]})
+	} else {
+		remaining := remainingPlanSteps(completed[len(completed)-1].Action)
+		output, err = chains.Predict(ctx, a.Replanner, map[string]any{
+			"input":           inputs["input"],
+			"completed_steps": formatCompletedSteps(completed),
+			"remaining_steps": formatPlan(remaining),
+		})
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, answer, ok := strings.Cut(output, _finalAnswerAction); ok {
+		answer = strings.TrimSpace(answer)
+		return nil, &schema.AgentFinish{
+			ReturnValues: map[string]any{a.OutputKey: answer},
+			Log:          output,
+		}, nil
+	}
+
+	plan := parsePlan(output)
+	if len(plan) == 0 {
+		return nil, nil, fmt.Errorf("%w: no plan in %q", ErrUnableToParseOutput, output)
+	}
+
+	return []schema.AgentAction{{
+		Tool:      PlanStepTool,
+		ToolInput: formatStepInput(inputs["input"], completed, plan[0]),
+		Log:       _planLogPrefix + formatPlan(plan),
+	}}, nil, nil
+}
+
+func (a *PlanAndExecuteAgent) GetInputKeys() []string {
+	return []string{"input"}
+}
+
+func (a *PlanAndExecuteAgent) GetOutputKeys() []string {
+	return []string{a.OutputKey}
+}
+
+// GetTools returns the tool that runs the steps of the plan.
+func (a *PlanAndExecuteAgent) GetTools() []tools.Tool {
+	return []tools.Tool{planStepTool{agent: a}}
+}
+
+// planStepTool runs a step of the plan with the step executor. A failed step
+// is reported as its observation, so that the plan can be revised. The step
+// uses the budget of the run of the plan, and exceeding it ends that run.
+type planStepTool struct {
+	agent *PlanAndExecuteAgent
+}
+
+func (t planStepTool) Name() string {
+	return PlanStepTool
+}
+
+func (t planStepTool) Description() string {
+	return "Carries out a step of the plan."
+}
+
+func (t planStepTool) Call(ctx context.Context, input string) (string, error) {
+	stepCtx := nestedRunContext(ctx)
+	if budget, ok := ctx.Value(budgetKey{}).(*budgetTracker); ok {
+		stepCtx = context.WithValue(stepCtx, budgetKey{}, budget)
+	}
+
+	output, err := chains.Run(stepCtx, t.agent.StepExecutor, input)
+	if err != nil {
+		if ctx.Err() != nil || errors.Is(err, ErrBudgetExceeded) {
+			return "", err
+		}
+		return _stepFailedPrefix + err.Error(), nil
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// completedPlanSteps returns the steps of the plan that have been run.
+func completedPlanSteps(steps []schema.AgentStep) []schema.AgentStep {
+	completed := make([]schema.AgentStep, 0, len(steps))
+	for _, step := range steps {
+		if step.Action.Tool == PlanStepTool {
+			completed = append(completed, step)
+		}
+	}
+	return completed
+}
+
+// planOf returns the plan of an action, starting with the step of the
+// action.
+func planOf(action schema.AgentAction) []string {
+	return parsePlan(strings.TrimPrefix(action.Log, _planLogPrefix))
+}
+
+// remainingPlanSteps returns the steps of the plan of an action that come
+// after the step of the action.
+func remainingPlanSteps(action schema.AgentAction) []string {
+	plan := planOf(action)
+	if len(plan) == 0 {
+		return nil
+	}
+	return plan[1:]
+}
+
+// stepOf returns the step of the plan an action runs.
+func stepOf(action schema.AgentAction) string {
+	if plan := planOf(action); len(plan) > 0 {
+		return plan[0]
+	}
+	return action.ToolInput
+}
+
+// parsePlan returns the steps of a numbered list. Text that is not a
+// numbered list is read as one step per line.
+func parsePlan(text string) []string {
+	var numbered, lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+		if m := _planStepPattern.FindStringSubmatch(line); m != nil {
+			numbered = append(numbered, strings.TrimSpace(m[1]))
+		}
+	}
+	if len(numbered) > 0 {
+		return numbered
+	}
+	return lines
+}
+
+func formatPlan(plan []string) string {
+	if len(plan) == 0 {
+		return _noStepsPlaceholder
+	}
+	var sb strings.Builder
+	for i, step := range plan {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, step)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func formatCompletedSteps(steps []schema.AgentStep) string {
+	if len(steps) == 0 {
+		return _noStepsPlaceholder
+	}
+	var sb strings.Builder
+	for i, step := range steps {
+		fmt.Fprintf(&sb, "%d. %s\nResult: %s\n", i+1, stepOf(step.Action), step.Observation)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// formatStepInput gives the step executor the step to carry out, with the
+// objective and the results of the previous steps it may depend on.
+func formatStepInput(objective string, completed []schema.AgentStep, step string) string {
+	if len(completed) == 0 {
+		return fmt.Sprintf("Objective: %s\n\nCarry out this step: %s", objective, step)
+	}
+	return fmt.Sprintf("Objective: %s\n\nPrevious steps and their results:\n%s\n\nCarry out this step: %s",
+		objective, formatCompletedSteps(completed), step)
+}
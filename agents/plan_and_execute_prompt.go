@@ -0,0 +1,59 @@
+package agents
+
+import (
+	"github.com/sayerxofficial/langchaingo/prompts"
+	"github.com/sayerxofficial/langchaingo/tools"
+)
+
+const (
+	_defaultPlannerTemplate = `Let's first understand the problem and devise a plan to solve it.
+The steps of the plan will be carried out by an assistant with access to the following tools:
+
+{{.tool_descriptions}}
+
+Write the plan as a numbered list of steps, one step per line, and nothing else.
+Each step must be a self-contained task for the assistant. Do not add superfluous steps.
+The result of the final step should be the answer to the objective.
+
+Objective: {{.input}}`
+
+	_defaultReplannerTemplate = `You are revising a plan to reach an objective.
+The steps of the plan are carried out by an assistant with access to the following tools:
+
+{{.tool_descriptions}}
+
+Objective: {{.input}}
+
+Completed steps and their results:
+{{.completed_steps}}
+
+Remaining steps of the current plan:
+{{.remaining_steps}}
+
+If the objective is reached, respond with "Final Answer: " followed by the answer to the objective.
+Otherwise respond with the numbered list of the steps that still need to be done, one step per line,
+and nothing else. Keep the remaining steps if they are still right, and change them if a step failed
+or its result calls for a different approach. Do not repeat completed steps.`
+)
+
+func createPlannerPrompt(tools []tools.Tool) prompts.PromptTemplate {
+	return prompts.PromptTemplate{
+		Template:       _defaultPlannerTemplate,
+		TemplateFormat: prompts.TemplateFormatGoTemplate,
+		InputVariables: []string{"input"},
+		PartialVariables: map[string]any{
+			"tool_descriptions": toolDescriptions(tools),
+		},
+	}
+}
+
+func createReplannerPrompt(tools []tools.Tool) prompts.PromptTemplate {
+	return prompts.PromptTemplate{
+		Template:       _defaultReplannerTemplate,
+		TemplateFormat: prompts.TemplateFormatGoTemplate,
+		InputVariables: []string{"input", "completed_steps", "remaining_steps"},
+		PartialVariables: map[string]any{
+			"tool_descriptions": toolDescriptions(tools),
+		},
+	}
+}
@@ -0,0 +1,195 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/agents"
+	"github.com/sayerxofficial/langchaingo/chains"
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/tools"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanAndExecuteAgent(t *testing.T) {
+	t.Parallel()
+
+	lookup := funcTool{name: "lookup", call: func(_ context.Context, input string) (string, error) {
+		return "the population of " + input + " is 2 million", nil
+	}}
+	llm := &scriptedLLM{turns: textTurns(
+		// The first plan.
+		"Here is the plan:\n1. Find the population of Paris.\n2. Double it.",
+		// The first step.
+		"Thought: I should look it up.\nAction: lookup\nAction Input: Paris",
+		"Thought: I now know the final answer\nFinal Answer: 2 million",
+		// The plan is kept.
+		"1. Double it.",
+		// The second step fails.
+		"I am not sure what to do.",
+		// The plan is revised.
+		"1. Multiply 2 million by 2.",
+		"Thought: I now know the final answer\nFinal Answer: 4 million",
+		// The objective is reached.
+		"Final Answer: Twice the population of Paris is 4 million.",
+	)}
+
+	executor, err := agents.Initialize(llm, []tools.Tool{lookup}, agents.PlanAndExecute,
+		agents.WithReturnIntermediateSteps())
+	require.NoError(t, err)
+
+	result, err := chains.Call(t.Context(), executor, map[string]any{"input": "What is twice the population of Paris?"})
+	require.NoError(t, err)
+	require.Equal(t, "Twice the population of Paris is 4 million.", result["output"])
+
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 3)
+	for _, step := range steps {
+		require.Equal(t, agents.PlanStepTool, step.Action.Tool)
+	}
+
+	require.Equal(t, "Plan:\n1. Find the population of Paris.\n2. Double it.", steps[0].Action.Log)
+	require.Equal(t, "2 million", steps[0].Observation)
+
+	require.Equal(t, "Plan:\n1. Double it.", steps[1].Action.Log)
+	require.Contains(t, steps[1].Action.ToolInput, "Result: 2 million")
+	require.Contains(t, steps[1].Observation, "The step failed")
+
+	require.Equal(t, "Plan:\n1. Multiply 2 million by 2.", steps[2].Action.Log)
+	require.Equal(t, "4 million", steps[2].Observation)
+
+	// The replanner sees the failure of the second step.
+	replan := llm.prompts()[5]
+	require.Contains(t, replan, "2. Double it.\nResult: The step failed")
+	require.Contains(t, replan, "Remaining steps of the current plan:\nNone.")
+}
+
+func TestPlanAndExecuteAgentBudget(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	lookup := funcTool{name: "lookup", call: func(_ context.Context, input string) (string, error) {
+		calls++
+		return "the population of " + input + " is 2 million", nil
+	}}
+	llm := &scriptedLLM{turns: textTurns(
+		"1. Find the population of Paris.\n2. Find the population of Lyon.",
+		"Thought: I should look it up.\nAction: lookup\nAction Input: Paris",
+		"Thought: I now know the final answer\nFinal Answer: 2 million",
+		"1. Find the population of Lyon.",
+		// The second call to the tool exceeds the budget of the run.
+		"Thought: I should look it up.\nAction: lookup\nAction Input: Lyon",
+		"Final Answer: Paris has 2 million people.",
+	)}
+
+	executor, err := agents.Initialize(llm, []tools.Tool{lookup}, agents.PlanAndExecute,
+		agents.WithReturnIntermediateSteps(),
+		agents.WithBudget(agents.Budget{MaxToolCalls: map[string]int{"lookup": 1}, FinalAnswer: true}))
+	require.NoError(t, err)
+
+	result, err := chains.Call(t.Context(), executor, map[string]any{"input": "How many people live in Paris and Lyon?"})
+	require.NoError(t, err)
+	require.Equal(t, "Paris has 2 million people.", result["output"])
+	require.Equal(t, 1, calls)
+
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 2)
+	require.Equal(t, "2 million", steps[0].Observation)
+	require.Contains(t, steps[1].Observation, "budget")
+}
+
+func TestPlanAndExecuteAgentBudgetExceeded(t *testing.T) {
+	t.Parallel()
+
+	lookup := funcTool{name: "lookup", call: func(context.Context, string) (string, error) {
+		return "2 million", nil
+	}}
+	llm := &scriptedLLM{turns: textTurns(
+		"1. Find the population of Paris.",
+		"Thought: I should look it up.\nAction: lookup\nAction Input: Paris",
+		"Thought: I should look it up again.\nAction: lookup\nAction Input: Paris",
+	)}
+
+	executor, err := agents.Initialize(llm, []tools.Tool{lookup}, agents.PlanAndExecute,
+		agents.WithBudget(agents.Budget{MaxToolCalls: map[string]int{"lookup": 1}}))
+	require.NoError(t, err)
+
+	// The step does not fail on its own: the run of the plan does.
+	_, err = chains.Call(t.Context(), executor, map[string]any{"input": "How many people live in Paris?"})
+	var budgetErr *agents.BudgetExceededError
+	require.ErrorAs(t, err, &budgetErr)
+	require.Equal(t, agents.BudgetLimitToolCalls, budgetErr.Limit)
+	require.Equal(t, "lookup", budgetErr.Tool)
+	require.Empty(t, budgetErr.Steps)
+}
+
+func TestPlanAndExecuteAgentFailingStep(t *testing.T) {
+	t.Parallel()
+
+	lookup := funcTool{name: "lookup", call: func(context.Context, string) (string, error) {
+		return "", errors.New("service unavailable")
+	}}
+	llm := &scriptedLLM{turns: textTurns(
+		"1. Find the population of Paris.",
+		"Thought: I should look it up.\nAction: lookup\nAction Input: Paris",
+		"Final Answer: The population of Paris could not be found.",
+	)}
+
+	var approved []string
+	executor, err := agents.Initialize(llm, []tools.Tool{lookup}, agents.PlanAndExecute,
+		agents.WithReturnIntermediateSteps(),
+		agents.WithApprovalHandler(agents.ApprovalFunc(
+			func(_ context.Context, action schema.AgentAction) (agents.ApprovalDecision, error) {
+				approved = append(approved, action.Tool)
+				return agents.Approve(), nil
+			})))
+	require.NoError(t, err)
+
+	result, err := chains.Call(t.Context(), executor, map[string]any{"input": "How many people live in Paris?"})
+	require.NoError(t, err)
+	require.Equal(t, "The population of Paris could not be found.", result["output"])
+
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 1)
+	require.Contains(t, steps[0].Observation, "The step failed")
+	require.Contains(t, steps[0].Observation, "service unavailable")
+	require.Contains(t, llm.prompts()[2], "Result: The step failed")
+
+	// Only the tool called by the step is approved, not the step itself.
+	require.Equal(t, []string{"lookup"}, approved)
+}
+
+func TestPlanAndExecuteAgentStepExecutorOptions(t *testing.T) {
+	t.Parallel()
+
+	lookup := funcTool{name: "lookup", call: func(context.Context, string) (string, error) {
+		return "2 million", nil
+	}}
+	llm := &scriptedLLM{turns: textTurns(
+		"1. Find the population of Paris.",
+		"Thought: I should look it up.\nAction: lookup\nAction Input: Paris",
+		"Thought: I now know the final answer\nFinal Answer: 2 million",
+		"Final Answer: Paris has 2 million people.",
+	)}
+
+	// The options of the run of the plan do not apply to its steps.
+	executor, err := agents.Initialize(llm, []tools.Tool{lookup}, agents.PlanAndExecute,
+		agents.WithOutputKey("answer"),
+		agents.WithMaxIterations(2),
+		agents.WithPromptPrefix("You plan trips."),
+		agents.WithStepExecutorOptions(agents.WithPromptPrefix("You look facts up.")))
+	require.NoError(t, err)
+
+	result, err := chains.Call(t.Context(), executor, map[string]any{"input": "How many people live in Paris?"})
+	require.NoError(t, err)
+	require.Equal(t, "Paris has 2 million people.", result["answer"])
+
+	step := llm.prompts()[1]
+	require.Contains(t, step, "You look facts up.")
+	require.NotContains(t, step, "You plan trips.")
+}
@@ -0,0 +1,208 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sayerxofficial/langchaingo/callbacks"
+	"github.com/sayerxofficial/langchaingo/chains"
+	"github.com/sayerxofficial/langchaingo/llms/streaming"
+	"github.com/sayerxofficial/langchaingo/schema"
+
+	"github.com/google/uuid"
+)
+
+// EventType is the type of an Event sent by Executor.Stream.
+type EventType string
+
+const (
+	// EventLLMToken is a chunk of text generated by the model.
+	EventLLMToken EventType = "llm_token"
+	// EventReasoningToken is a chunk of the reasoning of the model.
+	EventReasoningToken EventType = "reasoning_token"
+	// EventToolCallStarted is sent before the tool of an action runs.
+	EventToolCallStarted EventType = "tool_call_started"
+	// EventToolResult is sent with the step of an action once it is done.
+	EventToolResult EventType = "tool_result"
+	// EventStepFinished is sent once all the actions of a step are done.
+	EventStepFinished EventType = "step_finished"
+	// EventFinalAnswer is the last event of a successful run.
+	EventFinalAnswer EventType = "final_answer"
+	// EventError is the last event of a failed run.
+	EventError EventType = "error"
+)
+
+// Event is sent by Executor.Stream while an agent runs. A step is one
+// iteration of the executor: the model plans, then the tools of the planned
+// actions run. All the events of a step share its StepID, and the events of
+// an action share its ToolCallID.
+//
+// Tools that run an executor of their own, such as agent tools and the steps
+// of a PlanAndExecuteAgent, stream the events of that nested run as well.
+// Their ParentToolCallID is the ToolCallID of the action that started the
+// nested run, and their steps and iterations are those of the nested run.
+type Event struct {
+	Type EventType
+	// StepID identifies the step the event belongs to. It is empty for
+	// EventFinalAnswer and EventError.
+	StepID string
+	// Iteration is the index of the step in the run.
+	Iteration int
+	// ParentToolCallID identifies the action that started the nested run the
+	// event belongs to. It is empty for the events of the streamed run.
+	ParentToolCallID string
+	// Content is the text of EventLLMToken and EventReasoningToken.
+	Content string
+	// ToolCallID identifies the action of EventToolCallStarted and
+	// EventToolResult.
+	ToolCallID string
+	// Action is the action of EventToolCallStarted.
+	Action *schema.AgentAction
+	// Steps holds the step of EventToolResult, and all the steps taken
+	// during the step for EventStepFinished.
+	Steps []schema.AgentStep
+	// Output is the output of the run for EventFinalAnswer.
+	Output map[string]any
+	// Err is the error of EventError.
+	Err error
+}
+
+type eventSinkKey struct{}
+
+// eventSink sends the events of a run, tagged with the current step and, for
+// nested runs, the action that started the run.
+type eventSink struct {
+	send             func(Event)
+	stepID           string
+	iteration        int
+	toolCallID       string
+	parentToolCallID string
+}
+
+func sinkFromContext(ctx context.Context) (*eventSink, bool) {
+	sink, ok := ctx.Value(eventSinkKey{}).(*eventSink)
+	return sink, ok
+}
+
+// withStep returns a context whose events belong to a new step.
+func withStep(ctx context.Context, iteration int) context.Context {
+	sink, ok := sinkFromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, eventSinkKey{}, &eventSink{
+		send:             sink.send,
+		stepID:           uuid.New().String(),
+		iteration:        iteration,
+		parentToolCallID: sink.parentToolCallID,
+	})
+}
+
+// withToolCall returns a context whose events belong to the action with the
+// given tool call ID, and whose nested runs are tagged with it.
+func withToolCall(ctx context.Context, toolCallID string) context.Context {
+	sink, ok := sinkFromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	actionSink := *sink
+	actionSink.toolCallID = toolCallID
+	return context.WithValue(ctx, eventSinkKey{}, &actionSink)
+}
+
+// withNestedRun returns a context whose events belong to a run started by the
+// current action.
+func withNestedRun(ctx context.Context) context.Context {
+	sink, ok := sinkFromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, eventSinkKey{}, &eventSink{
+		send:             sink.send,
+		parentToolCallID: sink.toolCallID,
+	})
+}
+
+// emit sends an event if the run is streamed.
+func emit(ctx context.Context, event Event) {
+	sink, ok := sinkFromContext(ctx)
+	if !ok {
+		return
+	}
+	event.StepID = sink.stepID
+	event.Iteration = sink.iteration
+	event.ParentToolCallID = sink.parentToolCallID
+	sink.send(event)
+}
+
+// toolCallID returns the ID used to correlate the events of an action.
+func toolCallID(ctx context.Context, action schema.AgentAction, index int) string {
+	if action.ToolID != "" {
+		return action.ToolID
+	}
+	sink, ok := sinkFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s-%d", sink.stepID, index)
+}
+
+// streamingFunc returns the streaming function agents give to the model. It
+// forwards chunks to the callbacks handler, and to Executor.Stream if the run
+// is streamed. It returns nil if there is nothing to forward to.
+func streamingFunc(ctx context.Context, handler callbacks.Handler) streaming.Callback {
+	_, streamed := sinkFromContext(ctx)
+	if handler == nil && !streamed {
+		return nil
+	}
+
+	return func(ctx context.Context, chunk streaming.Chunk) error {
+		if handler != nil {
+			handler.HandleStreamingFunc(ctx, chunk)
+		}
+		switch chunk.Type {
+		case streaming.ChunkTypeText, streaming.ChunkTypeNone:
+			if chunk.Content != "" {
+				emit(ctx, Event{Type: EventLLMToken, Content: chunk.Content})
+			}
+		case streaming.ChunkTypeReasoning:
+			emit(ctx, Event{Type: EventReasoningToken, Content: chunk.ReasoningContent})
+		case streaming.ChunkTypeToolCall, streaming.ChunkTypeDone:
+		}
+		return nil
+	}
+}
+
+// Stream runs the agent like chains.Call and returns the events of the run as
+// they happen. The channel is closed after an EventFinalAnswer or an
+// EventError. The run stops if ctx is canceled, so a caller that stops
+// reading must cancel ctx.
+//
+// LLM and reasoning tokens are streamed by the agents of this package. Other
+// agents can stream them by calling the model with a streaming function that
+// forwards chunks to the callbacks handler of the executor.
+func (e *Executor) Stream(ctx context.Context, inputs map[string]any, options ...chains.ChainCallOption) <-chan Event { //nolint:lll
+	events := make(chan Event)
+	ctx = context.WithValue(ctx, eventSinkKey{}, &eventSink{send: func(event Event) {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}})
+
+	go func() {
+		defer close(events)
+
+		outputs, err := chains.Call(ctx, e, inputs, options...)
+		last := Event{Type: EventFinalAnswer, Output: outputs}
+		if err != nil {
+			last = Event{Type: EventError, Output: outputs, Err: err}
+		}
+		select {
+		case events <- last:
+		case <-ctx.Done():
+		}
+	}()
+
+	return events
+}
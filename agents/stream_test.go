@@ -0,0 +1,146 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/agents"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/tools"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutorStream(t *testing.T) {
+	t.Parallel()
+
+	echo := funcTool{name: "echo", call: func(_ context.Context, input string) (string, error) {
+		return "echo " + input, nil
+	}}
+	llm := &scriptedLLM{turns: []*llms.ContentChoice{
+		{
+			ReasoningContent: "I should echo.",
+			Content:          "Echoing.",
+			ToolCalls:        []llms.ToolCall{toolCall("call_1", "echo", `{"__arg1":"hi"}`)},
+		},
+		{Content: "It said echo hi."},
+	}}
+	executor := agents.NewExecutor(agents.NewToolCallingAgent(llm, []tools.Tool{echo}))
+
+	var events []agents.Event
+	for event := range executor.Stream(t.Context(), map[string]any{"input": "echo hi"}) {
+		events = append(events, event)
+	}
+
+	types := make([]agents.EventType, len(events))
+	for i, event := range events {
+		types[i] = event.Type
+	}
+	require.Equal(t, []agents.EventType{
+		agents.EventReasoningToken,
+		agents.EventLLMToken,
+		agents.EventToolCallStarted,
+		agents.EventToolResult,
+		agents.EventStepFinished,
+		agents.EventLLMToken,
+		agents.EventStepFinished,
+		agents.EventFinalAnswer,
+	}, types)
+
+	first, second := events[0].StepID, events[5].StepID
+	require.NotEmpty(t, first)
+	require.NotEqual(t, first, second)
+	for _, event := range events[:5] {
+		require.Equal(t, first, event.StepID)
+		require.Equal(t, 0, event.Iteration)
+	}
+	require.Equal(t, 1, events[6].Iteration)
+
+	require.Equal(t, "I should echo.", events[0].Content)
+	require.Equal(t, "Echoing.", events[1].Content)
+	require.Equal(t, "call_1", events[2].ToolCallID)
+	require.Equal(t, "hi", events[2].Action.ToolInput)
+	require.Equal(t, "call_1", events[3].ToolCallID)
+	require.Equal(t, "echo hi", events[3].Steps[0].Observation)
+	require.Len(t, events[4].Steps, 1)
+	require.Equal(t, "It said echo hi.", events[7].Output["output"])
+}
+
+func TestExecutorStreamError(t *testing.T) {
+	t.Parallel()
+
+	errPlan := errors.New("model unavailable")
+	executor := agents.NewExecutor(&testAgent{err: errPlan})
+
+	var last agents.Event
+	for event := range executor.Stream(t.Context(), nil) {
+		last = event
+	}
+	require.Equal(t, agents.EventError, last.Type)
+	require.ErrorIs(t, last.Err, errPlan)
+}
+
+func TestExecutorStreamToolCallIDs(t *testing.T) {
+	t.Parallel()
+
+	a := &testAgent{
+		actions: []schema.AgentAction{{Tool: "echo", ToolInput: "a"}, {Tool: "echo", ToolInput: "b"}},
+		tools: []tools.Tool{funcTool{name: "echo", call: func(_ context.Context, input string) (string, error) {
+			return input, nil
+		}}},
+	}
+	executor := agents.NewExecutor(a, agents.WithMaxIterations(1))
+
+	ids := map[agents.EventType][]string{}
+	for event := range executor.Stream(t.Context(), nil) {
+		if event.ToolCallID != "" {
+			ids[event.Type] = append(ids[event.Type], event.ToolCallID)
+		}
+	}
+
+	// Actions without a tool ID are told apart by their position.
+	started := ids[agents.EventToolCallStarted]
+	require.Len(t, started, 2)
+	require.NotEqual(t, started[0], started[1])
+	require.Equal(t, started, ids[agents.EventToolResult])
+}
+
+func TestExecutorStreamNestedRun(t *testing.T) {
+	t.Parallel()
+
+	research := agents.NewAgentTool("research", "Finds facts.", answeringAgent("Go 1.0 was released in 2012."))
+	llm := &scriptedLLM{turns: []*llms.ContentChoice{
+		{ToolCalls: []llms.ToolCall{toolCall("call_1", "research", `{"__arg1":"When was Go 1.0 released?"}`)}},
+		{Content: "In 2012."},
+	}}
+	executor := agents.NewExecutor(agents.NewToolCallingAgent(llm, []tools.Tool{research}))
+
+	var outer, nested []agents.Event
+	for event := range executor.Stream(t.Context(), map[string]any{"input": "When was Go 1.0 released?"}) {
+		if event.ParentToolCallID == "" {
+			outer = append(outer, event)
+		} else {
+			nested = append(nested, event)
+		}
+	}
+
+	// The run of the agent tool is tagged with the call that started it, and
+	// does not change the iterations of the outer run.
+	require.Len(t, outer, 6)
+	require.Equal(t, agents.EventToolCallStarted, outer[0].Type)
+	require.Equal(t, agents.EventToolResult, outer[1].Type)
+	require.Equal(t, 1, outer[3].Iteration)
+	require.Equal(t, "In 2012.", outer[5].Output["output"])
+
+	require.Len(t, nested, 2)
+	require.Equal(t, agents.EventLLMToken, nested[0].Type)
+	require.Equal(t, "Go 1.0 was released in 2012.", nested[0].Content)
+	require.Equal(t, agents.EventStepFinished, nested[1].Type)
+	for _, event := range nested {
+		require.Equal(t, "call_1", event.ParentToolCallID)
+		require.Equal(t, 0, event.Iteration)
+		require.NotEqual(t, outer[0].StepID, event.StepID)
+	}
+}
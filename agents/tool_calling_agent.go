@@ -0,0 +1,278 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/sayerxofficial/langchaingo/callbacks"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/prompts"
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/tools"
+
+	"github.com/google/uuid"
+)
+
+// _toolArgument is the name of the single string argument of tools that do
+// not describe their own parameters.
+const _toolArgument = "__arg1"
+
+// ToolCallingAgent is an Agent that uses the native tool calling of the
+// model, through llms.WithTools and the tool calls of the response, instead
+// of parsing the text of the response. It works with every llms.Model that
+// supports tools.
+//
+// The intermediate steps are given back to the model as the tool calls it
+// made, grouped by the response that made them, followed by
+// llms.ToolCallResponse parts with the matching tool call IDs, so the model
+// can call tools over as many turns as it needs.
+type ToolCallingAgent struct {
+	// LLM is the model used by the agent.
+	LLM llms.Model
+	// Prompt formats the messages sent to the model before the tool calls.
+	Prompt prompts.ChatPromptTemplate
+	// Tools is a list of the tools the agent can use.
+	Tools []tools.Tool
+	// Output key is the key where the final output is placed.
+	OutputKey string
+	// CallbacksHandler is the handler for callbacks.
+	CallbacksHandler callbacks.Handler
+}
+
+var _ Agent = (*ToolCallingAgent)(nil)
+
+// NewToolCallingAgent creates a new ToolCallingAgent. The system message and
+// extra messages of the prompt are set with the options of OpenAIOption.
+func NewToolCallingAgent(llm llms.Model, tools []tools.Tool, opts ...Option) *ToolCallingAgent {
+	options := openAIFunctionsDefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &ToolCallingAgent{
+		LLM:              llm,
+		Prompt:           createToolCallingPrompt(options),
+		Tools:            tools,
+		OutputKey:        options.outputKey,
+		CallbacksHandler: options.callbacksHandler,
+	}
+}
+
+// Plan decides what action to take or returns the final result of the input.
+func (a *ToolCallingAgent) Plan(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	fullInputs := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		fullInputs[key] = value
+	}
+	chatMessages, err := a.Prompt.FormatMessages(fullInputs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	messages := make([]llms.MessageContent, 0, len(chatMessages)+2*len(intermediateSteps))
+	for _, msg := range chatMessages {
+		messages = append(messages, llms.TextParts(msg.GetType(), msg.GetContent()))
+	}
+	messages = append(messages, a.constructScratchPad(intermediateSteps)...)
+
+	options := []llms.CallOption{llms.WithTools(toolDefinitions(a.Tools))}
+	if stream := streamingFunc(ctx, a.CallbacksHandler); stream != nil {
+		options = append(options, llms.WithStreamingFunc(stream))
+	}
+
+	resp, err := a.LLM.GenerateContent(ctx, messages, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+	RecordUsage(ctx, resp.Usage)
+
+	return a.ParseOutput(resp)
+}
+
+// ParseOutput turns the tool calls of the first choice of the response into
+// actions. A response without tool calls is the final answer.
+func (a *ToolCallingAgent) ParseOutput(resp *llms.ContentResponse) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	if resp == nil || len(resp.Choices) == 0 {
+		return nil, nil, ErrAgentNoReturn
+	}
+	choice := resp.Choices[0]
+
+	turnID := uuid.New().String()
+	actions := make([]schema.AgentAction, 0, len(choice.ToolCalls))
+	for _, toolCall := range choice.ToolCalls {
+		if toolCall.FunctionCall == nil {
+			continue
+		}
+		id := toolCall.ID
+		if id == "" {
+			id = strings.ReplaceAll(uuid.New().String(), "-", "")
+		}
+		action := schema.AgentAction{
+			Tool:      toolCall.FunctionCall.Name,
+			ToolInput: toolInput(toolCall.FunctionCall.Arguments),
+			ToolID:    id,
+			TurnID:    turnID,
+		}
+		// The text of the response is kept once, with the first tool call.
+		if len(actions) == 0 {
+			action.Log = choice.Content
+		}
+		actions = append(actions, action)
+	}
+
+	if len(actions) == 0 {
+		return nil, &schema.AgentFinish{
+			ReturnValues: map[string]any{a.OutputKey: choice.Content},
+			Log:          choice.Content,
+		}, nil
+	}
+	return actions, nil, nil
+}
+
+func (a *ToolCallingAgent) GetInputKeys() []string {
+	return a.Prompt.GetInputVariables()
+}
+
+func (a *ToolCallingAgent) GetOutputKeys() []string {
+	return []string{a.OutputKey}
+}
+
+func (a *ToolCallingAgent) GetTools() []tools.Tool {
+	return a.Tools
+}
+
+// constructScratchPad gives the steps back to the model. The steps of the
+// actions planned by the same response are given as one AI message with all
+// their tool calls, followed by a tool message with the result of each.
+// Steps without an action, such as parsing errors, are given as a human
+// message.
+func (a *ToolCallingAgent) constructScratchPad(steps []schema.AgentStep) []llms.MessageContent {
+	nameToTool := getNameToTool(a.Tools)
+
+	messages := make([]llms.MessageContent, 0, 2*len(steps))
+	for i := 0; i < len(steps); {
+		if steps[i].Action.Tool == "" {
+			messages = append(messages, llms.TextParts(llms.ChatMessageTypeHuman, steps[i].Observation))
+			i++
+			continue
+		}
+
+		turn := steps[i : i+sameTurn(steps[i:])]
+		call := llms.MessageContent{Role: llms.ChatMessageTypeAI}
+		for _, step := range turn {
+			if step.Action.Log != "" {
+				call.Parts = append(call.Parts, llms.TextContent{Text: step.Action.Log})
+			}
+		}
+		for _, step := range turn {
+			call.Parts = append(call.Parts, llms.ToolCall{
+				ID:   step.Action.ToolID,
+				Type: "function",
+				FunctionCall: &llms.FunctionCall{
+					Name:      step.Action.Tool,
+					Arguments: toolArguments(nameToTool[strings.ToUpper(step.Action.Tool)], step.Action.ToolInput),
+				},
+			})
+		}
+		messages = append(messages, call)
+
+		for _, step := range turn {
+			messages = append(messages, llms.MessageContent{
+				Role: llms.ChatMessageTypeTool,
+				Parts: []llms.ContentPart{llms.ToolCallResponse{
+					ToolCallID: step.Action.ToolID,
+					Name:       step.Action.Tool,
+					Content:    step.Observation,
+				}},
+			})
+		}
+		i += len(turn)
+	}
+
+	return messages
+}
+
+// sameTurn returns the number of steps at the start of steps whose actions
+// were planned by the same response as the first one. Actions without a turn
+// ID are given one by one.
+func sameTurn(steps []schema.AgentStep) int {
+	turnID := steps[0].Action.TurnID
+	n := 1
+	for turnID != "" && n < len(steps) && steps[n].Action.Tool != "" && steps[n].Action.TurnID == turnID {
+		n++
+	}
+	return n
+}
+
+func createToolCallingPrompt(opts Options) prompts.ChatPromptTemplate {
+	messageFormatters := []prompts.MessageFormatter{prompts.NewSystemMessagePromptTemplate(opts.systemMessage, nil)}
+	messageFormatters = append(messageFormatters, opts.extraMessages...)
+	messageFormatters = append(messageFormatters, prompts.NewHumanMessagePromptTemplate("{{.input}}", []string{"input"}))
+
+	return prompts.NewChatPromptTemplate(messageFormatters)
+}
+
+// toolDefinitions describes the tools to the model. Tools that implement
+// tools.FunctionTool describe their own parameters, the others take a single
+// string argument.
+func toolDefinitions(ts []tools.Tool) []llms.Tool {
+	res := make([]llms.Tool, 0, len(ts))
+	for _, tool := range ts {
+		if ft, ok := tool.(tools.FunctionTool); ok {
+			def := ft.FunctionDefinition()
+			res = append(res, llms.Tool{
+				Type:     "function",
+				Function: &def,
+			})
+			continue
+		}
+		res = append(res, llms.Tool{
+			Type: "function",
+			Function: &llms.FunctionDefinition{
+				Name:        tool.Name(),
+				Description: tool.Description(),
+				Parameters: map[string]any{
+					"properties": map[string]any{
+						_toolArgument: map[string]string{"title": _toolArgument, "type": "string"},
+					},
+					"required": []string{_toolArgument},
+					"type":     "object",
+				},
+			},
+		})
+	}
+	return res
+}
+
+// toolInput returns the input for the tool from the arguments of a tool
+// call: the single string argument if there is one, the arguments as they
+// are otherwise.
+func toolInput(args string) string {
+	var argsMap map[string]any
+	if err := json.Unmarshal([]byte(args), &argsMap); err != nil {
+		return args
+	}
+	if arg, ok := argsMap[_toolArgument].(string); ok {
+		return arg
+	}
+	return args
+}
+
+// toolArguments is the reverse of toolInput: it returns the arguments of the
+// tool call that gave the input. The input of a tool without a schema is
+// always wrapped, even when it is a JSON object itself.
+func toolArguments(tool tools.Tool, input string) string {
+	if _, ok := tool.(tools.FunctionTool); ok {
+		return input
+	}
+	args, err := json.Marshal(map[string]string{_toolArgument: input})
+	if err != nil {
+		return input
+	}
+	return string(args)
+}
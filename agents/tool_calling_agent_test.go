@@ -0,0 +1,129 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/agents"
+	"github.com/sayerxofficial/langchaingo/chains"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/tools"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolCallingAgent(t *testing.T) {
+	t.Parallel()
+
+	type cityInput struct {
+		City string `json:"city"`
+	}
+	weather, err := tools.NewTyped("weather", "Returns the weather in a city.",
+		func(_ context.Context, in cityInput) (string, error) {
+			return "sunny in " + in.City, nil
+		})
+	require.NoError(t, err)
+
+	llm := &scriptedLLM{turns: []*llms.ContentChoice{
+		{
+			Content: "Let me check both cities.",
+			ToolCalls: []llms.ToolCall{
+				toolCall("call_1", "weather", `{"city":"Paris"}`),
+				toolCall("call_2", "weather", `{"city":"Lyon"}`),
+			},
+		},
+		{ToolCalls: []llms.ToolCall{toolCall("call_3", "calculator", `{"__arg1":"2+2"}`)}},
+		{Content: "Sunny in both, and 2+2 is 4."},
+	}}
+
+	a := agents.NewToolCallingAgent(llm, []tools.Tool{weather, tools.Calculator{}})
+	executor := agents.NewExecutor(a, agents.WithReturnIntermediateSteps())
+
+	result, err := chains.Call(t.Context(), executor, map[string]any{"input": "Weather in Paris and Lyon, and 2+2?"})
+	require.NoError(t, err)
+	require.Equal(t, "Sunny in both, and 2+2 is 4.", result["output"])
+
+	steps, ok := result["intermediateSteps"].([]schema.AgentStep)
+	require.True(t, ok)
+	require.Len(t, steps, 3)
+	require.Equal(t, "2+2", steps[2].Action.ToolInput)
+	require.Equal(t, "4", steps[2].Observation)
+
+	// The last call holds the whole conversation: system, human, then one AI
+	// message per response with its tool calls, followed by one tool message
+	// per call.
+	last := llm.messages[2]
+	require.Len(t, last, 7)
+	require.Equal(t, llms.ChatMessageTypeSystem, last[0].Role)
+	require.Equal(t, llms.ChatMessageTypeHuman, last[1].Role)
+
+	require.Equal(t, llms.ChatMessageTypeAI, last[2].Role)
+	require.Equal(t, []llms.ContentPart{
+		llms.TextContent{Text: "Let me check both cities."},
+		toolCall("call_1", "weather", `{"city":"Paris"}`),
+		toolCall("call_2", "weather", `{"city":"Lyon"}`),
+	}, last[2].Parts)
+	require.Equal(t, llms.MessageContent{
+		Role: llms.ChatMessageTypeTool,
+		Parts: []llms.ContentPart{llms.ToolCallResponse{
+			ToolCallID: "call_1", Name: "weather", Content: "sunny in Paris",
+		}},
+	}, last[3])
+	require.Equal(t, "call_2", last[4].Parts[0].(llms.ToolCallResponse).ToolCallID)
+	require.Equal(t, llms.ChatMessageTypeAI, last[5].Role)
+	require.Equal(t, []llms.ContentPart{toolCall("call_3", "calculator", `{"__arg1":"2+2"}`)}, last[5].Parts)
+	require.Equal(t, llms.ToolCallResponse{ToolCallID: "call_3", Name: "calculator", Content: "4"}, last[6].Parts[0])
+}
+
+func TestToolCallingAgentJSONStringInput(t *testing.T) {
+	t.Parallel()
+
+	// A tool without a schema takes its input as a string, even one holding a
+	// JSON object, so it goes back to the model as the string argument.
+	echo := funcTool{name: "echo", call: func(_ context.Context, input string) (string, error) {
+		return input, nil
+	}}
+	llm := &scriptedLLM{turns: []*llms.ContentChoice{
+		{ToolCalls: []llms.ToolCall{toolCall("call_1", "echo", `{"__arg1":"{\"a\":1}"}`)}},
+		{Content: "done"},
+	}}
+
+	executor := agents.NewExecutor(agents.NewToolCallingAgent(llm, []tools.Tool{echo}))
+	_, err := chains.Call(t.Context(), executor, map[string]any{"input": "Echo a JSON object."})
+	require.NoError(t, err)
+
+	last := llm.messages[1]
+	require.Equal(t, []llms.ContentPart{toolCall("call_1", "echo", `{"__arg1":"{\"a\":1}"}`)}, last[2].Parts)
+	require.Equal(t, `{"a":1}`, last[3].Parts[0].(llms.ToolCallResponse).Content)
+}
+
+func TestToolCallingAgentParseOutput(t *testing.T) {
+	t.Parallel()
+
+	a := agents.NewToolCallingAgent(nil, nil, agents.WithOutputKey("answer"))
+
+	_, finish, err := a.ParseOutput(&llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "done"}}})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"answer": "done"}, finish.ReturnValues)
+
+	actions, _, err := a.ParseOutput(&llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		ToolCalls: []llms.ToolCall{toolCall("", "search", `{"__arg1":"go"}`)},
+	}}})
+	require.NoError(t, err)
+	require.Len(t, actions, 1)
+	require.Equal(t, "go", actions[0].ToolInput)
+	require.NotEmpty(t, actions[0].ToolID)
+
+	// The actions of a response share its turn.
+	actions, _, err = a.ParseOutput(&llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		ToolCalls: []llms.ToolCall{toolCall("call_1", "search", `{"__arg1":"go"}`), toolCall("call_2", "search", `{}`)},
+	}}})
+	require.NoError(t, err)
+	require.Len(t, actions, 2)
+	require.NotEmpty(t, actions[0].TurnID)
+	require.Equal(t, actions[0].TurnID, actions[1].TurnID)
+
+	_, _, err = a.ParseOutput(&llms.ContentResponse{})
+	require.ErrorIs(t, err, agents.ErrAgentNoReturn)
+}
@@ -0,0 +1,49 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/agents"
+	"github.com/sayerxofficial/langchaingo/chains"
+	"github.com/sayerxofficial/langchaingo/jsonschema"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/tools"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIFunctionsAgentWithTypedTool(t *testing.T) {
+	t.Parallel()
+
+	type weatherInput struct {
+		City string `json:"city"`
+	}
+	var got weatherInput
+	weather, err := tools.NewTyped("weather", "Returns the weather in a city.",
+		func(_ context.Context, in weatherInput) (string, error) {
+			got = in
+			return "sunny", nil
+		})
+	require.NoError(t, err)
+
+	llm := &scriptedLLM{turns: []*llms.ContentChoice{
+		{ToolCalls: []llms.ToolCall{toolCall("call_1", "weather", `{"city":"Paris"}`)}},
+		{Content: "It is sunny"},
+	}}
+	executor := agents.NewExecutor(agents.NewOpenAIFunctionsAgent(llm, []tools.Tool{weather, tools.Calculator{}}))
+
+	result, err := chains.Run(t.Context(), executor, "What is the weather in Paris?")
+	require.NoError(t, err)
+	require.Equal(t, "It is sunny", result)
+	require.Equal(t, weatherInput{City: "Paris"}, got)
+
+	// The typed tool is offered with its own schema, other tools keep the
+	// single string argument.
+	offered := llm.tools[0]
+	require.Len(t, offered, 2)
+	params, ok := offered[0].Function.Parameters.(jsonschema.Definition)
+	require.True(t, ok)
+	require.Equal(t, []string{"city"}, params.Required)
+	require.Contains(t, offered[1].Function.Parameters, "properties")
+}
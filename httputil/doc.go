@@ -45,6 +45,20 @@
 //	    },
 //	}
 //
+// # Retries
+//
+// RetryTransport retries requests rejected with a rate limit or a server
+// error, and idempotent requests that failed with a transport error, using a
+// jittered exponential [Backoff] and honoring the Retry-After header sent by
+// the server up to the backoff's maximum delay:
+//
+//	client := &http.Client{
+//	    Transport: &httputil.RetryTransport{
+//	        Transport:  httputil.DefaultTransport,
+//	        MaxRetries: 5,
+//	    },
+//	}
+//
 // # Integration with httprr
 //
 // The transports in this package are designed to work with the httprr
@@ -0,0 +1,271 @@
+package httputil
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Backoff describes a jittered exponential backoff. The zero value is usable
+// and falls back to the defaults documented on each field.
+type Backoff struct {
+	// Initial is the delay before the first retry. Defaults to 500ms.
+	Initial time.Duration
+	// Max caps the delay between two attempts. Defaults to 30s.
+	Max time.Duration
+	// Multiplier is the factor the delay grows by after each attempt.
+	// Defaults to 2.
+	Multiplier float64
+	// Jitter is the fraction of the delay, between 0 and 1, that is
+	// randomized. Defaults to 0.2, and a negative value disables it.
+	Jitter float64
+}
+
+// DefaultBackoff is the backoff used when none is configured.
+var DefaultBackoff = Backoff{ //nolint:gochecknoglobals
+	Initial:    500 * time.Millisecond,
+	Max:        30 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.2,
+}
+
+// Delay returns the time to wait before the given retry. The first retry is
+// attempt 0.
+func (b Backoff) Delay(attempt int) time.Duration {
+	initial := b.Initial
+	if initial <= 0 {
+		initial = DefaultBackoff.Initial
+	}
+	maxDelay := b.MaxDelay()
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = DefaultBackoff.Multiplier
+	}
+	jitter := b.Jitter
+	switch {
+	case jitter == 0:
+		jitter = DefaultBackoff.Jitter
+	case jitter < 0:
+		jitter = 0
+	case jitter > 1:
+		jitter = 1
+	}
+
+	delay := float64(initial)
+	for i := 0; i < attempt && delay < float64(maxDelay); i++ {
+		delay *= multiplier
+	}
+	delay = min(delay, float64(maxDelay))
+
+	// Randomize the delay within [delay*(1-jitter), delay].
+	delay -= delay * jitter * rand.Float64() //nolint:gosec
+	return time.Duration(delay)
+}
+
+// MaxDelay returns the maximum delay between two attempts.
+func (b Backoff) MaxDelay() time.Duration {
+	if b.Max <= 0 {
+		return DefaultBackoff.Max
+	}
+	return b.Max
+}
+
+// ParseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or an HTTP date. It reports false if the header is empty
+// or malformed.
+func ParseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// RetryAfterError wraps the error returned for an HTTP response that asked
+// the client to wait before retrying with a Retry-After header.
+type RetryAfterError struct {
+	Err   error
+	Delay time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfter returns the delay requested by the server.
+func (e *RetryAfterError) RetryAfter() (time.Duration, bool) {
+	return e.Delay, true
+}
+
+// WithRetryAfter wraps err, returned for resp, in a [RetryAfterError] if resp
+// has a valid Retry-After header. Otherwise it returns err unchanged.
+func WithRetryAfter(err error, resp *http.Response) error {
+	if err == nil || resp == nil {
+		return err
+	}
+	delay, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if !ok {
+		return err
+	}
+	return &RetryAfterError{Err: err, Delay: delay}
+}
+
+// Sleep waits for d or until ctx is done, whichever happens first. It returns
+// the context error if ctx finished first.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// RetryTransport is an [http.RoundTripper] that retries requests rejected
+// with a rate limit or a server error, and idempotent requests that failed
+// with a transport error such as a connection reset. Retries use a jittered
+// exponential backoff, or the delay requested by the server in the
+// Retry-After header capped by the backoff's maximum delay, and stop as soon
+// as the request context is done.
+//
+// Retries happen before the response body is handed to the caller, so a
+// streamed response is never retried once the caller started reading it.
+// Requests with a body are only retried if the body can be recreated through
+// [http.Request.GetBody], which is always the case for requests built with
+// [http.NewRequest] from a bytes or strings reader.
+type RetryTransport struct {
+	// Transport is the underlying [http.RoundTripper] to use.
+	// If nil, [DefaultTransport] is used.
+	Transport http.RoundTripper
+	// MaxRetries is the maximum number of retries after the first attempt.
+	// Defaults to 3, and a negative value disables retries.
+	MaxRetries int
+	// Backoff computes the delay between attempts.
+	Backoff Backoff
+	// ShouldRetry reports whether a response should be retried. If nil,
+	// [IsRetryableStatus] is applied to the response status code.
+	ShouldRetry func(resp *http.Response) bool
+}
+
+// IsRetryableStatus reports whether an HTTP status code indicates a transient
+// failure: 408, 429 and the 5xx codes other than 501.
+func IsRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	case http.StatusNotImplemented:
+		return false
+	default:
+		return code >= http.StatusInternalServerError
+	}
+}
+
+// RoundTrip implements the [http.RoundTripper] interface.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = DefaultTransport
+	}
+	maxRetries := t.MaxRetries
+	switch {
+	case maxRetries == 0:
+		maxRetries = 3
+	case maxRetries < 0:
+		maxRetries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			var err error
+			if attemptReq, err = rewindRequest(req); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := transport.RoundTrip(attemptReq)
+		if attempt >= maxRetries || req.Context().Err() != nil {
+			return resp, err
+		}
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			// The body was consumed and can't be sent again.
+			return resp, err
+		}
+
+		var delay time.Duration
+		if err != nil {
+			if !isIdempotent(req) {
+				return nil, err
+			}
+			delay = t.Backoff.Delay(attempt)
+		} else {
+			if !t.shouldRetry(resp) {
+				return resp, nil
+			}
+			var ok bool
+			delay, ok = ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+			if ok {
+				delay = min(delay, t.Backoff.MaxDelay())
+			} else {
+				delay = t.Backoff.Delay(attempt)
+			}
+
+			// Drain the body so the connection can be reused.
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+			resp.Body.Close()
+		}
+
+		if err := Sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *RetryTransport) shouldRetry(resp *http.Response) bool {
+	if t.ShouldRetry != nil {
+		return t.ShouldRetry(resp)
+	}
+	return IsRetryableStatus(resp.StatusCode)
+}
+
+// isIdempotent reports whether a request can be sent again after a transport
+// error, which may happen after the server received it: its method is
+// idempotent, or it carries an idempotency key.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != "" || req.Header.Get("X-Idempotency-Key") != ""
+}
+
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	newReq := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		newReq.Body = body
+	}
+	return newReq, nil
+}
@@ -2,6 +2,9 @@
 // (nested) struct. This struct can be used with the chat completion "function call" feature.
 // For more complicated schemas, it is recommended to use a dedicated JSON schema library
 // and/or pass in the schema in []byte format.
+//
+// Reflect builds a Definition from a Go type, and Definition.Validate checks a JSON document
+// against it.
 package jsonschema
 
 import "encoding/json"
@@ -34,6 +37,12 @@ type Definition struct {
 	Required []string `json:"required,omitempty"`
 	// Items specifies which data type an array contains, if the schema type is Array.
 	Items *Definition `json:"items,omitempty"`
+	// AdditionalProperties describes the properties not listed in Properties, if the schema
+	// type is Object. It is either a bool or a Definition.
+	AdditionalProperties any `json:"additionalProperties,omitempty"`
+	// Nullable allows the value to be null in addition to its type. It is encoded as a
+	// ["<type>", "null"] type, or as an anyOf with a null schema if Enum is set.
+	Nullable bool `json:"-"`
 }
 
 func (d Definition) MarshalJSON() ([]byte, error) {
@@ -41,9 +50,85 @@ func (d Definition) MarshalJSON() ([]byte, error) {
 		d.Properties = make(map[string]Definition)
 	}
 	type Alias Definition
+	if d.Nullable && d.Type != "" && d.Type != Null {
+		if len(d.Enum) > 0 {
+			// The enum would reject null, so it goes in its own schema.
+			nonNull := d
+			nonNull.Nullable = false
+			nonNull.Description = ""
+			return json.Marshal(struct {
+				Description string       `json:"description,omitempty"`
+				AnyOf       []Definition `json:"anyOf"`
+			}{
+				Description: d.Description,
+				AnyOf:       []Definition{nonNull, {Type: Null}},
+			})
+		}
+		return json.Marshal(struct {
+			Alias
+			Type []DataType `json:"type"`
+		}{
+			Alias: (Alias)(d),
+			Type:  []DataType{d.Type, Null},
+		})
+	}
 	return json.Marshal(struct {
 		Alias
 	}{
 		Alias: (Alias)(d),
 	})
 }
+
+// UnmarshalJSON decodes a schema, including the nullable forms written by
+// MarshalJSON. A type listing several types other than null is decoded as an
+// empty Type, which accepts any value.
+func (d *Definition) UnmarshalJSON(data []byte) error {
+	type Alias Definition
+	var aux struct {
+		*Alias
+		Type  json.RawMessage `json:"type,omitempty"`
+		AnyOf []Definition    `json:"anyOf,omitempty"`
+	}
+	aux.Alias = (*Alias)(d)
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if len(aux.Type) > 0 {
+		var types []DataType
+		if err := json.Unmarshal(aux.Type, &types); err != nil {
+			var t DataType
+			if err := json.Unmarshal(aux.Type, &t); err != nil {
+				return err
+			}
+			types = []DataType{t}
+		}
+		d.Type = ""
+		var nonNull []DataType
+		for _, t := range types {
+			if t == Null && len(types) > 1 {
+				d.Nullable = true
+				continue
+			}
+			nonNull = append(nonNull, t)
+		}
+		if len(nonNull) == 1 {
+			d.Type = nonNull[0]
+		}
+	}
+
+	if len(aux.AnyOf) == 2 && d.Type == "" {
+		for i, schema := range aux.AnyOf {
+			if schema.Type == Null {
+				description := d.Description
+				*d = aux.AnyOf[1-i]
+				d.Nullable = true
+				if d.Description == "" {
+					d.Description = description
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
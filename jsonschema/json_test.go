@@ -211,3 +211,67 @@ func structToMap(t *testing.T, v any) map[string]any {
 	}
 	return got
 }
+
+func TestDefinition_Nullable(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		def  jsonschema.Definition
+		want string
+	}{
+		{
+			name: "type",
+			def:  jsonschema.Definition{Type: jsonschema.String, Nullable: true},
+			want: `{"type":["string","null"],"properties":{}}`,
+		},
+		{
+			name: "enum",
+			def: jsonschema.Definition{
+				Type:        jsonschema.String,
+				Description: "a color",
+				Enum:        []string{"red", "blue"},
+				Nullable:    true,
+			},
+			want: `{"description":"a color","anyOf":[
+				{"type":"string","enum":["red","blue"],"properties":{}},
+				{"type":"null","properties":{}}
+			]}`,
+		},
+		{
+			name: "any",
+			def:  jsonschema.Definition{Nullable: true},
+			want: `{"properties":{}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var want map[string]any
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatalf("Failed to Unmarshal JSON: error = %v", err)
+			}
+			if got := structToMap(t, tt.def); !reflect.DeepEqual(got, want) {
+				t.Errorf("MarshalJSON() got = %v, want %v", got, want)
+			}
+
+			got, err := json.Marshal(tt.def)
+			if err != nil {
+				t.Fatalf("Failed to Marshal JSON: error = %v", err)
+			}
+
+			var decoded jsonschema.Definition
+			if err := json.Unmarshal(got, &decoded); err != nil {
+				t.Fatalf("Failed to unmarshal JSON: %v", err)
+			}
+			wantDef := tt.def
+			if wantDef.Type == "" {
+				wantDef.Nullable = false
+			}
+			wantDef.Properties = map[string]jsonschema.Definition{}
+			if !reflect.DeepEqual(decoded, wantDef) {
+				t.Errorf("UnmarshalJSON() got = %+v, want %+v", decoded, wantDef)
+			}
+		})
+	}
+}
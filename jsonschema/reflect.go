@@ -0,0 +1,179 @@
+package jsonschema
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrUnsupportedType is returned by Reflect for Go types that have no JSON
+// representation, such as channels and functions.
+var ErrUnsupportedType = errors.New("jsonschema: unsupported type")
+
+//nolint:gochecknoglobals
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// Reflect returns the Definition of the JSON encoding of v, which is usually
+// a struct value or a pointer to one. Struct fields are described by their
+// tags:
+//
+//   - json: the property name, following the encoding/json rules. Fields
+//     tagged omitempty are optional, all other fields are required.
+//   - description: the description of the property. The describe tag used by
+//     outputparser.Defined is accepted as well.
+//   - enum: a comma separated list of allowed values.
+//
+// Pointers are nullable, maps are objects whose additional properties are
+// described by the map values, and recursive types are described as plain
+// objects past the first level.
+func Reflect(v any) (Definition, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return Definition{}, fmt.Errorf("%w: nil", ErrUnsupportedType)
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	r := reflector{visiting: make(map[reflect.Type]bool)}
+	return r.reflect(t)
+}
+
+type reflector struct {
+	visiting map[reflect.Type]bool
+}
+
+func (r reflector) reflect(t reflect.Type) (Definition, error) { //nolint:cyclop
+	if t == timeType {
+		return Definition{Type: String}, nil
+	}
+	if t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(textMarshalerType) {
+		return Definition{Type: String}, nil
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Pointer:
+		def, err := r.reflect(t.Elem())
+		def.Nullable = true
+		return def, err
+	case reflect.Bool:
+		return Definition{Type: Boolean}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Definition{Type: Integer}, nil
+	case reflect.Float32, reflect.Float64:
+		return Definition{Type: Number}, nil
+	case reflect.String:
+		return Definition{Type: String}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			// []byte is encoded as a base64 string.
+			return Definition{Type: String}, nil
+		}
+		items, err := r.reflect(t.Elem())
+		if err != nil {
+			return Definition{}, err
+		}
+		return Definition{Type: Array, Items: &items}, nil
+	case reflect.Map:
+		values, err := r.reflect(t.Elem())
+		if err != nil {
+			return Definition{}, err
+		}
+		def := Definition{Type: Object}
+		if values.Type != "" {
+			def.AdditionalProperties = values
+		}
+		return def, nil
+	case reflect.Struct:
+		return r.reflectStruct(t)
+	case reflect.Interface:
+		// Any JSON value.
+		return Definition{}, nil
+	default:
+		return Definition{}, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+	}
+}
+
+func (r reflector) reflectStruct(t reflect.Type) (Definition, error) {
+	if r.visiting[t] {
+		return Definition{Type: Object}, nil
+	}
+	def := Definition{
+		Type:       Object,
+		Properties: make(map[string]Definition),
+	}
+	r.visiting[t] = true
+	defer delete(r.visiting, t)
+
+	if err := r.addFields(&def, t); err != nil {
+		return Definition{}, err
+	}
+	return def, nil
+}
+
+// addFields adds the fields of struct type t to def. Fields of embedded
+// structs without a json name are promoted, as encoding/json does.
+func (r reflector) addFields(def *Definition, t reflect.Type) error {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if err := r.addFields(def, ft); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop, err := r.reflect(field.Type)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if description := field.Tag.Get("description"); description != "" {
+			prop.Description = description
+		} else if describe := field.Tag.Get("describe"); describe != "" {
+			prop.Description = describe
+		}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			prop.Enum = strings.Split(enum, ",")
+		}
+
+		def.Properties[name] = prop
+		if !hasOption(opts, "omitempty") && !hasOption(opts, "omitzero") {
+			def.Required = append(def.Required, name)
+		}
+	}
+	return nil
+}
+
+func hasOption(opts, option string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
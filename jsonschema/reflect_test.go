@@ -0,0 +1,169 @@
+package jsonschema_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sayerxofficial/langchaingo/jsonschema"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city" description:"the city name"`
+}
+
+type Base struct {
+	ID string `json:"id"`
+}
+
+type Node struct {
+	Value    int     `json:"value"`
+	Children []*Node `json:"children,omitempty"`
+}
+
+type Person struct {
+	Base
+	Name     string            `json:"name" describe:"full name"`
+	Age      int               `json:"age,omitempty"`
+	Role     string            `json:"role" enum:"admin,user"`
+	Score    float64           `json:"score"`
+	Active   bool              `json:"active"`
+	Address  Address           `json:"address"`
+	Previous *Address          `json:"previous,omitempty"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Born     time.Time         `json:"born"`
+	Extra    any               `json:"extra,omitempty"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+func TestReflect(t *testing.T) {
+	t.Parallel()
+
+	def, err := jsonschema.Reflect(&Person{})
+	require.NoError(t, err)
+
+	assert.Equal(t, jsonschema.Object, def.Type)
+	assert.Equal(t, []string{"id", "name", "role", "score", "active", "address", "tags", "born"}, def.Required)
+	assert.NotContains(t, def.Properties, "Ignored")
+	assert.NotContains(t, def.Properties, "internal")
+
+	assert.Equal(t, jsonschema.Definition{Type: jsonschema.String}, def.Properties["id"])
+	assert.Equal(t, jsonschema.Definition{Type: jsonschema.String, Description: "full name"}, def.Properties["name"])
+	assert.Equal(t, jsonschema.Integer, def.Properties["age"].Type)
+	assert.Equal(t, []string{"admin", "user"}, def.Properties["role"].Enum)
+	assert.Equal(t, jsonschema.Number, def.Properties["score"].Type)
+	assert.Equal(t, jsonschema.Boolean, def.Properties["active"].Type)
+	assert.Equal(t, jsonschema.String, def.Properties["born"].Type)
+	assert.Equal(t, jsonschema.Definition{}, def.Properties["extra"])
+
+	address := def.Properties["address"]
+	assert.Equal(t, jsonschema.Object, address.Type)
+	assert.Equal(t, []string{"street", "city"}, address.Required)
+	assert.Equal(t, "the city name", address.Properties["city"].Description)
+
+	previous := def.Properties["previous"]
+	assert.True(t, previous.Nullable)
+	assert.Equal(t, address.Properties, previous.Properties)
+
+	assert.Equal(t, jsonschema.Array, def.Properties["tags"].Type)
+	assert.Equal(t, jsonschema.String, def.Properties["tags"].Items.Type)
+
+	labels := def.Properties["labels"]
+	assert.Equal(t, jsonschema.Object, labels.Type)
+	assert.Equal(t, jsonschema.Definition{Type: jsonschema.String}, labels.AdditionalProperties)
+}
+
+func TestReflect_Recursive(t *testing.T) {
+	t.Parallel()
+
+	def, err := jsonschema.Reflect(Node{})
+	require.NoError(t, err)
+
+	children := def.Properties["children"]
+	assert.Equal(t, jsonschema.Array, children.Type)
+	assert.Equal(t, jsonschema.Object, children.Items.Type)
+	assert.True(t, children.Items.Nullable)
+	assert.Empty(t, children.Items.Properties)
+}
+
+func TestReflect_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	_, err := jsonschema.Reflect(struct {
+		C chan int `json:"c"`
+	}{})
+	require.ErrorIs(t, err, jsonschema.ErrUnsupportedType)
+
+	_, err = jsonschema.Reflect(nil)
+	require.ErrorIs(t, err, jsonschema.ErrUnsupportedType)
+}
+
+func TestDefinition_Validate(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	def, err := jsonschema.Reflect(Person{})
+	require.NoError(t, err)
+
+	valid := `{
+		"id": "1", "name": "Ada", "role": "admin", "score": 9.5, "active": true,
+		"address": {"street": "Main St", "city": "London"},
+		"previous": null,
+		"tags": ["math"],
+		"labels": {"team": "engines"},
+		"born": "1815-12-10T00:00:00Z",
+		"unknown": 1
+	}`
+	require.NoError(t, def.Validate([]byte(valid)))
+
+	tests := []struct {
+		name string
+		data string
+		path string
+	}{
+		{"not an object", `[]`, "$"},
+		{"missing required", `{"id": "1"}`, "$"},
+		{"wrong type", `{"id": 1, "name": "Ada", "role": "user", "score": 1, "active": true,
+			"address": {"street": "", "city": ""}, "tags": [], "born": ""}`, "$.id"},
+		{"enum", `{"id": "1", "name": "Ada", "role": "root", "score": 1, "active": true,
+			"address": {"street": "", "city": ""}, "tags": [], "born": ""}`, "$.role"},
+		{"nested", `{"id": "1", "name": "Ada", "role": "user", "score": 1, "active": true,
+			"address": {"street": ""}, "tags": [], "born": ""}`, "$.address"},
+		{"array item", `{"id": "1", "name": "Ada", "role": "user", "score": 1, "active": true,
+			"address": {"street": "", "city": ""}, "tags": ["a", 2], "born": ""}`, "$.tags[1]"},
+		{"map value", `{"id": "1", "name": "Ada", "role": "user", "score": 1, "active": true,
+			"address": {"street": "", "city": ""}, "tags": [], "born": "", "labels": {"a": 1}}`, "$.labels.a"},
+		{"null", `{"id": "1", "name": null, "role": "user", "score": 1, "active": true,
+			"address": {"street": "", "city": ""}, "tags": [], "born": ""}`, "$.name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := def.Validate([]byte(tt.data))
+			var verr *jsonschema.ValidationError
+			require.True(t, errors.As(err, &verr), "got %v", err)
+			assert.Equal(t, tt.path, verr.Path)
+		})
+	}
+
+	require.Error(t, def.Validate([]byte(`{`)))
+}
+
+func TestDefinition_ValidateIntegerAndClosedObject(t *testing.T) {
+	t.Parallel()
+
+	def := jsonschema.Definition{
+		Type:                 jsonschema.Object,
+		Properties:           map[string]jsonschema.Definition{"n": {Type: jsonschema.Integer}},
+		AdditionalProperties: false,
+	}
+	require.NoError(t, def.Validate([]byte(`{"n": 3}`)))
+	require.Error(t, def.Validate([]byte(`{"n": 3.5}`)))
+	require.Error(t, def.Validate([]byte(`{"n": 3, "m": 1}`)))
+}
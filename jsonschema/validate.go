@@ -0,0 +1,143 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// ValidationError reports where a JSON document does not match a Definition.
+type ValidationError struct {
+	// Path locates the invalid value, such as "$.items[2].name".
+	Path string
+	// Message describes the problem.
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// Validate checks that data is a JSON document matching the definition. It
+// returns a *ValidationError for the first mismatch found, or an error from
+// encoding/json if data is not valid JSON. Properties not listed in the
+// definition are accepted unless AdditionalProperties is false.
+func (d Definition) Validate(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return d.validate(v, "$")
+}
+
+func (d Definition) validate(v any, path string) error { //nolint:cyclop
+	invalid := func(format string, args ...any) error {
+		return &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if v == nil {
+		if d.Type == "" || d.Type == Null || d.Nullable {
+			return nil
+		}
+		return invalid("expected %s, got null", d.Type)
+	}
+
+	switch d.Type {
+	case Object:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return invalid("expected object, got %s", typeOf(v))
+		}
+		return d.validateObject(obj, path)
+	case Array:
+		arr, ok := v.([]any)
+		if !ok {
+			return invalid("expected array, got %s", typeOf(v))
+		}
+		if d.Items != nil {
+			for i, item := range arr {
+				if err := d.Items.validate(item, path+"["+strconv.Itoa(i)+"]"); err != nil {
+					return err
+				}
+			}
+		}
+	case String:
+		s, ok := v.(string)
+		if !ok {
+			return invalid("expected string, got %s", typeOf(v))
+		}
+		if len(d.Enum) > 0 && !slices.Contains(d.Enum, s) {
+			return invalid("%q is not one of %q", s, d.Enum)
+		}
+	case Integer:
+		n, ok := v.(float64)
+		if !ok || n != math.Trunc(n) {
+			return invalid("expected integer, got %s", typeOf(v))
+		}
+	case Number:
+		if _, ok := v.(float64); !ok {
+			return invalid("expected number, got %s", typeOf(v))
+		}
+	case Boolean:
+		if _, ok := v.(bool); !ok {
+			return invalid("expected boolean, got %s", typeOf(v))
+		}
+	case Null:
+		return invalid("expected null, got %s", typeOf(v))
+	}
+	return nil
+}
+
+func (d Definition) validateObject(obj map[string]any, path string) error {
+	for _, name := range d.Required {
+		if _, ok := obj[name]; !ok {
+			return &ValidationError{Path: path, Message: fmt.Sprintf("missing required property %q", name)}
+		}
+	}
+	// Sorted, so that the same document always reports the same error.
+	for _, name := range slices.Sorted(maps.Keys(obj)) {
+		value := obj[name]
+		prop, ok := d.Properties[name]
+		if ok {
+			if err := prop.validate(value, path+"."+name); err != nil {
+				return err
+			}
+			continue
+		}
+		switch additional := d.AdditionalProperties.(type) {
+		case bool:
+			if !additional {
+				return &ValidationError{Path: path, Message: fmt.Sprintf("unexpected property %q", name)}
+			}
+		case Definition:
+			if err := additional.validate(value, path+"."+name); err != nil {
+				return err
+			}
+		case *Definition:
+			if err := additional.validate(value, path+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func typeOf(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strings"
 
 	"github.com/sayerxofficial/langchaingo/callbacks"
 	"github.com/sayerxofficial/langchaingo/httputil"
@@ -35,7 +36,10 @@ type LLM struct {
 	client           *anthropicclient.Client
 }
 
-var _ llms.Model = (*LLM)(nil)
+var (
+	_ llms.Model                 = (*LLM)(nil)
+	_ llms.StructuredOutputModel = (*LLM)(nil)
+)
 
 // New returns a new Anthropic LLM.
 func New(opts ...Option) (*LLM, error) {
@@ -75,6 +79,19 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 	return llms.GenerateFromSinglePrompt(ctx, o, prompt, options...)
 }
 
+// responseSchemaInstruction asks the model to answer with the tool of a
+// response schema when the tool can't be forced.
+const responseSchemaInstruction = "Answer by calling the %s tool, with your answer as its input."
+
+// SupportsStructuredOutput reports whether the model honors
+// llms.WithResponseSchema. The messages API enforces the schema by forcing
+// the model to call a tool whose input is the schema, or with extended
+// thinking, which can't be combined with a forced tool, by instructing it to
+// call the tool; the legacy text completions API has no structured output.
+func (o *LLM) SupportsStructuredOutput() bool {
+	return !o.client.UseLegacyTextCompletionsAPI
+}
+
 // GenerateContent implements the Model interface.
 func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
 	if o.CallbacksHandler != nil {
@@ -164,6 +181,23 @@ func generateMessagesContent(ctx context.Context, o *LLM, messages []llms.Messag
 	}
 
 	tools := toolsToTools(opts.Tools)
+	toolChoice := opts.ToolChoice
+	if rs := opts.ResponseSchema; rs != nil {
+		// Anthropic has no response format, so the schema becomes the input
+		// of a tool the model is forced to call.
+		tools = append(tools, anthropicclient.Tool{
+			Name:        rs.Name,
+			Description: rs.Description,
+			InputSchema: rs.Schema,
+		})
+		toolChoice = map[string]any{"type": "tool", "name": rs.Name}
+		if thinking != nil {
+			// Forcing a tool is refused with extended thinking, so the model
+			// is only asked to call it.
+			toolChoice = map[string]any{"type": "auto"}
+			systemPrompt = strings.TrimSpace(systemPrompt + "\n\n" + fmt.Sprintf(responseSchemaInstruction, rs.Name))
+		}
+	}
 	result, err := o.client.CreateMessage(ctx, &anthropicclient.MessageRequest{
 		Model:         opts.Model,
 		Messages:      chatMessages,
@@ -173,7 +207,7 @@ func generateMessagesContent(ctx context.Context, o *LLM, messages []llms.Messag
 		Temperature:   opts.Temperature,
 		TopP:          opts.TopP,
 		Tools:         tools,
-		ToolChoice:    opts.ToolChoice,
+		ToolChoice:    toolChoice,
 		Thinking:      thinking,
 		StreamingFunc: opts.StreamingFunc,
 	})
@@ -220,6 +254,19 @@ func generateMessagesContent(ctx context.Context, o *LLM, messages []llms.Messag
 				if err != nil {
 					return nil, fmt.Errorf("anthropic: failed to marshal tool use arguments: %w", err)
 				}
+				if opts.ResponseSchema != nil && toolUseContent.Name == opts.ResponseSchema.Name {
+					// The input of the forced tool call is the structured output.
+					choices = append(choices, &llms.ContentChoice{
+						Content:          string(argumentsJSON),
+						ReasoningContent: reasoningContent,
+						StopReason:       result.StopReason,
+						GenerationInfo: map[string]any{
+							"InputTokens":  result.Usage.InputTokens,
+							"OutputTokens": result.Usage.OutputTokens,
+						},
+					})
+					continue
+				}
 				choices = append(choices, &llms.ContentChoice{
 					ReasoningContent: reasoningContent,
 					ToolCalls: []llms.ToolCall{
@@ -252,10 +299,23 @@ func generateMessagesContent(ctx context.Context, o *LLM, messages []llms.Messag
 
 	resp := &llms.ContentResponse{
 		Choices: choices,
+		Usage:   usageFromResponse(result),
 	}
 	return resp, nil
 }
 
+// usageFromResponse converts the usage reported by the messages API. Anthropic
+// reports cached prompt tokens separately from InputTokens, so they are added
+// back in to keep InputTokens the full prompt size.
+func usageFromResponse(result *anthropicclient.MessageResponsePayload) *llms.Usage {
+	cached := result.Usage.CacheReadInputTokens
+	return &llms.Usage{
+		InputTokens:  result.Usage.InputTokens + result.Usage.CacheCreationInputTokens + cached,
+		OutputTokens: result.Usage.OutputTokens,
+		CachedTokens: cached,
+	}
+}
+
 func toolsToTools(tools []llms.Tool) []anthropicclient.Tool {
 	toolReq := make([]anthropicclient.Tool, len(tools))
 	for i, tool := range tools {
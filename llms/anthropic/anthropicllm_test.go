@@ -1,10 +1,16 @@
 package anthropic
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/llms/anthropic/internal/anthropicclient"
 )
 
 func TestNew(t *testing.T) {
@@ -225,3 +231,157 @@ func TestCall(t *testing.T) {
 	// Test that Call delegates to GenerateContent
 	t.Skip("Call() requires integration testing with mock client")
 }
+
+func TestGenerateContentResponseSchema(t *testing.T) {
+	t.Parallel()
+
+	var request map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"id": "msg_1", "type": "message", "role": "assistant", "model": "claude",
+			"stop_reason": "tool_use",
+			"content": [{"type": "tool_use", "id": "toolu_1", "name": "answer", "input": {"city": "Paris"}}],
+			"usage": {"input_tokens": 10, "output_tokens": 5}
+		}`)
+	}))
+	defer server.Close()
+
+	llm, err := New(WithToken("test-token"), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !llm.SupportsStructuredOutput() {
+		t.Fatal("expected structured output support")
+	}
+
+	resp, err := llm.GenerateContent(t.Context(),
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Capital of France?")},
+		llms.WithResponseSchema(&llms.ResponseSchema{
+			Name: "answer",
+			Schema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"city": map[string]any{"type": "string"}},
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The schema is sent as a tool the model is forced to call...
+	tools, _ := request["tools"].([]any)
+	if len(tools) != 1 || tools[0].(map[string]any)["name"] != "answer" {
+		t.Errorf("tools = %v, want the answer tool", request["tools"])
+	}
+	wantChoice := map[string]any{"type": "tool", "name": "answer"}
+	if !reflect.DeepEqual(request["tool_choice"], wantChoice) {
+		t.Errorf("tool_choice = %v, want %v", request["tool_choice"], wantChoice)
+	}
+
+	// ...and its input comes back as the content.
+	if got := resp.Choices[0].Content; got != `{"city":"Paris"}` {
+		t.Errorf("content = %q, want the tool input", got)
+	}
+	if len(resp.Choices[0].ToolCalls) != 0 {
+		t.Errorf("tool calls = %v, want none", resp.Choices[0].ToolCalls)
+	}
+}
+
+func TestGenerateContentResponseSchemaWithThinking(t *testing.T) {
+	t.Parallel()
+
+	var request map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"id": "msg_1", "type": "message", "role": "assistant", "model": "claude",
+			"stop_reason": "tool_use",
+			"content": [
+				{"type": "thinking", "thinking": "The capital of France is Paris.", "signature": "sig"},
+				{"type": "tool_use", "id": "toolu_1", "name": "answer", "input": {"city": "Paris"}}
+			],
+			"usage": {"input_tokens": 10, "output_tokens": 5}
+		}`)
+	}))
+	defer server.Close()
+
+	llm, err := New(WithToken("test-token"), WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := llm.GenerateContent(t.Context(),
+		[]llms.MessageContent{
+			llms.TextParts(llms.ChatMessageTypeSystem, "You are a geographer."),
+			llms.TextParts(llms.ChatMessageTypeHuman, "Capital of France?"),
+		},
+		llms.WithReasoning(llms.ReasoningNone, 2048),
+		llms.WithResponseSchema(&llms.ResponseSchema{
+			Name:   "answer",
+			Schema: map[string]any{"type": "object"},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Extended thinking refuses a forced tool, so the model is asked to call
+	// it instead.
+	wantChoice := map[string]any{"type": "auto"}
+	if !reflect.DeepEqual(request["tool_choice"], wantChoice) {
+		t.Errorf("tool_choice = %v, want %v", request["tool_choice"], wantChoice)
+	}
+	wantSystem := "You are a geographer.\n\nAnswer by calling the answer tool, with your answer as its input."
+	if request["system"] != wantSystem {
+		t.Errorf("system = %q, want %q", request["system"], wantSystem)
+	}
+	if got := resp.Choices[0].Content; got != `{"city":"Paris"}` {
+		t.Errorf("content = %q, want the tool input", got)
+	}
+}
+
+func TestUsageFromResponse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		usage string
+		want  *llms.Usage
+	}{
+		{
+			name:  "no cache",
+			usage: `{"input_tokens": 10, "output_tokens": 5}`,
+			want:  &llms.Usage{InputTokens: 10, OutputTokens: 5},
+		},
+		{
+			name:  "cache write",
+			usage: `{"input_tokens": 10, "output_tokens": 5, "cache_creation_input_tokens": 100}`,
+			want:  &llms.Usage{InputTokens: 110, OutputTokens: 5},
+		},
+		{
+			name:  "cache read",
+			usage: `{"input_tokens": 10, "output_tokens": 5, "cache_read_input_tokens": 100}`,
+			want:  &llms.Usage{InputTokens: 110, OutputTokens: 5, CachedTokens: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var result anthropicclient.MessageResponsePayload
+			if err := json.Unmarshal([]byte(`{"usage": `+tt.usage+`}`), &result); err != nil {
+				t.Fatal(err)
+			}
+			if got := usageFromResponse(&result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("usageFromResponse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
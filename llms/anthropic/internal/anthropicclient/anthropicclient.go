@@ -210,7 +210,7 @@ func (c *Client) decodeError(resp *http.Response) error {
 
 	var errResp errorMessage
 	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
-		return errors.New(msg)
+		return httputil.WithRetryAfter(errors.New(msg), resp)
 	}
-	return fmt.Errorf("%s: %s", msg, errResp.Error.Message)
+	return httputil.WithRetryAfter(fmt.Errorf("%s: %s", msg, errResp.Error.Message), resp)
 }
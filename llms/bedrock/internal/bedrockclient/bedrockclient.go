@@ -3,11 +3,15 @@ package bedrockclient
 import (
 	"context"
 	"errors"
+	"strconv"
 	"strings"
 
 	"github.com/sayerxofficial/langchaingo/llms"
 
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 )
 
 // Client is a Bedrock client.
@@ -106,3 +110,25 @@ func getMaxTokens(maxTokens, defaultValue int) int {
 	}
 	return maxTokens
 }
+
+// usageFromHeaders reads the token counts that Bedrock reports in the
+// X-Amzn-Bedrock-*-Token-Count response headers. It returns nil if the raw
+// response is not available or the headers are missing.
+func usageFromHeaders(metadata middleware.Metadata) *llms.Usage {
+	raw, ok := awsmiddleware.GetRawResponse(metadata).(*smithyhttp.Response)
+	if !ok || raw == nil {
+		return nil
+	}
+	input, err := strconv.Atoi(raw.Header.Get("X-Amzn-Bedrock-Input-Token-Count"))
+	if err != nil {
+		return nil
+	}
+	output, err := strconv.Atoi(raw.Header.Get("X-Amzn-Bedrock-Output-Token-Count"))
+	if err != nil {
+		return nil
+	}
+	return &llms.Usage{
+		InputTokens:  input,
+		OutputTokens: output,
+	}
+}
@@ -1,14 +1,19 @@
 package bedrockclient
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
 	"testing"
 
 	"github.com/sayerxofficial/langchaingo/llms"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/stretchr/testify/require"
 )
 
@@ -860,3 +865,52 @@ func TestBedrockRequestStructures(t *testing.T) {
 		require.Equal(t, []byte(`{"prompt": "Stream this"}`), input.Body)
 	})
 }
+
+func TestUsageFromHeaders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    *llms.Usage
+	}{
+		{
+			name: "no headers",
+		},
+		{
+			name:    "missing output count",
+			headers: map[string]string{"X-Amzn-Bedrock-Input-Token-Count": "10"},
+		},
+		{
+			name: "token counts",
+			headers: map[string]string{
+				"X-Amzn-Bedrock-Input-Token-Count":  "10",
+				"X-Amzn-Bedrock-Output-Token-Count": "5",
+			},
+			want: &llms.Usage{InputTokens: 10, OutputTokens: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			raw := &smithyhttp.Response{Response: &http.Response{Header: http.Header{}}}
+			for k, v := range tt.headers {
+				raw.Header.Set(k, v)
+			}
+			// The raw response is recorded in the metadata by the AWS
+			// middleware, as it is for real calls.
+			_, metadata, err := awsmiddleware.AddRawResponse{}.HandleDeserialize(t.Context(),
+				middleware.DeserializeInput{},
+				middleware.DeserializeHandlerFunc(func(context.Context, middleware.DeserializeInput) (
+					middleware.DeserializeOutput, middleware.Metadata, error,
+				) {
+					return middleware.DeserializeOutput{RawResponse: raw}, middleware.Metadata{}, nil
+				}))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, usageFromHeaders(metadata))
+		})
+	}
+
+	require.Nil(t, usageFromHeaders(middleware.Metadata{}))
+}
@@ -195,6 +195,10 @@ func createAnthropicCompletion(ctx context.Context,
 	}
 	return &llms.ContentResponse{
 		Choices: Contentchoices,
+		Usage: &llms.Usage{
+			InputTokens:  output.Usage.InputTokens,
+			OutputTokens: output.Usage.OutputTokens,
+		},
 	}, nil
 }
 
@@ -244,6 +248,7 @@ func parseStreamingCompletionResponse(ctx context.Context, client *bedrockruntim
 	defer streaming.CallWithDone(ctx, options.StreamingFunc) //nolint:errcheck
 
 	contentchoices := []*llms.ContentChoice{{GenerationInfo: map[string]interface{}{}}}
+	usage := &llms.Usage{}
 	for e := range stream.Events() {
 		if err = stream.Err(); err != nil {
 			return nil, err
@@ -259,6 +264,7 @@ func parseStreamingCompletionResponse(ctx context.Context, client *bedrockruntim
 			switch resp.Type {
 			case "message_start":
 				contentchoices[0].GenerationInfo["input_tokens"] = resp.Message.Usage.InputTokens
+				usage.InputTokens = resp.Message.Usage.InputTokens
 			case "content_block_delta":
 				if err = streaming.CallWithText(ctx, options.StreamingFunc, resp.Delta.Text); err != nil {
 					return nil, err
@@ -267,6 +273,7 @@ func parseStreamingCompletionResponse(ctx context.Context, client *bedrockruntim
 			case "message_delta":
 				contentchoices[0].StopReason = resp.Delta.StopReason
 				contentchoices[0].GenerationInfo["output_tokens"] = resp.Usage.OutputTokens
+				usage.OutputTokens = resp.Usage.OutputTokens
 			}
 		}
 	}
@@ -276,6 +283,7 @@ func parseStreamingCompletionResponse(ctx context.Context, client *bedrockruntim
 
 	return &llms.ContentResponse{
 		Choices: contentchoices,
+		Usage:   usage,
 	}, nil
 }
 
@@ -118,5 +118,8 @@ func createCohereCompletion(ctx context.Context,
 
 	return &llms.ContentResponse{
 		Choices: choices,
+		// Cohere models don't report usage in the body, so fall back to the
+		// token counts Bedrock sends in the response headers.
+		Usage: usageFromHeaders(resp.ResultMetadata),
 	}, nil
 }
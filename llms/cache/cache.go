@@ -5,9 +5,9 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"slices"
 
 	"github.com/sayerxofficial/langchaingo/llms"
-	"github.com/sayerxofficial/langchaingo/llms/streaming"
 )
 
 // Backend is the interface that needs to be implemented by cache backends.
@@ -18,6 +18,28 @@ type Backend interface {
 	Put(ctx context.Context, key string, response *llms.ContentResponse)
 }
 
+// Request is a call to the wrapped model, as seen by the cache.
+type Request struct {
+	// Key is the exact-match key calculated from the messages and options.
+	Key string
+	// Messages are the messages sent to the model.
+	Messages []llms.MessageContent
+	// Options are the options the model is called with.
+	Options llms.CallOptions
+}
+
+// RequestBackend is an optional extension of Backend for backends that look
+// up responses by the content of the request rather than by its key, such as
+// semantic caches. When a backend implements it, the Cacher uses GetRequest
+// and PutRequest instead of Get and Put.
+type RequestBackend interface {
+	Backend
+	// GetRequest returns a cached response for the request, or `nil`.
+	GetRequest(ctx context.Context, req Request) *llms.ContentResponse
+	// PutRequest stores the response to the request.
+	PutRequest(ctx context.Context, req Request, response *llms.ContentResponse)
+}
+
 // Cacher is an LLM wrapper that caches the responses from the LLM.
 type Cacher struct {
 	llm   llms.Model
@@ -61,30 +83,45 @@ func (c *Cacher) GenerateContent(ctx context.Context, messages []llms.MessageCon
 		return nil, err
 	}
 
-	if response := c.cache.Get(ctx, key); response != nil {
-		if len(response.Choices) > 0 {
-			// only stream the first choice.
-			if err := streaming.CallWithText(ctx, opts.StreamingFunc, response.Choices[0].Content); err != nil {
-				return nil, err
-			}
-		}
-		if err := streaming.CallWithDone(ctx, opts.StreamingFunc); err != nil {
+	req := Request{Key: key, Messages: messages, Options: opts}
+	if response := c.get(ctx, req); response != nil {
+		response, chunks := splitStreamChunks(response)
+		if err := replay(ctx, opts.StreamingFunc, response, chunks); err != nil {
 			return nil, err
 		}
-
 		return response, nil
 	}
 
+	var rec recorder
+	if opts.StreamingFunc != nil {
+		options = append(slices.Clip(options), llms.WithStreamingFunc(rec.wrap(opts.StreamingFunc)))
+	}
+
 	response, err := c.llm.GenerateContent(ctx, messages, options...)
 	if err != nil {
 		return nil, err
 	}
 
-	c.cache.Put(ctx, key, response)
+	c.put(ctx, req, withStreamChunks(response, rec.chunks()))
 
 	return response, nil
 }
 
+func (c *Cacher) get(ctx context.Context, req Request) *llms.ContentResponse {
+	if rb, ok := c.cache.(RequestBackend); ok {
+		return rb.GetRequest(ctx, req)
+	}
+	return c.cache.Get(ctx, req.Key)
+}
+
+func (c *Cacher) put(ctx context.Context, req Request, response *llms.ContentResponse) {
+	if rb, ok := c.cache.(RequestBackend); ok {
+		rb.PutRequest(ctx, req, response)
+		return
+	}
+	c.cache.Put(ctx, req.Key, response)
+}
+
 // hashKeyForCache is a helper function that generates a unique key for a given
 // set of messages and call options.
 func hashKeyForCache(messages []llms.MessageContent, opts llms.CallOptions) (string, error) {
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"slices"
 	"testing"
 
 	"github.com/sayerxofficial/langchaingo/llms"
@@ -174,3 +175,101 @@ func TestCache_Call_Streaming(t *testing.T) {
 	rq.True(stream)
 	rq.True(streamDone)
 }
+
+func TestCache_GenerateContent_StreamReplay(t *testing.T) {
+	t.Parallel()
+
+	chunks := []streaming.Chunk{
+		streaming.NewReasoningChunk("thinking"),
+		streaming.NewTextChunk("hel"),
+		streaming.NewTextChunk("lo"),
+		streaming.NewToolCallChunk(streaming.NewToolCall("call_1", "lookup", `{"q":"x"}`)),
+	}
+	response := &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			Content:          "hello",
+			ReasoningContent: "thinking",
+			ToolCalls: []llms.ToolCall{{
+				ID:           "call_1",
+				Type:         "function",
+				FunctionCall: &llms.FunctionCall{Name: "lookup", Arguments: `{"q":"x"}`},
+			}},
+		}},
+	}
+	backends := map[string]func() Backend{
+		"in memory": func() Backend { return newMockCache() },
+		"json":      func() Backend { return &jsonCache{entries: make(map[string][]byte)} },
+	}
+
+	for name, newBackend := range backends {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := t.Context()
+			rq := require.New(t)
+
+			mockLLM := &mockStreamingLLM{chunks: chunks, response: response}
+			llm := New(mockLLM, newBackend())
+			messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")}
+
+			var streamed []streaming.Chunk
+			record := llms.WithStreamingFunc(func(_ context.Context, chunk streaming.Chunk) error {
+				streamed = append(streamed, chunk)
+				return nil
+			})
+
+			_, err := llm.GenerateContent(ctx, messages, record)
+			rq.NoError(err)
+			rq.Equal(1, mockLLM.called)
+			live := streamed
+			rq.Equal(append(slices.Clone(chunks), streaming.NewDoneChunk()), live)
+
+			streamed = nil
+			resp, err := llm.GenerateContent(ctx, messages, record)
+			rq.NoError(err)
+			rq.Equal(1, mockLLM.called)
+			rq.Equal(live, streamed)
+			rq.Equal(response, resp, "stream chunks should not leak into the response")
+		})
+	}
+}
+
+func TestCache_GenerateContent_StreamSynthesized(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+
+	response := &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			Content:          "hello",
+			ReasoningContent: "thinking",
+			ToolCalls: []llms.ToolCall{{
+				ID:           "call_1",
+				Type:         "function",
+				FunctionCall: &llms.FunctionCall{Name: "lookup", Arguments: `{}`},
+			}},
+		}},
+	}
+	mockLLM := &mockStreamingLLM{response: response}
+	llm := New(mockLLM, newMockCache())
+	messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")}
+
+	// The response is cached without streaming...
+	_, err := llm.GenerateContent(ctx, messages)
+	rq.NoError(err)
+
+	// ...so a streamed hit is rebuilt from the response. The streaming
+	// callback is not part of the cache key.
+	var streamed []streaming.Chunk
+	_, err = llm.GenerateContent(ctx, messages, llms.WithStreamingFunc(func(_ context.Context, chunk streaming.Chunk) error {
+		streamed = append(streamed, chunk)
+		return nil
+	}))
+	rq.NoError(err)
+	rq.Equal(1, mockLLM.called)
+	rq.Equal([]streaming.Chunk{
+		streaming.NewReasoningChunk("thinking"),
+		streaming.NewTextChunk("hello"),
+		streaming.NewToolCallChunk(streaming.NewToolCall("call_1", "lookup", `{}`)),
+		streaming.NewDoneChunk(),
+	}, streamed)
+}
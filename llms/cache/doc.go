@@ -1,4 +1,5 @@
 // Package cache provides a generic wrapper that adds caching to a `llms.Model`. Responses are
 // cached under a key calculated based on the provided messages and options. Different cache
-// backends can be used when creating the wrapper.
+// backends can be used when creating the wrapper. Backends that implement `RequestBackend`
+// receive the whole request instead, which lets them match similar prompts.
 package cache
@@ -0,0 +1,272 @@
+// Package filesystem provides a content-addressed `cache.Backend` that stores
+// every response in its own file under a directory.
+//
+// Entries are written to a temporary file and renamed into place, so
+// several processes can share the same directory: readers never see a
+// partially written entry, and concurrent writers of the same key simply
+// replace each other's entry.
+package filesystem
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sayerxofficial/langchaingo/llms"
+)
+
+const (
+	entryExt = ".json"
+	// entryNameLen is the length of an entry file name: the hex encoded
+	// SHA-256 of the key followed by entryExt.
+	entryNameLen = 2*sha256.Size + len(entryExt)
+
+	defaultEvictionInterval = time.Minute
+)
+
+// entry is the content of a cache file.
+type entry struct {
+	Key      string                `json:"key"`
+	Created  time.Time             `json:"created"`
+	Response *llms.ContentResponse `json:"response"`
+}
+
+// FileSystem is a `cache.Backend` that stores responses as JSON files.
+type FileSystem struct {
+	Options Options
+	dir     string
+
+	mu        sync.Mutex
+	lastEvict time.Time
+}
+
+// New creates a new filesystem `cache.Backend` rooted at dir, creating the
+// directory if needed.
+func New(dir string, opts ...Option) (*FileSystem, error) {
+	options, err := applyOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	return &FileSystem{
+		Options: *options,
+		dir:     dir,
+	}, nil
+}
+
+// Get a value from the cache. If the key is not found or has expired, return
+// `nil`.
+func (f *FileSystem) Get(_ context.Context, key string) *llms.ContentResponse {
+	// errors are ignored, instead we return `nil` and pretend the key
+	// wasn't found.
+	path := f.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Key != key {
+		return nil
+	}
+
+	now := time.Now()
+	if f.Options.TTL > 0 && now.Sub(e.Created) >= f.Options.TTL {
+		_ = os.Remove(path)
+		return nil
+	}
+
+	// The modification time records the last access for eviction.
+	_ = os.Chtimes(path, now, now)
+
+	return e.Response
+}
+
+// Put a value into the cache. At most once per EvictionInterval, it then
+// evicts expired entries and the least recently used ones that exceed the
+// size limits.
+func (f *FileSystem) Put(_ context.Context, key string, response *llms.ContentResponse) {
+	data, err := json.Marshal(entry{
+		Key:      key,
+		Created:  time.Now(),
+		Response: response,
+	})
+	if err != nil {
+		return
+	}
+
+	if err := f.write(f.path(key), data); err != nil {
+		return
+	}
+
+	if f.shouldEvict(time.Now()) {
+		_ = f.evict()
+	}
+}
+
+// shouldEvict reports whether the eviction interval elapsed since the last
+// eviction, and if so records now as the last eviction.
+func (f *FileSystem) shouldEvict(now time.Time) bool {
+	if f.Options.TTL == 0 && f.Options.MaxEntries == 0 && f.Options.MaxBytes == 0 {
+		return false
+	}
+	interval := f.Options.EvictionInterval
+	if interval == 0 {
+		interval = defaultEvictionInterval
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.lastEvict.IsZero() && now.Sub(f.lastEvict) < interval {
+		return false
+	}
+	f.lastEvict = now
+	return true
+}
+
+// path returns the file of a key. Files are named after the hash of the key
+// and spread over subdirectories named after its first two characters.
+func (f *FileSystem) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(f.dir, name[:2], name+entryExt)
+}
+
+// write atomically replaces the file at path with data.
+func (f *FileSystem) write(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+type fileInfo struct {
+	path     string
+	size     int64
+	accessed time.Time
+}
+
+// evict removes expired entries and the least recently used entries beyond
+// MaxEntries and MaxBytes. Only entry files in the shard directories are
+// considered, so unrelated files sharing the directory are left alone. Files
+// removed concurrently by another process are skipped.
+func (f *FileSystem) evict() error {
+	files, err := f.entries()
+	if err != nil {
+		return err
+	}
+
+	// Most recently used first.
+	slices.SortFunc(files, func(a, b fileInfo) int {
+		return b.accessed.Compare(a.accessed)
+	})
+
+	now := time.Now()
+	var total int64
+	kept := 0
+	full := false
+	for _, file := range files {
+		// An entry can't be older than its last access, so this only
+		// removes entries that are certainly expired.
+		expired := f.Options.TTL > 0 && now.Sub(file.accessed) >= f.Options.TTL
+		if !expired && !full {
+			full = (f.Options.MaxEntries > 0 && kept >= f.Options.MaxEntries) ||
+				(f.Options.MaxBytes > 0 && total+file.size > f.Options.MaxBytes)
+		}
+		if expired || full {
+			if err := os.Remove(file.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			continue
+		}
+		kept++
+		total += file.size
+	}
+	return nil
+}
+
+// entries lists the entry files: files named after a key hash in the shard
+// directory named after the first two characters of that hash.
+func (f *FileSystem) entries() ([]fileInfo, error) {
+	shards, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []fileInfo
+	for _, shard := range shards {
+		if !shard.IsDir() || len(shard.Name()) != 2 || !isHex(shard.Name()) {
+			continue
+		}
+		dir := filepath.Join(f.dir, shard.Name())
+		names, err := os.ReadDir(dir)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		for _, d := range names {
+			if !d.Type().IsRegular() || !isEntryName(shard.Name(), d.Name()) {
+				continue
+			}
+			info, err := d.Info()
+			if err != nil {
+				continue // removed by another process
+			}
+			files = append(files, fileInfo{
+				path:     filepath.Join(dir, d.Name()),
+				size:     info.Size(),
+				accessed: info.ModTime(),
+			})
+		}
+	}
+	return files, nil
+}
+
+// isEntryName reports whether name is the name of an entry file in the given
+// shard directory.
+func isEntryName(shard, name string) bool {
+	if len(name) != entryNameLen || !strings.HasSuffix(name, entryExt) || !strings.HasPrefix(name, shard) {
+		return false
+	}
+	return isHex(strings.TrimSuffix(name, entryExt))
+}
+
+// isHex reports whether s only contains lowercase hexadecimal digits, as
+// produced by hex.EncodeToString.
+func isHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
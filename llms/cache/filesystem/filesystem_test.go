@@ -0,0 +1,202 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sayerxofficial/langchaingo/llms"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func response(content string) *llms.ContentResponse {
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			Content: content,
+			ToolCalls: []llms.ToolCall{{
+				ID:           "call_1",
+				Type:         "function",
+				FunctionCall: &llms.FunctionCall{Name: "lookup", Arguments: `{"q":"x"}`},
+			}},
+		}},
+		Usage: &llms.Usage{InputTokens: 3, OutputTokens: 5},
+	}
+}
+
+func TestFileSystem(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+
+	dir := t.TempDir()
+	cache, err := New(dir)
+	rq.NoError(err)
+
+	rq.Nil(cache.Get(ctx, "key1"), "empty cache should be empty")
+
+	val := response("value")
+	cache.Put(ctx, "key1", val)
+	rq.Equal(val, cache.Get(ctx, "key1"))
+
+	// The entry is visible to another instance sharing the directory.
+	other, err := New(dir)
+	rq.NoError(err)
+	rq.Equal(val, other.Get(ctx, "key1"))
+}
+
+func TestFileSystem_TTL(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+	ttl := time.Second / 2
+
+	cache, err := New(t.TempDir(), WithTTL(ttl))
+	rq.NoError(err)
+
+	cache.Put(ctx, "key1", response("value"))
+	rq.NotNil(cache.Get(ctx, "key1"))
+
+	time.Sleep(ttl * 2) // double the ttl to make sure the value has timed out.
+	rq.Nil(cache.Get(ctx, "key1"), "value should have expired")
+	rq.NoFileExists(cache.path("key1"))
+}
+
+func TestFileSystem_MaxEntries(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+
+	cache, err := New(t.TempDir(), WithMaxEntries(2), WithEvictionInterval(time.Nanosecond))
+	rq.NoError(err)
+
+	old := time.Now().Add(-time.Hour)
+	cache.Put(ctx, "key1", response("1"))
+	rq.NoError(os.Chtimes(cache.path("key1"), old, old))
+	cache.Put(ctx, "key2", response("2"))
+	rq.NoError(os.Chtimes(cache.path("key2"), old.Add(-time.Minute), old.Add(-time.Minute)))
+	cache.Put(ctx, "key3", response("3"))
+
+	rq.NotNil(cache.Get(ctx, "key1"))
+	rq.Nil(cache.Get(ctx, "key2"), "least recently used value should have been evicted")
+	rq.NotNil(cache.Get(ctx, "key3"))
+}
+
+func TestFileSystem_MaxBytes(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+
+	cache, err := New(t.TempDir(), WithEvictionInterval(time.Nanosecond))
+	rq.NoError(err)
+
+	cache.Put(ctx, "key1", response("1"))
+	info, err := os.Stat(cache.path("key1"))
+	rq.NoError(err)
+	old := time.Now().Add(-time.Hour)
+	rq.NoError(os.Chtimes(cache.path("key1"), old, old))
+
+	cache.Options.MaxBytes = info.Size() * 2
+	cache.Put(ctx, "key2", response("2"))
+	cache.Put(ctx, "key3", response("3"))
+
+	rq.NoFileExists(cache.path("key1"), "oldest value should have been evicted")
+	rq.NotNil(cache.Get(ctx, "key2"))
+	rq.NotNil(cache.Get(ctx, "key3"))
+}
+
+func TestFileSystem_Concurrent(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+
+	dir := t.TempDir()
+	var wg sync.WaitGroup
+	for i := range 4 {
+		cache, err := New(dir, WithMaxEntries(10), WithEvictionInterval(time.Nanosecond))
+		rq.NoError(err)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 20 {
+				key := string(rune('a' + (i+j)%26))
+				cache.Put(ctx, key, response(key))
+				if got := cache.Get(ctx, key); got != nil {
+					// A hit must never be a partially written entry.
+					assert.Len(t, got.Choices, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*", "*"+entryExt))
+	rq.NoError(err)
+	rq.LessOrEqual(len(matches), 10)
+}
+
+func TestFileSystem_EvictionInterval(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+
+	cache, err := New(t.TempDir(), WithMaxEntries(1))
+	rq.NoError(err)
+
+	// The first Put evicts, the next ones wait for the interval to elapse.
+	cache.Put(ctx, "key1", response("1"))
+	cache.Put(ctx, "key2", response("2"))
+	rq.FileExists(cache.path("key1"))
+	rq.FileExists(cache.path("key2"))
+
+	cache.Options.EvictionInterval = time.Nanosecond
+	cache.Put(ctx, "key3", response("3"))
+	rq.NoFileExists(cache.path("key1"))
+	rq.NoFileExists(cache.path("key2"))
+	rq.FileExists(cache.path("key3"))
+}
+
+func TestFileSystem_EvictKeepsUnrelatedFiles(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+
+	dir := t.TempDir()
+	cache, err := New(dir, WithMaxEntries(1), WithEvictionInterval(time.Nanosecond))
+	rq.NoError(err)
+
+	unrelated := []string{
+		filepath.Join(dir, "config.json"),
+		filepath.Join(dir, "settings", "user.json"),
+		filepath.Join(dir, "ab", "notes.json"),
+		filepath.Join(dir, "zz", strings.Repeat("f", 64)+entryExt),
+	}
+	old := time.Now().Add(-time.Hour)
+	for _, path := range unrelated {
+		rq.NoError(os.MkdirAll(filepath.Dir(path), 0o750))
+		rq.NoError(os.WriteFile(path, []byte("{}"), 0o600))
+		rq.NoError(os.Chtimes(path, old, old))
+	}
+
+	cache.Put(ctx, "key1", response("1"))
+	rq.NoError(os.Chtimes(cache.path("key1"), old, old))
+	cache.Put(ctx, "key2", response("2"))
+
+	rq.NoFileExists(cache.path("key1"))
+	rq.FileExists(cache.path("key2"))
+	for _, path := range unrelated {
+		rq.FileExists(path)
+	}
+}
+
+func TestNew_InvalidOption(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(t.TempDir(), WithMaxEntries(-1))
+	require.ErrorIs(t, err, ErrInvalidOption)
+}
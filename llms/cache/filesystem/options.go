@@ -0,0 +1,90 @@
+package filesystem
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidOption is returned by New when an option has an invalid value.
+var ErrInvalidOption = errors.New("filesystem: invalid option")
+
+// Option is a functional argument that configures the Options.
+type Option func(*Options) error
+
+// Options is a set of options for the filesystem cache.
+type Options struct {
+	// TTL is the time after which an entry expires. Zero means entries never
+	// expire.
+	TTL time.Duration
+	// MaxEntries is the maximum number of entries kept. Zero means no limit.
+	MaxEntries int
+	// MaxBytes is the maximum total size of the entry files. Zero means no
+	// limit.
+	MaxBytes int64
+	// EvictionInterval is the minimum time between two evictions. Evicting
+	// lists every entry, so it only runs on a Put at most once per interval,
+	// and the cache may exceed its limits in between. Zero means one minute.
+	EvictionInterval time.Duration
+}
+
+// WithTTL specifies the time after which an entry expires.
+func WithTTL(ttl time.Duration) Option {
+	return func(o *Options) error {
+		if ttl < 0 {
+			return ErrInvalidOption
+		}
+		o.TTL = ttl
+
+		return nil
+	}
+}
+
+// WithMaxEntries specifies the maximum number of entries kept. The least
+// recently used entries are evicted first.
+func WithMaxEntries(n int) Option {
+	return func(o *Options) error {
+		if n < 0 {
+			return ErrInvalidOption
+		}
+		o.MaxEntries = n
+
+		return nil
+	}
+}
+
+// WithMaxBytes specifies the maximum total size of the entry files. The
+// least recently used entries are evicted first.
+func WithMaxBytes(n int64) Option {
+	return func(o *Options) error {
+		if n < 0 {
+			return ErrInvalidOption
+		}
+		o.MaxBytes = n
+
+		return nil
+	}
+}
+
+// WithEvictionInterval specifies the minimum time between two evictions.
+func WithEvictionInterval(d time.Duration) Option {
+	return func(o *Options) error {
+		if d < 0 {
+			return ErrInvalidOption
+		}
+		o.EvictionInterval = d
+
+		return nil
+	}
+}
+
+func applyOptions(opts ...Option) (*Options, error) {
+	o := new(Options)
+
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+
+	return o, nil
+}
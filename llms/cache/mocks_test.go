@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/sayerxofficial/langchaingo/llms"
 	"github.com/sayerxofficial/langchaingo/llms/streaming"
@@ -76,3 +77,64 @@ func (m *mockCache) Put(_ context.Context, key string, response *llms.ContentRes
 	m.entries[key] = response
 	m.puts++
 }
+
+// === Mock for a streaming llms.Model
+
+// not synchronized, don't use concurrently!
+type mockStreamingLLM struct {
+	called   int
+	chunks   []streaming.Chunk
+	response *llms.ContentResponse
+}
+
+func (m *mockStreamingLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *mockStreamingLLM) GenerateContent(ctx context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	m.called++
+	if opts.StreamingFunc != nil {
+		for _, chunk := range m.chunks {
+			if err := opts.StreamingFunc(ctx, chunk); err != nil {
+				return nil, err
+			}
+		}
+		if err := streaming.CallWithDone(ctx, opts.StreamingFunc); err != nil {
+			return nil, err
+		}
+	}
+
+	return m.response, nil
+}
+
+// === Mock for a cache.Backend that serializes responses
+
+// not synchronized, don't use concurrently!
+type jsonCache struct {
+	entries map[string][]byte
+}
+
+func (m *jsonCache) Get(_ context.Context, key string) *llms.ContentResponse {
+	data, ok := m.entries[key]
+	if !ok {
+		return nil
+	}
+	var response llms.ContentResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil
+	}
+	return &response
+}
+
+func (m *jsonCache) Put(_ context.Context, key string, response *llms.ContentResponse) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	m.entries[key] = data
+}
@@ -0,0 +1,9 @@
+// Package semantic provides a `cache.Backend` that returns cached responses
+// for prompts that are similar, rather than identical, to a previous prompt.
+//
+// The text of the final user message is embedded and looked up in a
+// `vectorstores.VectorStore`. A cached response is returned when the most
+// similar stored prompt scores above the configured threshold. The earlier
+// messages and the call options that change the output, such as tools,
+// temperature and JSON mode, must still match exactly.
+package semantic
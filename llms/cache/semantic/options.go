@@ -0,0 +1,37 @@
+package semantic
+
+import "errors"
+
+const (
+	defaultThreshold  = 0.95
+	defaultCandidates = 4
+)
+
+// ErrInvalidThreshold is returned by New when the similarity threshold is not
+// between 0 and 1.
+var ErrInvalidThreshold = errors.New("semantic: threshold must be between 0 and 1")
+
+// Option is a functional argument that configures the semantic cache.
+type Option func(*options)
+
+type options struct {
+	threshold  float32
+	candidates int
+}
+
+// WithThreshold sets the minimum similarity score, between 0 and 1, a stored
+// prompt must reach to be considered a hit. Defaults to 0.95.
+func WithThreshold(threshold float32) Option {
+	return func(o *options) {
+		o.threshold = threshold
+	}
+}
+
+// WithCandidates sets the number of similar prompts fetched from the vector
+// store on each lookup. Stores that filter after searching may need more
+// candidates to find a prompt with matching options. Defaults to 4.
+func WithCandidates(n int) Option {
+	return func(o *options) {
+		o.candidates = n
+	}
+}
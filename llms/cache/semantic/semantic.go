@@ -0,0 +1,172 @@
+package semantic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/sayerxofficial/langchaingo/embeddings"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/llms/cache"
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+)
+
+const (
+	// MetadataPartition is the document metadata key holding the hash of the
+	// parts of a request that must match exactly.
+	MetadataPartition = "cache_partition"
+	// MetadataResponse is the document metadata key holding the cached
+	// response, encoded as JSON.
+	MetadataResponse = "cache_response"
+)
+
+// Semantic is a `cache.Backend` that looks up responses by the similarity of
+// the final user message. It is meant to be used through `cache.Cacher`; the
+// plain Get and Put methods only see the request key and never hit.
+type Semantic struct {
+	embedder embeddings.Embedder
+	store    vectorstores.VectorStore
+	opts     options
+}
+
+// assert that `Semantic` implements the `cache.RequestBackend` interface.
+var _ cache.RequestBackend = (*Semantic)(nil)
+
+// New creates a semantic cache that embeds prompts with embedder and stores
+// them in store.
+func New(embedder embeddings.Embedder, store vectorstores.VectorStore, opts ...Option) (*Semantic, error) {
+	o := options{
+		threshold:  defaultThreshold,
+		candidates: defaultCandidates,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.threshold < 0 || o.threshold > 1 {
+		return nil, ErrInvalidThreshold
+	}
+	if o.candidates <= 0 {
+		o.candidates = defaultCandidates
+	}
+
+	return &Semantic{
+		embedder: embedder,
+		store:    store,
+		opts:     o,
+	}, nil
+}
+
+// Get always returns `nil`: a semantic lookup needs the request, not only
+// its key.
+func (s *Semantic) Get(context.Context, string) *llms.ContentResponse {
+	return nil
+}
+
+// Put does nothing: a semantic cache entry needs the request, not only its
+// key.
+func (s *Semantic) Put(context.Context, string, *llms.ContentResponse) {}
+
+// GetRequest returns the response cached for the most similar prompt that was
+// sent with the same earlier messages and options, or `nil` if no prompt is
+// similar enough. Errors from the embedder or the store are treated as a miss.
+func (s *Semantic) GetRequest(ctx context.Context, req cache.Request) *llms.ContentResponse {
+	prompt, partition, ok := splitRequest(req)
+	if !ok {
+		return nil
+	}
+
+	docs, err := s.store.SimilaritySearch(ctx, prompt, s.opts.candidates,
+		vectorstores.WithEmbedder(s.embedder),
+		vectorstores.WithFilters(map[string]any{MetadataPartition: partition}),
+		vectorstores.WithScoreThreshold(s.opts.threshold),
+	)
+	if err != nil {
+		return nil
+	}
+
+	// Not every store honors filters and thresholds, so check both again.
+	var best *schema.Document
+	for i, doc := range docs {
+		if doc.Metadata[MetadataPartition] != partition || doc.Score < s.opts.threshold {
+			continue
+		}
+		if best == nil || doc.Score > best.Score {
+			best = &docs[i]
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	encoded, ok := best.Metadata[MetadataResponse].(string)
+	if !ok {
+		return nil
+	}
+	var response llms.ContentResponse
+	if err := json.Unmarshal([]byte(encoded), &response); err != nil {
+		return nil
+	}
+	return &response
+}
+
+// PutRequest stores the response under the final user message of the
+// request. Requests that do not end with a user message are not cached.
+func (s *Semantic) PutRequest(ctx context.Context, req cache.Request, response *llms.ContentResponse) {
+	prompt, partition, ok := splitRequest(req)
+	if !ok || response == nil {
+		return
+	}
+
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+
+	// Errors are ignored, the response is simply not cached.
+	_, _ = s.store.AddDocuments(ctx, []schema.Document{{
+		PageContent: prompt,
+		Metadata: map[string]any{
+			MetadataPartition: partition,
+			MetadataResponse:  string(encoded),
+		},
+	}}, vectorstores.WithEmbedder(s.embedder))
+}
+
+// splitRequest returns the text of the final user message, which is matched
+// by similarity, and a hash of everything else in the request, which must
+// match exactly.
+func splitRequest(req cache.Request) (string, string, bool) {
+	if len(req.Messages) == 0 {
+		return "", "", false
+	}
+	last := req.Messages[len(req.Messages)-1]
+	if last.Role != llms.ChatMessageTypeHuman {
+		return "", "", false
+	}
+
+	var texts []string
+	var others []llms.ContentPart
+	for _, part := range last.Parts {
+		if text, ok := part.(llms.TextContent); ok {
+			texts = append(texts, text.Text)
+			continue
+		}
+		others = append(others, part)
+	}
+	prompt := strings.Join(texts, "\n")
+	if prompt == "" {
+		return "", "", false
+	}
+
+	hash := sha256.New()
+	enc := json.NewEncoder(hash)
+	for _, v := range []any{req.Messages[:len(req.Messages)-1], others, req.Options} {
+		if err := enc.Encode(v); err != nil {
+			return "", "", false
+		}
+	}
+	return prompt, hex.EncodeToString(hash.Sum(nil)), true
+}
@@ -0,0 +1,165 @@
+package semantic
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/llms/cache"
+	"github.com/sayerxofficial/langchaingo/vectorstores/inmemory"
+
+	"github.com/stretchr/testify/require"
+)
+
+// wordEmbedder embeds texts as bags of words, so texts that share most of
+// their words are similar.
+type wordEmbedder struct{}
+
+func (e wordEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = e.EmbedQuery(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (wordEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	vector := make([]float32, 64)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(strings.Trim(word, "?!.,")))
+		vector[h.Sum32()%64]++
+	}
+	return vector, nil
+}
+
+type countingLLM struct {
+	called int
+}
+
+func (m *countingLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *countingLLM) GenerateContent(_ context.Context, messages []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	m.called++
+	last := messages[len(messages)-1].Parts[0].(llms.TextContent).Text
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			Content: "answer to " + last,
+			ToolCalls: []llms.ToolCall{{
+				ID:           "call_1",
+				Type:         "function",
+				FunctionCall: &llms.FunctionCall{Name: "lookup", Arguments: `{"q":"x"}`},
+			}},
+		}},
+	}, nil
+}
+
+func newCacher(t *testing.T, opts ...Option) (*cache.Cacher, *countingLLM) {
+	t.Helper()
+
+	store, err := inmemory.New(t.Context(), inmemory.WithEmbedder(wordEmbedder{}))
+	require.NoError(t, err)
+	backend, err := New(wordEmbedder{}, store, opts...)
+	require.NoError(t, err)
+
+	llm := &countingLLM{}
+	return cache.New(llm, backend), llm
+}
+
+func TestSemantic(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+
+	cacher, llm := newCacher(t, WithThreshold(0.8))
+
+	resp, err := cacher.GenerateContent(ctx, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "What is the capital of France?"),
+	})
+	rq.NoError(err)
+	rq.Equal(1, llm.called)
+	rq.Equal("answer to What is the capital of France?", resp.Choices[0].Content)
+
+	// A similar prompt is served from the cache, tool calls included.
+	resp, err = cacher.GenerateContent(ctx, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "what is the capital of france"),
+	})
+	rq.NoError(err)
+	rq.Equal(1, llm.called)
+	rq.Equal("answer to What is the capital of France?", resp.Choices[0].Content)
+	rq.Len(resp.Choices[0].ToolCalls, 1)
+	rq.Equal("lookup", resp.Choices[0].ToolCalls[0].FunctionCall.Name)
+
+	// A different prompt misses.
+	_, err = cacher.GenerateContent(ctx, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "How tall is the Eiffel tower?"),
+	})
+	rq.NoError(err)
+	rq.Equal(2, llm.called)
+}
+
+func TestSemantic_OptionsArePartOfKey(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	prompt := []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "What is the capital of France?"),
+	}
+	cases := []struct {
+		name string
+		opts []llms.CallOption
+	}{
+		{"temperature", []llms.CallOption{llms.WithTemperature(0.9)}},
+		{"json mode", []llms.CallOption{llms.WithJSONMode()}},
+		{"tools", []llms.CallOption{llms.WithTools([]llms.Tool{{
+			Type:     "function",
+			Function: &llms.FunctionDefinition{Name: "lookup"},
+		}})}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rq := require.New(t)
+
+			cacher, llm := newCacher(t)
+			_, err := cacher.GenerateContent(ctx, prompt)
+			rq.NoError(err)
+			_, err = cacher.GenerateContent(ctx, prompt, tc.opts...)
+			rq.NoError(err)
+			rq.Equal(2, llm.called)
+
+			_, err = cacher.GenerateContent(ctx, prompt, tc.opts...)
+			rq.NoError(err)
+			rq.Equal(2, llm.called)
+		})
+	}
+}
+
+func TestSemantic_HistoryIsPartOfKey(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+
+	cacher, llm := newCacher(t)
+	question := llms.TextParts(llms.ChatMessageTypeHuman, "What is the capital of France?")
+
+	_, err := cacher.GenerateContent(ctx, []llms.MessageContent{question})
+	rq.NoError(err)
+	_, err = cacher.GenerateContent(ctx, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeSystem, "Answer in French."),
+		question,
+	})
+	rq.NoError(err)
+	rq.Equal(2, llm.called)
+}
+
+func TestNew_InvalidThreshold(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(wordEmbedder{}, nil, WithThreshold(1.5))
+	require.ErrorIs(t, err, ErrInvalidThreshold)
+}
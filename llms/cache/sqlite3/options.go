@@ -0,0 +1,135 @@
+package sqlite3
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// DefaultTableName is the name of the table responses are stored in.
+const DefaultTableName = "langchaingo_llm_cache"
+
+// ErrInvalidOption is returned by New when an option has an invalid value.
+var ErrInvalidOption = errors.New("sqlite3: invalid option")
+
+// Option is a functional argument that configures the Options.
+type Option func(*Options) error
+
+// Options is a set of options for the SQLite cache.
+type Options struct {
+	// DB is the database connection. If nil, a connection to DBAddress is
+	// opened and closed by Close.
+	DB *sql.DB
+	// DBAddress is the file path of the database. Defaults to ":memory:".
+	DBAddress string
+	// TableName is the name of the table responses are stored in.
+	TableName string
+	// TTL is the time after which an entry expires. Zero means entries never
+	// expire.
+	TTL time.Duration
+	// MaxEntries is the maximum number of entries kept. Zero means no limit.
+	MaxEntries int
+	// MaxBytes is the maximum total size of the stored responses. Zero means
+	// no limit.
+	MaxBytes int64
+	// EvictionInterval is the minimum time between two evictions. Evicting
+	// scans the whole table, so it only runs on a Put at most once per
+	// interval, and the cache may exceed its limits in between. Zero means
+	// one minute.
+	EvictionInterval time.Duration
+}
+
+// WithDB specifies an existing database connection to use.
+func WithDB(db *sql.DB) Option {
+	return func(o *Options) error {
+		o.DB = db
+
+		return nil
+	}
+}
+
+// WithDBAddress specifies the file path of the database to open.
+func WithDBAddress(addr string) Option {
+	return func(o *Options) error {
+		o.DBAddress = addr
+
+		return nil
+	}
+}
+
+// WithTableName specifies the name of the table responses are stored in.
+func WithTableName(name string) Option {
+	return func(o *Options) error {
+		if name == "" {
+			return ErrInvalidOption
+		}
+		o.TableName = name
+
+		return nil
+	}
+}
+
+// WithTTL specifies the time after which an entry expires.
+func WithTTL(ttl time.Duration) Option {
+	return func(o *Options) error {
+		if ttl < 0 {
+			return ErrInvalidOption
+		}
+		o.TTL = ttl
+
+		return nil
+	}
+}
+
+// WithMaxEntries specifies the maximum number of entries kept. The least
+// recently used entries are evicted first.
+func WithMaxEntries(n int) Option {
+	return func(o *Options) error {
+		if n < 0 {
+			return ErrInvalidOption
+		}
+		o.MaxEntries = n
+
+		return nil
+	}
+}
+
+// WithMaxBytes specifies the maximum total size of the stored responses. The
+// least recently used entries are evicted first.
+func WithMaxBytes(n int64) Option {
+	return func(o *Options) error {
+		if n < 0 {
+			return ErrInvalidOption
+		}
+		o.MaxBytes = n
+
+		return nil
+	}
+}
+
+// WithEvictionInterval specifies the minimum time between two evictions.
+func WithEvictionInterval(d time.Duration) Option {
+	return func(o *Options) error {
+		if d < 0 {
+			return ErrInvalidOption
+		}
+		o.EvictionInterval = d
+
+		return nil
+	}
+}
+
+func applyOptions(opts ...Option) (*Options, error) {
+	o := &Options{
+		DBAddress: ":memory:",
+		TableName: DefaultTableName,
+	}
+
+	for _, opt := range opts {
+		if err := opt(o); err != nil {
+			return nil, err
+		}
+	}
+
+	return o, nil
+}
@@ -0,0 +1,202 @@
+// Package sqlite3 provides a `cache.Backend` that persists responses in a
+// SQLite database. The database can be shared by several processes.
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sayerxofficial/langchaingo/llms"
+
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver.
+)
+
+const schema = `CREATE TABLE IF NOT EXISTS %[1]s (
+	key TEXT PRIMARY KEY,
+	response TEXT NOT NULL,
+	created INTEGER NOT NULL,
+	accessed INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_%[1]s_accessed ON %[1]s (accessed);`
+
+const (
+	// busyTimeout is how long, in milliseconds, a connection waits for a
+	// lock held by another connection or process.
+	busyTimeout = 5000
+
+	defaultEvictionInterval = time.Minute
+)
+
+// SQLite is a `cache.Backend` backed by a SQLite table.
+type SQLite struct {
+	Options Options
+	db      *sql.DB
+	ownsDB  bool
+
+	mu        sync.Mutex
+	lastEvict time.Time
+}
+
+// New creates a new SQLite `cache.Backend` and creates its table if needed.
+func New(ctx context.Context, opts ...Option) (*SQLite, error) {
+	options, err := applyOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SQLite{
+		Options: *options,
+		db:      options.DB,
+	}
+	if s.db == nil {
+		if s.db, err = open(options.DBAddress); err != nil {
+			return nil, err
+		}
+		s.ownsDB = true
+	}
+
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf(schema, options.TableName)); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func open(addr string) (*sql.DB, error) {
+	if addr == ":memory:" {
+		db, err := sql.Open("sqlite3", addr)
+		if err != nil {
+			return nil, err
+		}
+		// Every connection to ":memory:" opens a different database.
+		db.SetMaxOpenConns(1)
+		return db, nil
+	}
+
+	sep := "?"
+	if strings.Contains(addr, "?") {
+		sep = "&"
+	}
+	return sql.Open("sqlite3", fmt.Sprintf("%s%s_busy_timeout=%d&_journal_mode=WAL", addr, sep, busyTimeout))
+}
+
+// Close closes the database connection if it was opened by New.
+func (s *SQLite) Close() error {
+	if !s.ownsDB {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// Get a value from the cache. If the key is not found or has expired, return
+// `nil`.
+func (s *SQLite) Get(ctx context.Context, key string) *llms.ContentResponse {
+	// errors are ignored, instead we return `nil` and pretend the key
+	// wasn't found.
+	var encoded string
+	var created int64
+	query := fmt.Sprintf("SELECT response, created FROM %s WHERE key = ?", s.Options.TableName)
+	if err := s.db.QueryRowContext(ctx, query, key).Scan(&encoded, &created); err != nil {
+		return nil
+	}
+
+	now := time.Now()
+	if s.expired(created, now) {
+		query := fmt.Sprintf("DELETE FROM %s WHERE key = ? AND created = ?", s.Options.TableName)
+		_, _ = s.db.ExecContext(ctx, query, key, created)
+		return nil
+	}
+
+	var response llms.ContentResponse
+	if err := json.Unmarshal([]byte(encoded), &response); err != nil {
+		return nil
+	}
+
+	query = fmt.Sprintf("UPDATE %s SET accessed = ? WHERE key = ?", s.Options.TableName)
+	_, _ = s.db.ExecContext(ctx, query, now.UnixNano(), key)
+
+	return &response
+}
+
+// Put a value into the cache. At most once per EvictionInterval, it then
+// evicts expired entries and the least recently used ones that exceed the
+// size limits.
+func (s *SQLite) Put(ctx context.Context, key string, response *llms.ContentResponse) {
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	query := fmt.Sprintf(`INSERT INTO %s (key, response, created, accessed) VALUES (?, ?, ?, ?)
+ON CONFLICT (key) DO UPDATE SET response = excluded.response, created = excluded.created, accessed = excluded.accessed`,
+		s.Options.TableName)
+	if _, err := s.db.ExecContext(ctx, query, key, string(encoded), now.UnixNano(), now.UnixNano()); err != nil {
+		return
+	}
+
+	if s.shouldEvict(now) {
+		_ = s.evict(ctx, now.UnixNano())
+	}
+}
+
+// shouldEvict reports whether the eviction interval elapsed since the last
+// eviction, and if so records now as the last eviction.
+func (s *SQLite) shouldEvict(now time.Time) bool {
+	if s.Options.TTL == 0 && s.Options.MaxEntries == 0 && s.Options.MaxBytes == 0 {
+		return false
+	}
+	interval := s.Options.EvictionInterval
+	if interval == 0 {
+		interval = defaultEvictionInterval
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.lastEvict.IsZero() && now.Sub(s.lastEvict) < interval {
+		return false
+	}
+	s.lastEvict = now
+	return true
+}
+
+// evict removes expired entries and the least recently used entries beyond
+// MaxEntries and MaxBytes.
+func (s *SQLite) evict(ctx context.Context, now int64) error {
+	table := s.Options.TableName
+	if s.Options.TTL > 0 {
+		query := fmt.Sprintf("DELETE FROM %s WHERE created <= ?", table)
+		if _, err := s.db.ExecContext(ctx, query, now-int64(s.Options.TTL)); err != nil {
+			return err
+		}
+	}
+	if s.Options.MaxEntries > 0 {
+		query := fmt.Sprintf(`DELETE FROM %[1]s WHERE key IN (
+	SELECT key FROM %[1]s ORDER BY accessed DESC, key LIMIT -1 OFFSET ?
+)`, table)
+		if _, err := s.db.ExecContext(ctx, query, s.Options.MaxEntries); err != nil {
+			return err
+		}
+	}
+	if s.Options.MaxBytes > 0 {
+		query := fmt.Sprintf(`DELETE FROM %[1]s WHERE key IN (
+	SELECT key FROM (
+		SELECT key, SUM(length(response)) OVER (ORDER BY accessed DESC, key) AS total FROM %[1]s
+	) WHERE total > ?
+)`, table)
+		if _, err := s.db.ExecContext(ctx, query, s.Options.MaxBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SQLite) expired(created int64, now time.Time) bool {
+	return s.Options.TTL > 0 && now.Sub(time.Unix(0, created)) >= s.Options.TTL
+}
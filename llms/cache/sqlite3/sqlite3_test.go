@@ -0,0 +1,170 @@
+package sqlite3
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sayerxofficial/langchaingo/llms"
+
+	"github.com/stretchr/testify/require"
+)
+
+func response(content string) *llms.ContentResponse {
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{
+			Content: content,
+			ToolCalls: []llms.ToolCall{{
+				ID:           "call_1",
+				Type:         "function",
+				FunctionCall: &llms.FunctionCall{Name: "lookup", Arguments: `{"q":"x"}`},
+			}},
+		}},
+		Usage: &llms.Usage{InputTokens: 3, OutputTokens: 5},
+	}
+}
+
+func TestSQLite(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "cache.db")
+	cache, err := New(ctx, WithDBAddress(path))
+	rq.NoError(err)
+
+	rq.Nil(cache.Get(ctx, "key1"), "empty cache should be empty")
+
+	val := response("value")
+	cache.Put(ctx, "key1", val)
+	rq.Equal(val, cache.Get(ctx, "key1"))
+	rq.NoError(cache.Close())
+
+	// The entry survives reopening the database.
+	cache, err = New(ctx, WithDBAddress(path))
+	rq.NoError(err)
+	defer cache.Close()
+	rq.Equal(val, cache.Get(ctx, "key1"))
+}
+
+func TestSQLite_TTL(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+	ttl := time.Second / 2
+
+	cache, err := New(ctx, WithTTL(ttl))
+	rq.NoError(err)
+	defer cache.Close()
+
+	cache.Put(ctx, "key1", response("value"))
+	rq.NotNil(cache.Get(ctx, "key1"))
+
+	time.Sleep(ttl * 2) // double the ttl to make sure the value has timed out.
+	rq.Nil(cache.Get(ctx, "key1"), "value should have expired")
+}
+
+func TestSQLite_MaxEntries(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+
+	cache, err := New(ctx, WithMaxEntries(2), WithEvictionInterval(time.Nanosecond))
+	rq.NoError(err)
+	defer cache.Close()
+
+	cache.Put(ctx, "key1", response("1"))
+	cache.Put(ctx, "key2", response("2"))
+	rq.NotNil(cache.Get(ctx, "key1")) // key2 is now the least recently used.
+	cache.Put(ctx, "key3", response("3"))
+
+	rq.NotNil(cache.Get(ctx, "key1"))
+	rq.Nil(cache.Get(ctx, "key2"), "least recently used value should have been evicted")
+	rq.NotNil(cache.Get(ctx, "key3"))
+}
+
+func TestSQLite_MaxBytes(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+
+	cache, err := New(ctx, WithEvictionInterval(time.Nanosecond))
+	rq.NoError(err)
+	defer cache.Close()
+
+	cache.Put(ctx, "key1", response("1"))
+	var size int64
+	rq.NoError(cache.db.QueryRowContext(ctx, "SELECT length(response) FROM "+DefaultTableName).Scan(&size))
+
+	cache.Options.MaxBytes = size * 2
+	cache.Put(ctx, "key2", response("2"))
+	cache.Put(ctx, "key3", response("3"))
+
+	rq.Nil(cache.Get(ctx, "key1"), "oldest value should have been evicted")
+	rq.NotNil(cache.Get(ctx, "key2"))
+	rq.NotNil(cache.Get(ctx, "key3"))
+}
+
+func TestSQLite_EvictionInterval(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+
+	cache, err := New(ctx, WithMaxEntries(1))
+	rq.NoError(err)
+	defer cache.Close()
+
+	// The first Put evicts, the next ones wait for the interval to elapse.
+	cache.Put(ctx, "key1", response("1"))
+	cache.Put(ctx, "key2", response("2"))
+	rq.NotNil(cache.Get(ctx, "key1"))
+	rq.NotNil(cache.Get(ctx, "key2"))
+
+	cache.Options.EvictionInterval = time.Nanosecond
+	cache.Put(ctx, "key3", response("3"))
+	rq.Nil(cache.Get(ctx, "key1"))
+	rq.Nil(cache.Get(ctx, "key2"))
+	rq.NotNil(cache.Get(ctx, "key3"))
+}
+
+func TestSQLite_Concurrent(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+
+	// Separate connections behave like separate processes sharing the file.
+	path := filepath.Join(t.TempDir(), "cache.db")
+	caches := make([]*SQLite, 4)
+	for i := range caches {
+		var err error
+		caches[i], err = New(ctx, WithDBAddress(path), WithMaxEntries(10), WithEvictionInterval(time.Nanosecond))
+		rq.NoError(err)
+		defer caches[i].Close()
+	}
+
+	var wg sync.WaitGroup
+	for i, cache := range caches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 20 {
+				key := string(rune('a' + (i+j)%26))
+				cache.Put(ctx, key, response(key))
+				_ = cache.Get(ctx, key)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var count int
+	rq.NoError(caches[0].db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+DefaultTableName).Scan(&count))
+	rq.LessOrEqual(count, 10)
+}
+
+func TestNew_InvalidOption(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(t.Context(), WithTTL(-time.Second))
+	require.ErrorIs(t, err, ErrInvalidOption)
+}
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"sync"
+
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/llms/streaming"
+)
+
+// GenerationInfoStreamChunks is the GenerationInfo key of the first choice
+// under which a cached response keeps the chunks it was streamed in. The
+// key is removed from responses returned by the Cacher.
+const GenerationInfoStreamChunks = "CacheStreamChunks"
+
+// recorder records the chunks passed to a streaming callback.
+type recorder struct {
+	mu       sync.Mutex
+	recorded []streaming.Chunk
+}
+
+// wrap returns a callback that records every chunk but the final done chunk
+// before passing it to cb.
+func (r *recorder) wrap(cb streaming.Callback) streaming.Callback {
+	return func(ctx context.Context, chunk streaming.Chunk) error {
+		if chunk.Type != streaming.ChunkTypeDone {
+			r.mu.Lock()
+			r.recorded = append(r.recorded, chunk)
+			r.mu.Unlock()
+		}
+		return cb(ctx, chunk)
+	}
+}
+
+func (r *recorder) chunks() []streaming.Chunk {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recorded
+}
+
+// withStreamChunks returns a copy of response that carries chunks, leaving
+// response untouched.
+func withStreamChunks(response *llms.ContentResponse, chunks []streaming.Chunk) *llms.ContentResponse {
+	if len(chunks) == 0 || response == nil || len(response.Choices) == 0 {
+		return response
+	}
+
+	choice := *response.Choices[0]
+	choice.GenerationInfo = maps.Clone(choice.GenerationInfo)
+	if choice.GenerationInfo == nil {
+		choice.GenerationInfo = make(map[string]any)
+	}
+	choice.GenerationInfo[GenerationInfoStreamChunks] = chunks
+
+	cached := *response
+	cached.Choices = append([]*llms.ContentChoice{&choice}, response.Choices[1:]...)
+	return &cached
+}
+
+// splitStreamChunks returns a copy of a cached response without its stream
+// chunks, and the chunks. Chunks are decoded from JSON when the backend
+// serialized the response.
+func splitStreamChunks(response *llms.ContentResponse) (*llms.ContentResponse, []streaming.Chunk) {
+	if len(response.Choices) == 0 {
+		return response, nil
+	}
+	value, ok := response.Choices[0].GenerationInfo[GenerationInfoStreamChunks]
+	if !ok {
+		return response, nil
+	}
+
+	chunks, ok := value.([]streaming.Chunk)
+	if !ok {
+		data, err := json.Marshal(value)
+		if err == nil {
+			err = json.Unmarshal(data, &chunks)
+		}
+		if err != nil {
+			chunks = nil
+		}
+	}
+
+	choice := *response.Choices[0]
+	choice.GenerationInfo = maps.Clone(choice.GenerationInfo)
+	delete(choice.GenerationInfo, GenerationInfoStreamChunks)
+	if len(choice.GenerationInfo) == 0 {
+		choice.GenerationInfo = nil
+	}
+
+	cleaned := *response
+	cleaned.Choices = append([]*llms.ContentChoice{&choice}, response.Choices[1:]...)
+	return &cleaned, chunks
+}
+
+// replay streams a cached response to cb. Recorded chunks are replayed as
+// they were streamed; otherwise the first choice is streamed as its
+// reasoning, text and tool calls. The stream always ends with a done chunk.
+func replay(ctx context.Context, cb streaming.Callback, response *llms.ContentResponse, chunks []streaming.Chunk) error {
+	if cb == nil {
+		return nil
+	}
+
+	if chunks == nil && len(response.Choices) > 0 {
+		chunks = chunksFromChoice(response.Choices[0])
+	}
+	for _, chunk := range chunks {
+		if err := cb(ctx, chunk); err != nil {
+			return err
+		}
+	}
+	return streaming.CallWithDone(ctx, cb)
+}
+
+// chunksFromChoice synthesizes the chunks a choice could have been streamed
+// in.
+func chunksFromChoice(choice *llms.ContentChoice) []streaming.Chunk {
+	var chunks []streaming.Chunk
+	if choice.ReasoningContent != "" {
+		chunks = append(chunks, streaming.NewReasoningChunk(choice.ReasoningContent))
+	}
+	if choice.Content != "" {
+		chunks = append(chunks, streaming.NewTextChunk(choice.Content))
+	}
+	for _, tc := range choice.ToolCalls {
+		if tc.FunctionCall == nil {
+			continue
+		}
+		chunks = append(chunks, streaming.NewToolCallChunk(
+			streaming.NewToolCall(tc.ID, tc.FunctionCall.Name, tc.FunctionCall.Arguments)))
+	}
+	return chunks
+}
@@ -59,6 +59,10 @@ func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageConten
 				Content: result.Text,
 			},
 		},
+		Usage: &llms.Usage{
+			InputTokens:  result.InputTokens,
+			OutputTokens: result.OutputTokens,
+		},
 	}
 
 	if o.CallbacksHandler != nil {
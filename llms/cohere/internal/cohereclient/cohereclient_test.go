@@ -1,7 +1,9 @@
 package cohereclient
 
 import (
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
@@ -61,3 +63,42 @@ func TestClient_CreateGenerationWithCustomModel(t *testing.T) {
 	assert.NotNil(t, resp)
 	assert.NotEmpty(t, resp.Text)
 }
+
+func TestClient_CreateGenerationUsage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		meta       string
+		wantInput  int
+		wantOutput int
+	}{
+		{
+			name: "no billed units",
+			meta: `{}`,
+		},
+		{
+			name:       "billed units",
+			meta:       `{"billed_units": {"input_tokens": 10, "output_tokens": 5}}`,
+			wantInput:  10,
+			wantOutput: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprintf(w, `{"generations": [{"text": "hi"}], "meta": %s}`, tt.meta)
+			}))
+			defer server.Close()
+
+			client, err := New("test-api-key", server.URL, "command")
+			require.NoError(t, err)
+			generation, err := client.CreateGeneration(t.Context(), &GenerationRequest{Prompt: "hello"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantInput, generation.InputTokens)
+			assert.Equal(t, tt.wantOutput, generation.OutputTokens)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 )
 
 // ErrorCode represents a standardized error code for LLM operations.
@@ -125,6 +126,19 @@ func (e *Error) WithDetail(key string, value interface{}) *Error {
 	return e
 }
 
+// ErrorDetailRetryAfter is the Details key under which providers can report
+// how long the caller should wait before retrying, as a time.Duration.
+// ErrorMapper sets it from errors that have a RetryAfter method, such as the
+// httputil.RetryAfterError returned for responses with a Retry-After header.
+const ErrorDetailRetryAfter = "retry_after"
+
+// RetryAfter returns the delay the provider asked for before the request is
+// retried, if any.
+func (e *Error) RetryAfter() (time.Duration, bool) {
+	d, ok := e.Details[ErrorDetailRetryAfter].(time.Duration)
+	return d, ok
+}
+
 // IsAuthenticationError returns true if the error is an authentication error.
 func IsAuthenticationError(err error) bool {
 	var e *Error
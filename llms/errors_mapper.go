@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net"
 	"strings"
+	"time"
 )
 
 // ErrorMapper helps map provider-specific errors to standardized errors.
@@ -144,7 +145,14 @@ func (m *ErrorMapper) WrapError(err error) error {
 		}
 	}
 
-	return NewError(code, m.provider, message).WithCause(err)
+	stdErr = NewError(code, m.provider, message).WithCause(err)
+	var retryAfter interface{ RetryAfter() (time.Duration, bool) }
+	if errors.As(err, &retryAfter) {
+		if d, ok := retryAfter.RetryAfter(); ok {
+			stdErr.WithDetail(ErrorDetailRetryAfter, d)
+		}
+	}
+	return stdErr
 }
 
 // Map is an alias for WrapError for consistency with provider error mappers.
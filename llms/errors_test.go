@@ -5,8 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/sayerxofficial/langchaingo/httputil"
 	"github.com/sayerxofficial/langchaingo/llms"
 )
 
@@ -222,6 +225,29 @@ func TestErrorMapper(t *testing.T) {
 	}
 }
 
+func TestErrorMapperRetryAfter(t *testing.T) {
+	mapper := llms.NewErrorMapper("test-provider")
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"7"}}}
+	err := mapper.WrapError(httputil.WithRetryAfter(errors.New("429 Too Many Requests"), resp))
+
+	var stdErr *llms.Error
+	if !errors.As(err, &stdErr) {
+		t.Fatal("Expected wrapped error to be *llms.Error")
+	}
+	if d, ok := stdErr.RetryAfter(); !ok || d != 7*time.Second {
+		t.Errorf("RetryAfter() = %v, %v, want 7s, true", d, ok)
+	}
+
+	err = mapper.WrapError(errors.New("429 Too Many Requests"))
+	if !errors.As(err, &stdErr) {
+		t.Fatal("Expected wrapped error to be *llms.Error")
+	}
+	if _, ok := stdErr.RetryAfter(); ok {
+		t.Error("RetryAfter() should not be set without a Retry-After header")
+	}
+}
+
 func TestProviderSpecificMappers(t *testing.T) {
 	t.Run("OpenAI mapper", func(t *testing.T) {
 		mapper := llms.OpenAIErrorMapper()
@@ -0,0 +1,5 @@
+// Package fallback provides an llms.Model that fails over between an ordered
+// list of models. A call moves on to the next model when it fails with a
+// retryable *llms.Error, such as a rate limit or a timeout; which error codes
+// trigger a fallback and which are fatal can be configured.
+package fallback
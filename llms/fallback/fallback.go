@@ -0,0 +1,167 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+
+	"github.com/sayerxofficial/langchaingo/callbacks"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/llms/streaming"
+)
+
+const (
+	// GenerationInfoModel is the GenerationInfo key holding the name of the
+	// model that produced a choice.
+	GenerationInfoModel = "FallbackModel"
+	// GenerationInfoIndex is the GenerationInfo key holding the position of
+	// the model that produced a choice.
+	GenerationInfoIndex = "FallbackIndex"
+)
+
+// ErrNoModels is returned by New when no models are given.
+var ErrNoModels = errors.New("fallback: at least one model is required")
+
+// Handler is an optional extension of callbacks.Handler. Handlers that
+// implement it are told every time a call moves on to the next model.
+type Handler interface {
+	HandleLLMFallback(ctx context.Context, from, to string, err error)
+}
+
+// Model is an llms.Model that wraps an ordered list of models. Calls go to
+// the first model and move on to the next one when a call fails with a
+// retryable *llms.Error.
+type Model struct {
+	CallbacksHandler callbacks.Handler
+
+	models []llms.Model
+	opts   options
+}
+
+// assert that `Model` implements the `llms.Model` interface.
+var _ llms.Model = (*Model)(nil)
+
+// New creates a Model that tries models in the given order.
+func New(models []llms.Model, opts ...Option) (*Model, error) {
+	if len(models) == 0 {
+		return nil, ErrNoModels
+	}
+
+	o := options{
+		errorMapper: llms.NewErrorMapper("fallback"),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Model{
+		CallbacksHandler: o.callbacksHandler,
+		models:           models,
+		opts:             o,
+	}, nil
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (m *Model) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+// GenerateContent asks each model in turn to generate content until one of
+// them succeeds or fails with an error that does not allow a fallback. A
+// streamed call never falls back once the first chunk has been delivered.
+func (m *Model) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	if m.CallbacksHandler != nil {
+		m.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
+	}
+
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	var streamed atomic.Bool
+	if opts.StreamingFunc != nil {
+		streamingFunc := opts.StreamingFunc
+		options = append(slices.Clip(options), llms.WithStreamingFunc(func(ctx context.Context, chunk streaming.Chunk) error {
+			streamed.Store(true)
+			return streamingFunc(ctx, chunk)
+		}))
+	}
+
+	var errs []error
+	for i, model := range m.models {
+		resp, err := model.GenerateContent(ctx, messages, options...)
+		if err == nil {
+			m.annotate(resp, i)
+			if m.CallbacksHandler != nil {
+				m.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, resp)
+			}
+			return resp, nil
+		}
+
+		err = m.opts.errorMapper.WrapError(err)
+		errs = append(errs, fmt.Errorf("%s: %w", m.name(i), err))
+		if m.CallbacksHandler != nil {
+			m.CallbacksHandler.HandleLLMError(ctx, err)
+		}
+
+		if ctx.Err() != nil || streamed.Load() || !m.shouldFallback(err) {
+			return nil, err
+		}
+
+		if h, ok := m.CallbacksHandler.(Handler); ok && i < len(m.models)-1 {
+			h.HandleLLMFallback(ctx, m.name(i), m.name(i+1), err)
+		}
+	}
+
+	return nil, fmt.Errorf("fallback: all models failed: %w", errors.Join(errs...))
+}
+
+// shouldFallback reports whether err allows the call to move on to the next
+// model.
+func (m *Model) shouldFallback(err error) bool {
+	var llmErr *llms.Error
+	if !errors.As(err, &llmErr) {
+		return false
+	}
+	if slices.Contains(m.opts.fatalCodes, llmErr.Code) {
+		return false
+	}
+	if m.opts.fallbackCodes != nil {
+		return slices.Contains(m.opts.fallbackCodes, llmErr.Code)
+	}
+	return llms.IsRateLimitError(err) ||
+		llms.IsTimeoutError(err) ||
+		llms.IsQuotaExceededError(err) ||
+		llms.IsProviderUnavailableError(err)
+}
+
+// annotate records the model that answered in the GenerationInfo of every
+// choice.
+func (m *Model) annotate(resp *llms.ContentResponse, i int) {
+	if resp == nil {
+		return
+	}
+	for _, choice := range resp.Choices {
+		if choice.GenerationInfo == nil {
+			choice.GenerationInfo = make(map[string]any)
+		}
+		choice.GenerationInfo[GenerationInfoModel] = m.name(i)
+		choice.GenerationInfo[GenerationInfoIndex] = i
+	}
+}
+
+func (m *Model) name(i int) string {
+	if i < len(m.opts.names) && m.opts.names[i] != "" {
+		return m.opts.names[i]
+	}
+	return fmt.Sprintf("%T", m.models[i])
+}
@@ -0,0 +1,183 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/callbacks"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/llms/streaming"
+
+	"github.com/stretchr/testify/require"
+)
+
+// not synchronized, don't use concurrently!
+type mockLLM struct {
+	called  int
+	content string
+	err     error
+	stream  bool
+}
+
+func (m *mockLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *mockLLM) GenerateContent(ctx context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	m.called++
+	if m.stream {
+		if err := streaming.CallWithText(ctx, opts.StreamingFunc, "partial"); err != nil {
+			return nil, err
+		}
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: m.content}},
+	}, nil
+}
+
+type fallbackHandler struct {
+	callbacks.SimpleHandler
+	fallbacks []string
+	errors    int
+}
+
+func (h *fallbackHandler) HandleLLMError(context.Context, error) {
+	h.errors++
+}
+
+func (h *fallbackHandler) HandleLLMFallback(_ context.Context, from, to string, _ error) {
+	h.fallbacks = append(h.fallbacks, from+"->"+to)
+}
+
+func TestNew_NoModels(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(nil)
+	require.ErrorIs(t, err, ErrNoModels)
+}
+
+func TestModel_FallsBackOnRetryableError(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	rq := require.New(t)
+
+	primary := &mockLLM{err: llms.NewError(llms.ErrCodeRateLimit, "primary", "slow down")}
+	secondary := &mockLLM{content: "hello"}
+	handler := &fallbackHandler{}
+
+	m, err := New([]llms.Model{primary, secondary},
+		WithNames("primary", "secondary"),
+		WithCallback(handler),
+	)
+	rq.NoError(err)
+
+	resp, err := m.GenerateContent(ctx, []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")})
+	rq.NoError(err)
+	rq.Equal("hello", resp.Choices[0].Content)
+	rq.Equal("secondary", resp.Choices[0].GenerationInfo[GenerationInfoModel])
+	rq.Equal(1, resp.Choices[0].GenerationInfo[GenerationInfoIndex])
+	rq.Equal(1, primary.called)
+	rq.Equal(1, secondary.called)
+	rq.Equal([]string{"primary->secondary"}, handler.fallbacks)
+	rq.Equal(1, handler.errors)
+}
+
+func TestModel_ClassifiesRawErrors(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	primary := &mockLLM{err: errors.New("HTTP 503: service unavailable")}
+	secondary := &mockLLM{content: "hello"}
+
+	m, err := New([]llms.Model{primary, secondary})
+	require.NoError(t, err)
+
+	resp, err := m.GenerateContent(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, "hello", resp.Choices[0].Content)
+}
+
+func TestModel_StopsOnNonRetryableError(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		opts []Option
+	}{
+		{
+			name: "authentication is not retryable by default",
+			err:  llms.NewError(llms.ErrCodeAuthentication, "primary", "bad key"),
+		},
+		{
+			name: "fatal codes win over fallback codes",
+			err:  llms.NewError(llms.ErrCodeRateLimit, "primary", "slow down"),
+			opts: []Option{
+				WithFallbackCodes(llms.ErrCodeRateLimit),
+				WithFatalCodes(llms.ErrCodeRateLimit),
+			},
+		},
+		{
+			name: "custom fallback codes replace the defaults",
+			err:  llms.NewError(llms.ErrCodeTimeout, "primary", "too slow"),
+			opts: []Option{WithFallbackCodes(llms.ErrCodeContentFilter)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			primary := &mockLLM{err: tc.err}
+			secondary := &mockLLM{content: "hello"}
+
+			m, err := New([]llms.Model{primary, secondary}, tc.opts...)
+			require.NoError(t, err)
+
+			_, err = m.GenerateContent(t.Context(), nil)
+			require.ErrorIs(t, err, tc.err)
+			require.Equal(t, 0, secondary.called)
+		})
+	}
+}
+
+func TestModel_AllModelsFail(t *testing.T) {
+	t.Parallel()
+
+	errA := llms.NewError(llms.ErrCodeRateLimit, "a", "slow down")
+	errB := llms.NewError(llms.ErrCodeQuotaExceeded, "b", "out of credits")
+	m, err := New([]llms.Model{&mockLLM{err: errA}, &mockLLM{err: errB}})
+	require.NoError(t, err)
+
+	_, err = m.GenerateContent(t.Context(), nil)
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, errB)
+}
+
+func TestModel_NoFallbackAfterStreamStarted(t *testing.T) {
+	t.Parallel()
+
+	primary := &mockLLM{stream: true, err: llms.NewError(llms.ErrCodeTimeout, "primary", "stream dropped")}
+	secondary := &mockLLM{content: "hello"}
+
+	m, err := New([]llms.Model{primary, secondary})
+	require.NoError(t, err)
+
+	var chunks []string
+	_, err = m.GenerateContent(t.Context(), nil, llms.WithStreamingFunc(func(_ context.Context, chunk streaming.Chunk) error {
+		chunks = append(chunks, chunk.Content)
+		return nil
+	}))
+	require.True(t, llms.IsTimeoutError(err))
+	require.Equal(t, []string{"partial"}, chunks)
+	require.Equal(t, 0, secondary.called)
+}
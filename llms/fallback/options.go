@@ -0,0 +1,59 @@
+package fallback
+
+import (
+	"github.com/sayerxofficial/langchaingo/callbacks"
+	"github.com/sayerxofficial/langchaingo/llms"
+)
+
+// Option is a functional argument that configures the fallback Model.
+type Option func(*options)
+
+type options struct {
+	names            []string
+	fallbackCodes    []llms.ErrorCode
+	fatalCodes       []llms.ErrorCode
+	errorMapper      *llms.ErrorMapper
+	callbacksHandler callbacks.Handler
+}
+
+// WithNames sets the names used to report which model answered a call. The
+// names are matched to the models by position. Models without a name are
+// reported by their Go type.
+func WithNames(names ...string) Option {
+	return func(o *options) {
+		o.names = names
+	}
+}
+
+// WithFallbackCodes sets the error codes that move a call to the next model.
+// By default rate limit, timeout, quota exceeded and provider unavailable
+// errors trigger a fallback.
+func WithFallbackCodes(codes ...llms.ErrorCode) Option {
+	return func(o *options) {
+		o.fallbackCodes = codes
+	}
+}
+
+// WithFatalCodes sets the error codes that are returned immediately, without
+// trying the remaining models. Fatal codes take precedence over fallback codes.
+func WithFatalCodes(codes ...llms.ErrorCode) Option {
+	return func(o *options) {
+		o.fatalCodes = codes
+	}
+}
+
+// WithErrorMapper sets the mapper used to classify errors that are not
+// already an *llms.Error.
+func WithErrorMapper(mapper *llms.ErrorMapper) Option {
+	return func(o *options) {
+		o.errorMapper = mapper
+	}
+}
+
+// WithCallback sets the callbacks handler. If the handler also implements
+// Handler, it is notified every time a call falls back to the next model.
+func WithCallback(handler callbacks.Handler) Option {
+	return func(o *options) {
+		o.callbacksHandler = handler
+	}
+}
@@ -122,6 +122,45 @@ func (ToolCallResponse) isPart() {}
 // It can potentially return multiple content choices.
 type ContentResponse struct {
 	Choices []*ContentChoice
+
+	// Usage is the token usage reported by the provider for the whole call.
+	// It is nil when the provider does not report usage.
+	Usage *Usage `json:",omitempty"`
+}
+
+// Usage is a provider-agnostic accounting of the tokens consumed by a
+// GenerateContent call.
+type Usage struct {
+	// InputTokens is the number of tokens in the prompt, including any cached
+	// tokens.
+	InputTokens int `json:"input_tokens"`
+	// OutputTokens is the number of tokens generated by the model, including
+	// any reasoning tokens.
+	OutputTokens int `json:"output_tokens"`
+	// CachedTokens is the number of input tokens that were served from the
+	// provider's prompt cache.
+	CachedTokens int `json:"cached_tokens,omitempty"`
+	// ReasoningTokens is the number of output tokens spent on reasoning.
+	ReasoningTokens int `json:"reasoning_tokens,omitempty"`
+}
+
+// TotalTokens returns the sum of input and output tokens.
+func (u *Usage) TotalTokens() int {
+	if u == nil {
+		return 0
+	}
+	return u.InputTokens + u.OutputTokens
+}
+
+// Add accumulates the token counts of other into u.
+func (u *Usage) Add(other *Usage) {
+	if u == nil || other == nil {
+		return
+	}
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.CachedTokens += other.CachedTokens
+	u.ReasoningTokens += other.ReasoningTokens
 }
 
 // ContentChoice is one of the response choices returned by GenerateContent
@@ -34,3 +34,24 @@ func TestTextParts(t *testing.T) {
 		})
 	}
 }
+
+func TestUsage(t *testing.T) {
+	t.Parallel()
+
+	var nilUsage *Usage
+	if got := nilUsage.TotalTokens(); got != 0 {
+		t.Errorf("nil TotalTokens() = %d, want 0", got)
+	}
+
+	u := &Usage{InputTokens: 10, OutputTokens: 5, CachedTokens: 2}
+	u.Add(&Usage{InputTokens: 3, OutputTokens: 4, ReasoningTokens: 1})
+	u.Add(nil)
+
+	want := &Usage{InputTokens: 13, OutputTokens: 9, CachedTokens: 2, ReasoningTokens: 1}
+	if !reflect.DeepEqual(u, want) {
+		t.Errorf("Add() = %+v, want %+v", u, want)
+	}
+	if got := u.TotalTokens(); got != 22 {
+		t.Errorf("TotalTokens() = %d, want 22", got)
+	}
+}
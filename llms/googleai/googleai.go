@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
 	"github.com/sayerxofficial/langchaingo/internal/imageutil"
@@ -38,6 +39,12 @@ func (g *GoogleAI) Call(ctx context.Context, prompt string, options ...llms.Call
 	return llms.GenerateFromSinglePrompt(ctx, g, prompt, options...)
 }
 
+// SupportsStructuredOutput reports that the model honors
+// llms.WithResponseSchema through its response schema.
+func (g *GoogleAI) SupportsStructuredOutput() bool {
+	return true
+}
+
 // GenerateContent implements the [llms.Model] interface.
 func (g *GoogleAI) GenerateContent(
 	ctx context.Context,
@@ -100,6 +107,13 @@ func (g *GoogleAI) GenerateContent(
 		model.ResponseMIMEType = ResponseMIMETypeJson
 	}
 
+	if opts.ResponseSchema != nil {
+		if model.ResponseSchema, err = convertResponseSchema(opts.ResponseSchema); err != nil {
+			return nil, err
+		}
+		model.ResponseMIMEType = ResponseMIMETypeJson
+	}
+
 	var response *llms.ContentResponse
 
 	if len(messages) == 1 {
@@ -174,6 +188,18 @@ func convertCandidates(candidates []*genai.Candidate, usage *genai.UsageMetadata
 				ToolCalls:      toolCalls,
 			})
 	}
+
+	if usage != nil {
+		// The genai package doesn't expose the thoughts token count of
+		// thinking models, which the total includes but not the candidates.
+		thoughts := max(usage.TotalTokenCount-usage.PromptTokenCount-usage.CandidatesTokenCount, 0)
+		contentResponse.Usage = &llms.Usage{
+			InputTokens:     int(usage.PromptTokenCount),
+			OutputTokens:    int(usage.CandidatesTokenCount + thoughts),
+			CachedTokens:    int(usage.CachedContentTokenCount),
+			ReasoningTokens: int(thoughts),
+		}
+	}
 	return &contentResponse, nil
 }
 
@@ -412,6 +438,24 @@ func convertTools(tools []llms.Tool) ([]*genai.Tool, error) {
 	return []*genai.Tool{&genaiTool}, nil
 }
 
+// convertResponseSchema converts a response schema, typically a
+// jsonschema.Definition, to a genai schema.
+func convertResponseSchema(rs *llms.ResponseSchema) (*genai.Schema, error) {
+	data, err := json.Marshal(rs.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("response schema: %w", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("response schema: %w", err)
+	}
+	schema, err := convertToSchema(m, false)
+	if err != nil {
+		return nil, fmt.Errorf("response schema: %w", err)
+	}
+	return schema, nil
+}
+
 // convert map[any]any to map[string]any if possible
 func convertMaps(i any) any {
 	switch v := i.(type) {
@@ -444,12 +488,17 @@ func convertToSchema(e any, topLevel bool) (*genai.Schema, error) {
 		return nil, fmt.Errorf("tool: unsupported type %T of Parameters", e)
 	}
 
+	if anyOf, ok := eMap["anyOf"].([]any); ok {
+		return convertNullableSchema(eMap, anyOf, topLevel)
+	}
+
 	if ty, ok := eMap["type"]; ok {
-		tyString, ok := ty.(string)
-		if !ok {
-			return nil, fmt.Errorf("tool: expected string for type")
+		tyString, nullable, err := convertSchemaType(ty)
+		if err != nil {
+			return nil, err
 		}
 		schema.Type = convertToolSchemaType(tyString)
+		schema.Nullable = nullable
 
 		if topLevel && schema.Type != genai.TypeObject {
 			return nil, fmt.Errorf("tool: top-level schema must be an object")
@@ -521,6 +570,43 @@ func convertToSchema(e any, topLevel bool) (*genai.Schema, error) {
 	return schema, nil
 }
 
+// convertSchemaType returns the type of a schema, which is either a string or
+// a type and "null" as jsonschema.Definition encodes nullable types.
+func convertSchemaType(ty any) (string, bool, error) {
+	if tyString, ok := ty.(string); ok {
+		return tyString, false, nil
+	}
+	types, err := convertToSliceOfStrings(ty)
+	if err != nil || len(types) != 2 || !slices.Contains(types, "null") {
+		return "", false, fmt.Errorf("tool: expected string or a type and \"null\" for type")
+	}
+	if types[0] == "null" {
+		return types[1], true, nil
+	}
+	return types[0], true, nil
+}
+
+// convertNullableSchema converts an anyOf of a schema and a null schema, as
+// jsonschema.Definition encodes nullable enums, to a nullable genai schema.
+func convertNullableSchema(eMap map[string]any, anyOf []any, topLevel bool) (*genai.Schema, error) {
+	if len(anyOf) == 2 {
+		for i, s := range anyOf {
+			if m, ok := s.(map[string]any); ok && m["type"] == "null" {
+				schema, err := convertToSchema(anyOf[1-i], topLevel)
+				if err != nil {
+					return nil, err
+				}
+				schema.Nullable = true
+				if description, ok := eMap["description"].(string); ok && schema.Description == "" {
+					schema.Description = description
+				}
+				return schema, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("tool: anyOf is only supported with a null schema")
+}
+
 func convertToSliceOfStrings(e any) ([]string, error) {
 	if rs, ok := e.([]string); ok {
 		return rs, nil
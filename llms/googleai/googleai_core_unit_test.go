@@ -370,6 +370,60 @@ func TestConvertCandidates(t *testing.T) { //nolint:funlen // comprehensive test
 	}
 }
 
+func TestConvertCandidatesUsage(t *testing.T) {
+	t.Parallel()
+
+	candidates := []*genai.Candidate{
+		{Content: &genai.Content{Parts: []genai.Part{genai.Text("Response")}}},
+	}
+	tests := []struct {
+		name  string
+		usage *genai.UsageMetadata
+		want  *llms.Usage
+	}{
+		{
+			name: "no usage",
+		},
+		{
+			name: "usage",
+			usage: &genai.UsageMetadata{
+				PromptTokenCount:     10,
+				CandidatesTokenCount: 5,
+				TotalTokenCount:      15,
+			},
+			want: &llms.Usage{InputTokens: 10, OutputTokens: 5},
+		},
+		{
+			name: "cached content",
+			usage: &genai.UsageMetadata{
+				PromptTokenCount:        10,
+				CachedContentTokenCount: 8,
+				CandidatesTokenCount:    5,
+				TotalTokenCount:         15,
+			},
+			want: &llms.Usage{InputTokens: 10, OutputTokens: 5, CachedTokens: 8},
+		},
+		{
+			name: "thinking",
+			usage: &genai.UsageMetadata{
+				PromptTokenCount:     10,
+				CandidatesTokenCount: 5,
+				TotalTokenCount:      35,
+			},
+			want: &llms.Usage{InputTokens: 10, OutputTokens: 25, ReasoningTokens: 20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := convertCandidates(candidates, tt.usage)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, result.Usage)
+		})
+	}
+}
+
 func TestCall(t *testing.T) {
 	t.Parallel()
 
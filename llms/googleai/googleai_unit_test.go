@@ -3,8 +3,10 @@ package googleai
 import (
 	"testing"
 
+	"github.com/sayerxofficial/langchaingo/jsonschema"
 	"github.com/sayerxofficial/langchaingo/llms"
 
+	"github.com/google/generative-ai-go/genai"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -405,3 +407,52 @@ func TestConvertTools(t *testing.T) { //nolint:funlen // comprehensive test //no
 		assert.Contains(t, funcDecl.Parameters.Required, "location")
 	})
 }
+
+func TestConvertResponseSchema(t *testing.T) {
+	t.Parallel()
+
+	schema, err := convertResponseSchema(&llms.ResponseSchema{
+		Name: "answer",
+		Schema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"city": map[string]any{"type": "string", "description": "The city"},
+				"tags": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			},
+			"required": []string{"city"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, genai.TypeObject, schema.Type)
+	assert.Equal(t, []string{"city"}, schema.Required)
+	assert.Equal(t, "The city", schema.Properties["city"].Description)
+	assert.Equal(t, genai.TypeString, schema.Properties["tags"].Items.Type)
+
+	_, err = convertResponseSchema(&llms.ResponseSchema{Schema: "not a schema"})
+	assert.Error(t, err)
+}
+
+func TestConvertResponseSchema_Nullable(t *testing.T) {
+	t.Parallel()
+
+	schema, err := convertResponseSchema(&llms.ResponseSchema{
+		Schema: jsonschema.Definition{
+			Type: jsonschema.Object,
+			Properties: map[string]jsonschema.Definition{
+				"note":  {Type: jsonschema.String, Nullable: true},
+				"color": {Type: jsonschema.String, Enum: []string{"red", "blue"}, Nullable: true, Description: "a color"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	note := schema.Properties["note"]
+	assert.Equal(t, genai.TypeString, note.Type)
+	assert.True(t, note.Nullable)
+
+	color := schema.Properties["color"]
+	assert.Equal(t, genai.TypeString, color.Type)
+	assert.True(t, color.Nullable)
+	assert.Equal(t, []string{"red", "blue"}, color.Enum)
+	assert.Equal(t, "a color", color.Description)
+}
@@ -201,6 +201,8 @@ func testMultiContentText(t *testing.T, llm llms.Model) {
 	assert.Regexp(t, "(?i)dog|carnivo|canid|canine", c1.Content)
 	assert.Contains(t, c1.GenerationInfo, "output_tokens")
 	assert.NotZero(t, c1.GenerationInfo["output_tokens"])
+	require.NotNil(t, resp.Usage)
+	assert.NotZero(t, resp.Usage.OutputTokens)
 }
 
 func testMultiContentTextUsingTextParts(t *testing.T, llm llms.Model) {
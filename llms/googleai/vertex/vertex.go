@@ -177,6 +177,20 @@ func convertCandidates(candidates []*genai.Candidate, usage *genai.UsageMetadata
 				ToolCalls:      toolCalls,
 			})
 	}
+
+	if usage != nil {
+		// Unlike the googleai package, the Vertex AI genai package doesn't
+		// expose the cached content token count of the response, so
+		// CachedTokens stays zero.
+		// Nor does it expose the thoughts token count of thinking models,
+		// which the total includes but not the candidates.
+		thoughts := max(usage.TotalTokenCount-usage.PromptTokenCount-usage.CandidatesTokenCount, 0)
+		contentResponse.Usage = &llms.Usage{
+			InputTokens:     int(usage.PromptTokenCount),
+			OutputTokens:    int(usage.CandidatesTokenCount + thoughts),
+			ReasoningTokens: int(thoughts),
+		}
+	}
 	return &contentResponse, nil
 }
 
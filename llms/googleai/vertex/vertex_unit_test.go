@@ -2,6 +2,7 @@ package vertex
 
 import (
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -449,6 +450,51 @@ func TestConvertCandidates(t *testing.T) { //nolint:funlen // comprehensive test
 	}
 }
 
+func TestConvertCandidatesUsage(t *testing.T) {
+	candidates := []*genai.Candidate{
+		{Content: &genai.Content{Parts: []genai.Part{genai.Text("Response")}}},
+	}
+	tests := []struct {
+		name  string
+		usage *genai.UsageMetadata
+		want  *llms.Usage
+	}{
+		{
+			name: "no usage",
+		},
+		{
+			name: "usage",
+			usage: &genai.UsageMetadata{
+				PromptTokenCount:     10,
+				CandidatesTokenCount: 5,
+				TotalTokenCount:      15,
+			},
+			want: &llms.Usage{InputTokens: 10, OutputTokens: 5},
+		},
+		{
+			name: "thinking",
+			usage: &genai.UsageMetadata{
+				PromptTokenCount:     10,
+				CandidatesTokenCount: 5,
+				TotalTokenCount:      35,
+			},
+			want: &llms.Usage{InputTokens: 10, OutputTokens: 25, ReasoningTokens: 20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := convertCandidates(candidates, tt.usage)
+			if err != nil {
+				t.Fatalf("convertCandidates() error = %v", err)
+			}
+			if !reflect.DeepEqual(result.Usage, tt.want) {
+				t.Errorf("expected usage %+v, got %+v", tt.want, result.Usage)
+			}
+		})
+	}
+}
+
 // Note: We cannot create a custom type that implements genai.Part
 // because it has an unexported method toPart()
 
@@ -42,6 +42,9 @@ func GenerateFromSinglePrompt(ctx context.Context, llm Model, prompt string, opt
 	if err != nil {
 		return "", err
 	}
+	if resp != nil {
+		reportUsage(ctx, resp.Usage)
+	}
 
 	choices := resp.Choices
 	if len(choices) < 1 {
@@ -50,3 +53,29 @@ func GenerateFromSinglePrompt(ctx context.Context, llm Model, prompt string, opt
 	c1 := choices[0]
 	return c1.Content, nil
 }
+
+type usageHookKey struct{}
+
+// WithUsageHook returns a context in which GenerateFromSinglePrompt reports
+// the usage of every response to hook, so that callers of chains that only
+// return text, such as chains.LLMChain, can account for the tokens they use.
+// Hooks already set on ctx are called as well.
+func WithUsageHook(ctx context.Context, hook func(ctx context.Context, usage *Usage)) context.Context {
+	if previous, ok := ctx.Value(usageHookKey{}).(func(context.Context, *Usage)); ok {
+		next := hook
+		hook = func(ctx context.Context, usage *Usage) {
+			previous(ctx, usage)
+			next(ctx, usage)
+		}
+	}
+	return context.WithValue(ctx, usageHookKey{}, hook)
+}
+
+// reportUsage calls the usage hooks of ctx.
+func reportUsage(ctx context.Context, usage *Usage) {
+	hook, ok := ctx.Value(usageHookKey{}).(func(context.Context, *Usage))
+	if !ok || usage == nil {
+		return
+	}
+	hook(ctx, usage)
+}
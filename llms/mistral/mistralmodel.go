@@ -160,6 +160,7 @@ func generateNonStreamingContent(ctx context.Context, m *Model, callOptions *llm
 
 	langchainContentResponse := &llms.ContentResponse{
 		Choices: make([]*llms.ContentChoice, 0),
+		Usage:   convertUsage(res.Usage),
 	}
 	for idx, choice := range res.Choices {
 		langchainContentResponse.Choices = append(langchainContentResponse.Choices, &llms.ContentChoice{
@@ -211,6 +212,9 @@ func generateStreamingContent(ctx context.Context, m *Model, callOptions *llms.C
 		langchainContentResponse.Choices[0].GenerationInfo["created"] = chatResChunk.Created
 		langchainContentResponse.Choices[0].GenerationInfo["model"] = chatResChunk.Model
 		langchainContentResponse.Choices[0].GenerationInfo["usage"] = chatResChunk.Usage
+		if chatResChunk.Usage.TotalTokens > 0 {
+			langchainContentResponse.Usage = convertUsage(chatResChunk.Usage)
+		}
 		if chatResChunk.Error == nil {
 			for _, choice := range chatResChunk.Choices {
 				chunkStr += choice.Delta.Content
@@ -241,6 +245,13 @@ func generateStreamingContent(ctx context.Context, m *Model, callOptions *llms.C
 	return langchainContentResponse, nil
 }
 
+func convertUsage(usage sdk.UsageInfo) *llms.Usage {
+	return &llms.Usage{
+		InputTokens:  usage.PromptTokens,
+		OutputTokens: usage.CompletionTokens,
+	}
+}
+
 func convertToMistralChatMessages(langchainMessages []llms.MessageContent) ([]sdk.ChatMessage, error) {
 	messages := make([]sdk.ChatMessage, 0)
 	for _, msg := range langchainMessages {
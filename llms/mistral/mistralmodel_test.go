@@ -480,3 +480,28 @@ func TestGenerateContent(t *testing.T) {
 	// This test requires mocking the Mistral SDK client
 	t.Skip("GenerateContent() requires integration testing with mock Mistral client")
 }
+
+func TestConvertUsage(t *testing.T) {
+	tests := []struct {
+		name  string
+		usage sdk.UsageInfo
+		want  llms.Usage
+	}{
+		{
+			name: "no usage",
+		},
+		{
+			name:  "usage",
+			usage: sdk.UsageInfo{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+			want:  llms.Usage{InputTokens: 10, OutputTokens: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := convertUsage(tt.usage); *got != tt.want {
+				t.Errorf("convertUsage() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
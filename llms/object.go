@@ -0,0 +1,206 @@
+package llms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/sayerxofficial/langchaingo/jsonschema"
+)
+
+// ErrInvalidObject is returned by GenerateObject when the model does not
+// produce JSON that matches the schema of the requested type.
+var ErrInvalidObject = errors.New("response does not match the schema")
+
+// objectValueKey is the property that holds non-object types, since most
+// providers only accept an object at the top level of a response schema.
+const objectValueKey = "value"
+
+var schemaNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ObjectOption is a functional argument that configures GenerateObject.
+type ObjectOption func(*objectOptions)
+
+type objectOptions struct {
+	name        string
+	description string
+	strict      bool
+	maxRepairs  int
+	callOptions []CallOption
+}
+
+// WithObjectName sets the name of the response schema. Defaults to the name
+// of the requested type.
+func WithObjectName(name string) ObjectOption {
+	return func(o *objectOptions) {
+		o.name = name
+	}
+}
+
+// WithObjectDescription sets the description of the response schema, which
+// tells the model what the object is for.
+func WithObjectDescription(description string) ObjectOption {
+	return func(o *objectOptions) {
+		o.description = description
+	}
+}
+
+// WithObjectStrict asks models that support it to follow the schema exactly.
+// See ResponseSchema.Strict.
+func WithObjectStrict() ObjectOption {
+	return func(o *objectOptions) {
+		o.strict = true
+	}
+}
+
+// WithObjectRepairs sets how many times the model is asked again, with the
+// validation error, when its response does not match the schema. Defaults
+// to 0.
+func WithObjectRepairs(n int) ObjectOption {
+	return func(o *objectOptions) {
+		o.maxRepairs = n
+	}
+}
+
+// WithObjectCallOptions sets the options the model is called with.
+func WithObjectCallOptions(options ...CallOption) ObjectOption {
+	return func(o *objectOptions) {
+		o.callOptions = append(o.callOptions, options...)
+	}
+}
+
+// GenerateObject asks the model for a JSON value matching the schema of T
+// and decodes it. Models that implement StructuredOutputModel are
+// constrained natively through WithResponseSchema; other models are given
+// the schema in the prompt. The response is validated against the schema
+// before it is decoded, and the model can be asked to repair an invalid
+// response with WithObjectRepairs.
+func GenerateObject[T any](ctx context.Context, model Model, messages []MessageContent, options ...ObjectOption) (T, error) { //nolint:lll
+	var zero T
+
+	opts := objectOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	typ := reflect.TypeFor[T]()
+	def, err := jsonschema.Reflect(new(T))
+	if err != nil {
+		return zero, err
+	}
+	wrapped := def.Type != jsonschema.Object
+	if wrapped {
+		def = jsonschema.Definition{
+			Type:       jsonschema.Object,
+			Properties: map[string]jsonschema.Definition{objectValueKey: def},
+			Required:   []string{objectValueKey},
+		}
+	}
+
+	rs := &ResponseSchema{
+		Name:        objectName(opts.name, typ),
+		Description: opts.description,
+		Schema:      def,
+		Strict:      opts.strict,
+	}
+	callOptions := append(opts.callOptions[:len(opts.callOptions):len(opts.callOptions)], WithResponseSchema(rs))
+
+	messages = messages[:len(messages):len(messages)]
+	if so, ok := model.(StructuredOutputModel); !ok || !so.SupportsStructuredOutput() {
+		instructions, err := objectInstructions(rs)
+		if err != nil {
+			return zero, err
+		}
+		messages = append(messages, TextParts(ChatMessageTypeHuman, instructions))
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := model.GenerateContent(ctx, messages, callOptions...)
+		if err != nil {
+			return zero, err
+		}
+
+		content, err := objectContent(resp, rs.Name)
+		if err == nil {
+			err = def.Validate([]byte(content))
+		}
+		if err == nil {
+			var v T
+			if err = decodeObject(content, wrapped, &v); err == nil {
+				return v, nil
+			}
+		}
+
+		if attempt >= opts.maxRepairs {
+			return zero, fmt.Errorf("%w: %w", ErrInvalidObject, err)
+		}
+		messages = append(messages,
+			TextParts(ChatMessageTypeAI, content),
+			TextParts(ChatMessageTypeHuman, fmt.Sprintf(
+				"Your response does not match the JSON schema: %v. Respond again with only the corrected JSON.", err)),
+		)
+	}
+}
+
+// objectName returns name, or the name of typ if it is a valid schema name.
+func objectName(name string, typ reflect.Type) string {
+	if name != "" {
+		return name
+	}
+	if schemaNamePattern.MatchString(typ.Name()) {
+		return typ.Name()
+	}
+	return "response"
+}
+
+func objectInstructions(rs *ResponseSchema) (string, error) {
+	schema, err := json.Marshal(rs.Schema)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	sb.WriteString("Respond only with a JSON object that conforms to the JSON schema below, without any other text.\n")
+	if rs.Description != "" {
+		fmt.Fprintf(&sb, "The object is %s.\n", rs.Description)
+	}
+	fmt.Fprintf(&sb, "```json\n%s\n```", schema)
+	return sb.String(), nil
+}
+
+// objectContent extracts the JSON text of a response. It accepts text
+// wrapped in a markdown code block, and a tool call named after the schema
+// for models that answer with one.
+func objectContent(resp *ContentResponse, name string) (string, error) {
+	if resp == nil || len(resp.Choices) == 0 {
+		return "", errors.New("empty response from model")
+	}
+	choice := resp.Choices[0]
+	content := strings.TrimSpace(choice.Content)
+	if content == "" {
+		for _, tc := range choice.ToolCalls {
+			if tc.FunctionCall != nil && tc.FunctionCall.Name == name {
+				return tc.FunctionCall.Arguments, nil
+			}
+		}
+		return "", errors.New("response has no content")
+	}
+	if start, end := strings.Index(content, "{"), strings.LastIndex(content, "}"); start >= 0 && end > start {
+		content = content[start : end+1]
+	}
+	return content, nil
+}
+
+func decodeObject(content string, wrapped bool, v any) error {
+	if !wrapped {
+		return json.Unmarshal([]byte(content), v)
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(content), &obj); err != nil {
+		return err
+	}
+	return json.Unmarshal(obj[objectValueKey], v)
+}
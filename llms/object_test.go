@@ -0,0 +1,148 @@
+package llms_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/jsonschema"
+	"github.com/sayerxofficial/langchaingo/llms"
+
+	"github.com/stretchr/testify/require"
+)
+
+type Recipe struct {
+	Name        string   `json:"name"`
+	Ingredients []string `json:"ingredients"`
+	Servings    int      `json:"servings"`
+	Notes       *string  `json:"notes,omitempty"`
+}
+
+// scriptedModel answers calls with canned responses and records the
+// messages and options it was called with.
+type scriptedModel struct {
+	responses  []string
+	structured bool
+	messages   [][]llms.MessageContent
+	options    []llms.CallOptions
+}
+
+func (m *scriptedModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *scriptedModel) GenerateContent(_ context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+	m.messages = append(m.messages, messages)
+	m.options = append(m.options, opts)
+
+	content := m.responses[0]
+	m.responses = m.responses[1:]
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: content}}}, nil
+}
+
+func (m *scriptedModel) SupportsStructuredOutput() bool {
+	return m.structured
+}
+
+func TestGenerateObject_Native(t *testing.T) {
+	t.Parallel()
+	rq := require.New(t)
+
+	model := &scriptedModel{
+		structured: true,
+		responses:  []string{`{"name":"Pancakes","ingredients":["flour","milk"],"servings":4}`},
+	}
+	messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "A recipe please")}
+
+	recipe, err := llms.GenerateObject[Recipe](t.Context(), model, messages,
+		llms.WithObjectCallOptions(llms.WithTemperature(0.2)))
+	rq.NoError(err)
+	rq.Equal(Recipe{Name: "Pancakes", Ingredients: []string{"flour", "milk"}, Servings: 4}, recipe)
+
+	// The schema is passed natively and the prompt is left untouched.
+	rq.Equal(messages, model.messages[0])
+	opts := model.options[0]
+	rq.InDelta(0.2, opts.Temperature, 0.0001)
+	rq.NotNil(opts.ResponseSchema)
+	rq.Equal("Recipe", opts.ResponseSchema.Name)
+	def, ok := opts.ResponseSchema.Schema.(jsonschema.Definition)
+	rq.True(ok)
+	rq.Equal(jsonschema.Object, def.Type)
+	rq.ElementsMatch([]string{"name", "ingredients", "servings"}, def.Required)
+	rq.Equal(jsonschema.Array, def.Properties["ingredients"].Type)
+	rq.Equal(jsonschema.String, def.Properties["ingredients"].Items.Type)
+}
+
+func TestGenerateObject_PromptFallback(t *testing.T) {
+	t.Parallel()
+	rq := require.New(t)
+
+	model := &scriptedModel{
+		responses: []string{"Sure!\n```json\n{\"name\":\"Soup\",\"ingredients\":[],\"servings\":2}\n```"},
+	}
+	messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "A recipe please")}
+
+	recipe, err := llms.GenerateObject[Recipe](t.Context(), model, messages)
+	rq.NoError(err)
+	rq.Equal("Soup", recipe.Name)
+
+	sent := model.messages[0]
+	rq.Len(sent, 2)
+	instructions := sent[1].Parts[0].(llms.TextContent).Text
+	rq.Contains(instructions, "JSON schema")
+	rq.Contains(instructions, `"servings"`)
+}
+
+func TestGenerateObject_Repair(t *testing.T) {
+	t.Parallel()
+	rq := require.New(t)
+
+	model := &scriptedModel{
+		structured: true,
+		responses: []string{
+			`{"name":"Soup","ingredients":["water"],"servings":"two"}`,
+			`{"name":"Soup","ingredients":["water"],"servings":2}`,
+		},
+	}
+	messages := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "A recipe please")}
+
+	// Without repairs the invalid response is an error.
+	_, err := llms.GenerateObject[Recipe](t.Context(), model, messages)
+	rq.ErrorIs(err, llms.ErrInvalidObject)
+	rq.Contains(err.Error(), "$.servings: expected integer")
+
+	model.responses = []string{
+		`{"name":"Soup","ingredients":["water"],"servings":"two"}`,
+		`{"name":"Soup","ingredients":["water"],"servings":2}`,
+	}
+	model.messages = nil
+	recipe, err := llms.GenerateObject[Recipe](t.Context(), model, messages, llms.WithObjectRepairs(1))
+	rq.NoError(err)
+	rq.Equal(2, recipe.Servings)
+
+	// The second call carries the invalid response and the validation error.
+	rq.Len(model.messages, 2)
+	repair := model.messages[1]
+	rq.Len(repair, 3)
+	rq.Equal(llms.ChatMessageTypeAI, repair[1].Role)
+	rq.Contains(repair[2].Parts[0].(llms.TextContent).Text, "expected integer")
+}
+
+func TestGenerateObject_NonObject(t *testing.T) {
+	t.Parallel()
+	rq := require.New(t)
+
+	model := &scriptedModel{
+		structured: true,
+		responses:  []string{`{"value":["a","b"]}`},
+	}
+
+	tags, err := llms.GenerateObject[[]string](t.Context(), model,
+		[]llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Some tags")})
+	rq.NoError(err)
+	rq.Equal([]string{"a", "b"}, tags)
+	rq.Equal("response", model.options[0].ResponseSchema.Name)
+}
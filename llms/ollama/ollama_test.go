@@ -13,6 +13,7 @@ import (
 	"github.com/sayerxofficial/langchaingo/llms"
 	"github.com/sayerxofficial/langchaingo/llms/streaming"
 
+	"github.com/ollama/ollama/api"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -386,3 +387,31 @@ func TestWithPullTimeout(t *testing.T) {
 		t.Fatalf("Expected timeout error, got: %v", err)
 	}
 }
+
+func TestCreateContentResponseUsage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		metrics api.Metrics
+		want    *llms.Usage
+	}{
+		{
+			name: "no metrics",
+			want: &llms.Usage{},
+		},
+		{
+			name:    "metrics",
+			metrics: api.Metrics{PromptEvalCount: 10, EvalCount: 5},
+			want:    &llms.Usage{InputTokens: 10, OutputTokens: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			resp := (&LLM{}).createContentResponse(api.ChatResponse{Metrics: tt.metrics})
+			assert.Equal(t, tt.want, resp.Usage)
+		})
+	}
+}
@@ -333,7 +333,13 @@ func (o *LLM) createContentResponse(resp api.ChatResponse) *llms.ContentResponse
 		})
 	}
 
-	return &llms.ContentResponse{Choices: choices}
+	return &llms.ContentResponse{
+		Choices: choices,
+		Usage: &llms.Usage{
+			InputTokens:  resp.PromptEvalCount,
+			OutputTokens: resp.EvalCount,
+		},
+	}
 }
 
 func (o *LLM) CreateEmbedding(ctx context.Context, inputTexts []string) ([][]float32, error) {
@@ -142,6 +142,26 @@ type ResponseFormatJSONSchema struct {
 	Name   string                            `json:"name"`
 	Strict bool                              `json:"strict"`
 	Schema *ResponseFormatJSONSchemaProperty `json:"schema"`
+	// RawSchema is a JSON Schema sent as is instead of Schema, for schemas
+	// that ResponseFormatJSONSchemaProperty can't represent.
+	RawSchema json.RawMessage `json:"-"`
+}
+
+// MarshalJSON implements json.Marshaler, sending RawSchema if set.
+func (s ResponseFormatJSONSchema) MarshalJSON() ([]byte, error) {
+	if s.RawSchema == nil {
+		type alias ResponseFormatJSONSchema
+		return json.Marshal(alias(s))
+	}
+	return json.Marshal(struct {
+		Name   string          `json:"name"`
+		Strict bool            `json:"strict"`
+		Schema json.RawMessage `json:"schema"`
+	}{
+		Name:   s.Name,
+		Strict: s.Strict,
+		Schema: s.RawSchema,
+	})
 }
 
 // ResponseFormat is the format of the response.
@@ -334,6 +354,9 @@ type ChatUsage struct {
 	CompletionTokensDetails struct {
 		ReasoningTokens int `json:"reasoning_tokens"`
 	} `json:"completion_tokens_details"`
+	PromptTokensDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"prompt_tokens_details"`
 }
 
 // ChatCompletionResponse is a response to a chat request.
@@ -354,6 +377,9 @@ type Usage struct {
 	CompletionTokensDetails struct {
 		ReasoningTokens int `json:"reasoning_tokens"`
 	} `json:"completion_tokens_details"`
+	PromptTokensDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"prompt_tokens_details"`
 }
 
 // StreamedToolCall is a call to a tool.
@@ -461,16 +487,7 @@ func (c *Client) createChat(ctx context.Context, payload *ChatRequest) (*ChatCom
 	defer r.Body.Close()
 
 	if r.StatusCode != http.StatusOK {
-		msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
-
-		// No need to check the error here: if it fails, we'll just return the
-		// status code.
-		var errResp errorMessage
-		if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
-			return nil, errors.New(msg)
-		}
-
-		return nil, fmt.Errorf("%s: %s", msg, errResp.Error.Message)
+		return nil, decodeError(r)
 	}
 	if payload.Stream {
 		return parseStreamingChatResponse(ctx, r, payload)
@@ -660,6 +677,7 @@ func updateChatUsage(chatUsage *ChatUsage, streamUsage *Usage) {
 	chatUsage.PromptTokens = streamUsage.PromptTokens
 	chatUsage.TotalTokens = streamUsage.TotalTokens
 	chatUsage.CompletionTokensDetails.ReasoningTokens = streamUsage.CompletionTokensDetails.ReasoningTokens
+	chatUsage.PromptTokensDetails.CachedTokens = streamUsage.PromptTokensDetails.CachedTokens
 }
 
 func updateFunctionCall(message *ChatMessage, functionCall *FunctionCall) {
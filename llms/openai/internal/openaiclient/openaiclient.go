@@ -2,10 +2,13 @@ package openaiclient
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/sayerxofficial/langchaingo/httputil"
 )
 
 const (
@@ -188,3 +191,17 @@ func (c *Client) buildAzureURL(suffix string, model string) string {
 		baseURL, model, suffix, c.apiVersion,
 	)
 }
+
+// decodeError returns the error for a response with an unexpected status,
+// with the delay of its Retry-After header if any.
+func decodeError(r *http.Response) error {
+	msg := fmt.Sprintf("API returned unexpected status code: %d", r.StatusCode)
+
+	// No need to check the error here: if it fails, we'll just return the
+	// status code.
+	var errResp errorMessage
+	if err := json.NewDecoder(r.Body).Decode(&errResp); err != nil {
+		return httputil.WithRetryAfter(errors.New(msg), r)
+	}
+	return httputil.WithRetryAfter(fmt.Errorf("%s: %s", msg, errResp.Error.Message), r)
+}
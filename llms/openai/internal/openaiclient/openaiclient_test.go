@@ -2,10 +2,14 @@ package openaiclient
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/sayerxofficial/langchaingo/httputil"
 	"github.com/sayerxofficial/langchaingo/internal/httprr"
 	"github.com/sayerxofficial/langchaingo/llms/streaming"
 
@@ -222,3 +226,26 @@ func TestClient_WithResponseFormat(t *testing.T) {
 	assert.NotEmpty(t, resp.Choices)
 	assert.NotEmpty(t, resp.Choices[0].Message.Content)
 }
+
+func TestClient_RetryAfter(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"error": {"message": "Rate limit reached", "type": "requests"}}`)
+	}))
+	defer server.Close()
+
+	client, err := New("test-api-key", "gpt-4o", server.URL, "", APITypeOpenAI, "", http.DefaultClient, "", nil, false, false)
+	require.NoError(t, err)
+
+	_, err = client.CreateChat(t.Context(), &ChatRequest{})
+	require.ErrorContains(t, err, "Rate limit reached")
+	var retryAfter *httputil.RetryAfterError
+	require.ErrorAs(t, err, &retryAfter)
+	assert.Equal(t, 7*time.Second, retryAfter.Delay)
+
+	_, err = client.CreateEmbedding(t.Context(), &EmbeddingRequest{Input: []string{"hello"}})
+	require.ErrorAs(t, err, &retryAfter)
+}
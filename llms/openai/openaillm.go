@@ -2,6 +2,7 @@ package openai
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/sayerxofficial/langchaingo/callbacks"
@@ -14,6 +15,7 @@ type ChatMessage = openaiclient.ChatMessage
 type LLM struct {
 	CallbacksHandler callbacks.Handler
 	client           *openaiclient.Client
+	structuredOutput bool
 }
 
 const (
@@ -24,7 +26,10 @@ const (
 	RoleTool      = "tool"
 )
 
-var _ llms.Model = (*LLM)(nil)
+var (
+	_ llms.Model                 = (*LLM)(nil)
+	_ llms.StructuredOutputModel = (*LLM)(nil)
+)
 
 // New returns a new OpenAI LLM.
 func New(opts ...Option) (*LLM, error) {
@@ -35,6 +40,7 @@ func New(opts ...Option) (*LLM, error) {
 	return &LLM{
 		client:           c,
 		CallbacksHandler: opt.callbackHandler,
+		structuredOutput: opt.supportsStructuredOutput(),
 	}, err
 }
 
@@ -204,6 +210,35 @@ func (o *LLM) handleToolMessage(mc llms.MessageContent) error {
 	return nil
 }
 
+// SupportsStructuredOutput reports whether the model honors
+// llms.WithResponseSchema through the json_schema response format. This is
+// the case for the OpenAI API, and for other servers enabled with
+// WithStructuredOutput.
+func (o *LLM) SupportsStructuredOutput() bool {
+	return o.structuredOutput
+}
+
+// responseFormatFromSchema converts a response schema to a json_schema
+// response format. The schema is sent as is, so keywords such as object
+// additionalProperties and nullable types are preserved.
+func responseFormatFromSchema(rs *llms.ResponseSchema) (*ResponseFormat, error) {
+	data, err := json.Marshal(rs.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("openai: invalid response schema: %w", err)
+	}
+	if len(data) == 0 || data[0] != '{' {
+		return nil, fmt.Errorf("openai: invalid response schema: expected a JSON object, got %s", data)
+	}
+	return &ResponseFormat{
+		Type: "json_schema",
+		JSONSchema: &ResponseFormatJSONSchema{
+			Name:      rs.Name,
+			Strict:    rs.Strict,
+			RawSchema: data,
+		},
+	}, nil
+}
+
 // createChatRequest creates an OpenAI chat request with the given parameters.
 func (o *LLM) createChatRequest(chatMsgs []*ChatMessage, opts llms.CallOptions) (*openaiclient.ChatRequest, error) {
 	req := &openaiclient.ChatRequest{
@@ -236,6 +271,15 @@ func (o *LLM) createChatRequest(chatMsgs []*ChatMessage, opts llms.CallOptions)
 		req.ResponseFormat = o.client.ResponseFormat
 	}
 
+	// a response schema passed with the call takes precedence
+	if opts.ResponseSchema != nil {
+		format, err := responseFormatFromSchema(opts.ResponseSchema)
+		if err != nil {
+			return nil, err
+		}
+		req.ResponseFormat = format
+	}
+
 	// set reasoning options, depends on the client and request options
 	o.setReasoning(req, opts)
 
@@ -323,7 +367,15 @@ func (o *LLM) processResponse(result *openaiclient.ChatCompletionResponse) *llms
 		o.processToolCalls(choices[i], c)
 	}
 
-	return &llms.ContentResponse{Choices: choices}
+	return &llms.ContentResponse{
+		Choices: choices,
+		Usage: &llms.Usage{
+			InputTokens:     result.Usage.PromptTokens,
+			OutputTokens:    result.Usage.CompletionTokens,
+			CachedTokens:    result.Usage.PromptTokensDetails.CachedTokens,
+			ReasoningTokens: result.Usage.CompletionTokensDetails.ReasoningTokens,
+		},
+	}
 }
 
 // processToolCalls processes tool calls in the response.
@@ -1,6 +1,8 @@
 package openai
 
 import (
+	"net/url"
+
 	"github.com/sayerxofficial/langchaingo/callbacks"
 	"github.com/sayerxofficial/langchaingo/llms/openai/internal/openaiclient"
 )
@@ -34,6 +36,9 @@ type options struct {
 	httpClient   openaiclient.Doer
 
 	responseFormat *ResponseFormat
+	// structuredOutput overrides whether the server supports the
+	// json_schema response format.
+	structuredOutput *bool
 
 	// fine tuning reasoning options for various LLM providers
 	useReasoningMaxTokens bool
@@ -133,6 +138,16 @@ func WithCallback(callbackHandler callbacks.Handler) Option {
 	}
 }
 
+// WithStructuredOutput reports whether the server supports the json_schema
+// response format used by llms.WithResponseSchema. If not set, it is assumed
+// for the OpenAI API only, and other servers set with WithBaseURL or the
+// Azure API fall back to prompting for JSON.
+func WithStructuredOutput(enabled bool) Option {
+	return func(opts *options) {
+		opts.structuredOutput = &enabled
+	}
+}
+
 // WithResponseFormat allows setting a custom response format.
 func WithResponseFormat(responseFormat *ResponseFormat) Option {
 	return func(opts *options) {
@@ -156,3 +171,19 @@ func WithModernReasoningFormat() Option {
 		opts.modernReasoningFormat = true
 	}
 }
+
+// supportsStructuredOutput reports whether the configured server supports the
+// json_schema response format.
+func (o *options) supportsStructuredOutput() bool {
+	if o.structuredOutput != nil {
+		return *o.structuredOutput
+	}
+	if openaiclient.IsAzure(openaiclient.APIType(o.apiType)) {
+		return false
+	}
+	if o.baseURL == "" {
+		return true
+	}
+	u, err := url.Parse(o.baseURL)
+	return err == nil && u.Hostname() == "api.openai.com"
+}
@@ -0,0 +1,49 @@
+package openai
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/llms/openai/internal/openaiclient"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessResponseUsage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		usage string
+		want  *llms.Usage
+	}{
+		{
+			name:  "no usage",
+			usage: `{}`,
+			want:  &llms.Usage{},
+		},
+		{
+			name:  "usage",
+			usage: `{"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15}`,
+			want:  &llms.Usage{InputTokens: 10, OutputTokens: 5},
+		},
+		{
+			name: "cached and reasoning tokens",
+			usage: `{"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15,
+				"prompt_tokens_details": {"cached_tokens": 8},
+				"completion_tokens_details": {"reasoning_tokens": 3}}`,
+			want: &llms.Usage{InputTokens: 10, OutputTokens: 5, CachedTokens: 8, ReasoningTokens: 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var result openaiclient.ChatCompletionResponse
+			require.NoError(t, json.Unmarshal([]byte(`{"choices": [{"message": {"content": "hi"}}], "usage": `+tt.usage+`}`), &result))
+			assert.Equal(t, tt.want, (&LLM{}).processResponse(&result).Usage)
+		})
+	}
+}
@@ -172,3 +172,81 @@ func TestStructuredOutputFunctionCalling(t *testing.T) {
 	assert.Regexp(t, "\"search_engine\":", c1.ToolCalls[0].FunctionCall.Arguments)
 	assert.Regexp(t, "\"search_query\":", c1.ToolCalls[0].FunctionCall.Arguments)
 }
+
+func TestResponseFormatFromSchema(t *testing.T) {
+	t.Parallel()
+
+	format, err := responseFormatFromSchema(&llms.ResponseSchema{
+		Name:   "answer",
+		Strict: true,
+		Schema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"steps": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				"scores": map[string]any{
+					"type":                 "object",
+					"additionalProperties": map[string]any{"type": "integer"},
+				},
+				"note": map[string]any{"type": []string{"string", "null"}},
+			},
+			"required": []string{"steps"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "json_schema", format.Type)
+	data, err := json.Marshal(format)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "json_schema",
+		"json_schema": {
+			"name": "answer",
+			"strict": true,
+			"schema": {
+				"type": "object",
+				"properties": {
+					"steps": {"type": "array", "items": {"type": "string"}},
+					"scores": {"type": "object", "additionalProperties": {"type": "integer"}},
+					"note": {"type": ["string", "null"]}
+				},
+				"required": ["steps"]
+			}
+		}
+	}`, string(data))
+
+	_, err = responseFormatFromSchema(&llms.ResponseSchema{Name: "answer", Schema: "string"})
+	require.Error(t, err)
+}
+
+func TestSupportsStructuredOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts []Option
+		want bool
+	}{
+		{name: "default", want: true},
+		{name: "openai base url", opts: []Option{WithBaseURL("https://api.openai.com/v1")}, want: true},
+		{name: "other base url", opts: []Option{WithBaseURL("http://localhost:11434/v1")}, want: false},
+		{
+			name: "other base url enabled",
+			opts: []Option{WithBaseURL("http://localhost:8000/v1"), WithStructuredOutput(true)},
+			want: true,
+		},
+		{name: "disabled", opts: []Option{WithStructuredOutput(false)}, want: false},
+		{
+			name: "azure",
+			opts: []Option{WithAPIType(APITypeAzure), WithModel("gpt-4o"), WithEmbeddingModel("text-embedding")},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			llm, err := New(append([]Option{WithToken("test")}, tt.opts...)...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, llm.SupportsStructuredOutput())
+		})
+	}
+}
@@ -151,6 +151,10 @@ type CallOptions struct {
 	// JSONMode is a flag to enable JSON mode.
 	JSONMode bool `json:"json"`
 
+	// ResponseSchema constrains the output of the model to JSON that matches
+	// a schema. Models that don't support structured output ignore it.
+	ResponseSchema *ResponseSchema `json:"response_schema,omitempty"`
+
 	// Tools is a list of tools to use. Each tool can be a specific tool or a function.
 	Tools []Tool `json:"tools,omitempty"`
 	// ToolChoice is the choice of tool to use, it can either be "none", "auto" (the default behavior), or a specific tool as described in the ToolChoice type.
@@ -181,6 +185,30 @@ type CallOptions struct {
 	ResponseFormat string  `json:"response_format,omitempty"`
 }
 
+// ResponseSchema is a JSON Schema the output of a model must conform to.
+type ResponseSchema struct {
+	// Name is the name of the schema. It may only contain letters, digits,
+	// underscores and dashes.
+	Name string `json:"name"`
+	// Description tells the model what the output is for.
+	Description string `json:"description,omitempty"`
+	// Schema is the JSON Schema, typically a jsonschema.Definition or a
+	// map[string]any. The top-level schema must be an object.
+	Schema any `json:"schema"`
+	// Strict asks the model to follow the schema exactly. Only used by
+	// OpenAI, which then requires every property to be required.
+	Strict bool `json:"strict,omitempty"`
+}
+
+// StructuredOutputModel is implemented by models that constrain their output
+// natively when called with WithResponseSchema.
+type StructuredOutputModel interface {
+	Model
+	// SupportsStructuredOutput reports whether the model honors
+	// CallOptions.ResponseSchema.
+	SupportsStructuredOutput() bool
+}
+
 // Tool is a tool that can be used by the model.
 type Tool struct {
 	// Type is the type of the tool.
@@ -392,6 +420,15 @@ func WithJSONMode() CallOption {
 	}
 }
 
+// WithResponseSchema will add an option to constrain the output of the model
+// to JSON matching the given schema. Models that implement
+// StructuredOutputModel enforce the schema natively.
+func WithResponseSchema(schema *ResponseSchema) CallOption {
+	return func(o *CallOptions) {
+		o.ResponseSchema = schema
+	}
+}
+
 // WithMetadata will add an option to set metadata to include in the request.
 // The meaning of this field is specific to the backend in use.
 func WithMetadata(metadata map[string]interface{}) CallOption {
@@ -0,0 +1,17 @@
+// Package ratelimit provides a client-side rate limiter for llms.Model. It
+// enforces requests-per-minute, tokens-per-minute and max-in-flight budgets
+// before a call is sent to the provider.
+//
+// Token usage is estimated with llms.CountTokens before the call and
+// reconciled with the llms.Usage reported by the provider once the response
+// arrives. A Limiter can be shared by several models, so that all the
+// workers in a process draw from the same budget:
+//
+//	limiter := ratelimit.NewLimiter(
+//		ratelimit.WithRequestsPerMinute(500),
+//		ratelimit.WithTokensPerMinute(200_000),
+//		ratelimit.WithMaxInFlight(8),
+//	)
+//	fast := ratelimit.New(fastLLM, ratelimit.WithLimiter(limiter))
+//	slow := ratelimit.New(slowLLM, ratelimit.WithLimiter(limiter))
+package ratelimit
@@ -0,0 +1,154 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sayerxofficial/langchaingo/httputil"
+)
+
+// Limiter enforces requests-per-minute, tokens-per-minute and max-in-flight
+// budgets. It is safe for concurrent use and can be shared by any number of
+// models.
+type Limiter struct {
+	mu       sync.Mutex
+	requests *bucket
+	tokens   *bucket
+	inFlight chan struct{}
+	now      func() time.Time
+}
+
+// NewLimiter creates a Limiter with the given budgets. Budgets that are not
+// set are not enforced.
+func NewLimiter(opts ...Option) *Limiter {
+	o := applyOptions(opts...)
+	l := &Limiter{now: time.Now}
+	start := l.now()
+	if o.requestsPerMinute > 0 {
+		l.requests = newBucket(o.requestsPerMinute, start)
+	}
+	if o.tokensPerMinute > 0 {
+		l.tokens = newBucket(o.tokensPerMinute, start)
+	}
+	if o.maxInFlight > 0 {
+		l.inFlight = make(chan struct{}, o.maxInFlight)
+	}
+	return l
+}
+
+// Acquire blocks until a call estimated to use the given number of tokens
+// fits in every budget, or until ctx is done. On success it returns a release
+// function that must be called once the call finished, with the number of
+// tokens actually used; a negative value keeps the estimate.
+func (l *Limiter) Acquire(ctx context.Context, tokens int) (func(actualTokens int), error) {
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if err := l.take(ctx, tokens); err != nil {
+		l.releaseSlot()
+		return nil, err
+	}
+
+	var once sync.Once
+	return func(actualTokens int) {
+		once.Do(func() {
+			if actualTokens >= 0 {
+				l.reconcile(actualTokens - tokens)
+			}
+			l.releaseSlot()
+		})
+	}, nil
+}
+
+// take waits until the request and token buckets can cover the call and then
+// draws from them.
+func (l *Limiter) take(ctx context.Context, tokens int) error {
+	for {
+		l.mu.Lock()
+		now := l.now()
+		wait := max(l.requests.waitFor(1, now), l.tokens.waitFor(tokens, now))
+		if wait <= 0 {
+			l.requests.take(1)
+			l.tokens.take(tokens)
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		if err := httputil.Sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// reconcile corrects the token bucket by the difference between the actual
+// and the estimated usage of a call.
+func (l *Limiter) reconcile(delta int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens.refill(l.now())
+	l.tokens.take(delta)
+}
+
+func (l *Limiter) releaseSlot() {
+	if l.inFlight != nil {
+		<-l.inFlight
+	}
+}
+
+// bucket is a token bucket that refills its capacity once per minute. Its
+// balance can go negative when a call uses more than was estimated; the debt
+// is paid back by later refills. A nil bucket never limits.
+type bucket struct {
+	capacity float64
+	perSec   float64
+	balance  float64
+	last     time.Time
+}
+
+func newBucket(perMinute int, now time.Time) *bucket {
+	return &bucket{
+		capacity: float64(perMinute),
+		perSec:   float64(perMinute) / time.Minute.Seconds(),
+		balance:  float64(perMinute),
+		last:     now,
+	}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if b == nil {
+		return
+	}
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.balance = min(b.capacity, b.balance+elapsed*b.perSec)
+		b.last = now
+	}
+}
+
+// waitFor returns how long to wait until n can be drawn from the bucket.
+// Requests larger than the capacity only need a full bucket.
+func (b *bucket) waitFor(n int, now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.refill(now)
+	need := min(float64(n), b.capacity)
+	if b.balance >= need {
+		return 0
+	}
+	return time.Duration((need - b.balance) / b.perSec * float64(time.Second))
+}
+
+func (b *bucket) take(n int) {
+	if b == nil {
+		return
+	}
+	b.balance = min(b.capacity, b.balance-float64(n))
+}
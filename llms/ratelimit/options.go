@@ -0,0 +1,70 @@
+package ratelimit
+
+import "github.com/sayerxofficial/langchaingo/llms"
+
+// Option is a functional argument that configures a Limiter or a Model.
+type Option func(*options)
+
+type options struct {
+	requestsPerMinute int
+	tokensPerMinute   int
+	maxInFlight       int
+	limiter           *Limiter
+	countTokens       func(text string) int
+}
+
+// WithRequestsPerMinute limits the number of calls started per minute. Zero
+// means no limit.
+func WithRequestsPerMinute(n int) Option {
+	return func(o *options) {
+		o.requestsPerMinute = n
+	}
+}
+
+// WithTokensPerMinute limits the number of tokens, prompt and completion,
+// consumed per minute. Zero means no limit.
+func WithTokensPerMinute(n int) Option {
+	return func(o *options) {
+		o.tokensPerMinute = n
+	}
+}
+
+// WithMaxInFlight limits the number of calls running at the same time. Zero
+// means no limit.
+func WithMaxInFlight(n int) Option {
+	return func(o *options) {
+		o.maxInFlight = n
+	}
+}
+
+// WithLimiter makes a Model draw from a shared Limiter. When set, the budget
+// options passed to New are ignored.
+func WithLimiter(limiter *Limiter) Option {
+	return func(o *options) {
+		o.limiter = limiter
+	}
+}
+
+// WithTokenCounter sets the function used to estimate the number of tokens
+// in a prompt. Defaults to llms.CountTokens with the model name passed in the
+// call options.
+func WithTokenCounter(countTokens func(text string) int) Option {
+	return func(o *options) {
+		o.countTokens = countTokens
+	}
+}
+
+func applyOptions(opts ...Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// defaultTokenCounter counts tokens with the tokenizer of the given model.
+func defaultTokenCounter(model string) func(string) int {
+	return func(text string) int {
+		return llms.CountTokens(model, text)
+	}
+}
@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/sayerxofficial/langchaingo/llms"
+)
+
+// Model is an llms.Model wrapper that holds calls back until they fit in the
+// budgets of its Limiter.
+type Model struct {
+	llm         llms.Model
+	limiter     *Limiter
+	countTokens func(string) int
+}
+
+// assert that `Model` implements the `llms.Model` interface.
+var _ llms.Model = (*Model)(nil)
+
+// New wraps a Model with a rate limiter. Use WithLimiter to share budgets
+// between several models; otherwise a new Limiter is created from the
+// budget options.
+func New(llm llms.Model, opts ...Option) *Model {
+	o := applyOptions(opts...)
+	limiter := o.limiter
+	if limiter == nil {
+		limiter = NewLimiter(opts...)
+	}
+	return &Model{
+		llm:         llm,
+		limiter:     limiter,
+		countTokens: o.countTokens,
+	}
+}
+
+// Limiter returns the limiter used by the model.
+func (m *Model) Limiter() *Limiter {
+	return m.limiter
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (m *Model) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+// GenerateContent waits for the budgets to allow the call and then forwards
+// it to the wrapped model. The token budget is charged with an estimate up
+// front and corrected with the usage reported in the response.
+func (m *Model) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	release, err := m.limiter.Acquire(ctx, m.estimateTokens(messages, opts))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := m.llm.GenerateContent(ctx, messages, options...)
+	actual := -1
+	if resp != nil && resp.Usage != nil {
+		actual = resp.Usage.TotalTokens()
+	}
+	release(actual)
+
+	return resp, err
+}
+
+// estimateTokens estimates the tokens a call will use: the text of the
+// messages plus the maximum number of tokens the model may generate.
+func (m *Model) estimateTokens(messages []llms.MessageContent, opts llms.CallOptions) int {
+	countTokens := m.countTokens
+	if countTokens == nil {
+		countTokens = defaultTokenCounter(opts.Model)
+	}
+
+	tokens := opts.MaxTokens
+	for _, msg := range messages {
+		for _, part := range msg.Parts {
+			switch p := part.(type) {
+			case llms.TextContent:
+				tokens += countTokens(p.Text)
+			case llms.ToolCall:
+				if p.FunctionCall != nil {
+					tokens += countTokens(p.FunctionCall.Arguments)
+				}
+			case llms.ToolCallResponse:
+				tokens += countTokens(p.Content)
+			}
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,145 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sayerxofficial/langchaingo/llms"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLLM struct {
+	usage    *llms.Usage
+	block    chan struct{}
+	inFlight atomic.Int32
+	peak     atomic.Int32
+}
+
+func (m *mockLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *mockLLM) GenerateContent(context.Context, []llms.MessageContent, ...llms.CallOption) (*llms.ContentResponse, error) {
+	n := m.inFlight.Add(1)
+	defer m.inFlight.Add(-1)
+	for {
+		peak := m.peak.Load()
+		if n <= peak || m.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	if m.block != nil {
+		<-m.block
+	}
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: "ok"}},
+		Usage:   m.usage,
+	}, nil
+}
+
+func countWords(text string) int {
+	return len(text)
+}
+
+func shortContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestLimiter_RequestsPerMinute(t *testing.T) {
+	t.Parallel()
+
+	l := NewLimiter(WithRequestsPerMinute(2))
+	for range 2 {
+		release, err := l.Acquire(t.Context(), 0)
+		require.NoError(t, err)
+		release(-1)
+	}
+
+	_, err := l.Acquire(shortContext(t), 0)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestLimiter_ReconcilesTokens(t *testing.T) {
+	t.Parallel()
+
+	l := NewLimiter(WithTokensPerMinute(100))
+
+	// The call used less than estimated: the difference is refunded.
+	release, err := l.Acquire(t.Context(), 90)
+	require.NoError(t, err)
+	release(10)
+
+	release, err = l.Acquire(t.Context(), 80)
+	require.NoError(t, err)
+
+	// The call used more than estimated: the budget goes into debt.
+	release(190)
+	_, err = l.Acquire(shortContext(t), 1)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestLimiter_LargeRequestNeedsFullBucket(t *testing.T) {
+	t.Parallel()
+
+	l := NewLimiter(WithTokensPerMinute(10))
+	release, err := l.Acquire(t.Context(), 1000)
+	require.NoError(t, err)
+	release(-1)
+}
+
+func TestModel_SharedMaxInFlight(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewLimiter(WithMaxInFlight(2))
+	llm := &mockLLM{block: make(chan struct{})}
+	a := New(llm, WithLimiter(limiter), WithTokenCounter(countWords))
+	b := New(llm, WithLimiter(limiter), WithTokenCounter(countWords))
+
+	var wg sync.WaitGroup
+	for i := range 6 {
+		m := a
+		if i%2 == 1 {
+			m = b
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := m.GenerateContent(t.Context(), []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")})
+			assert.NoError(t, err)
+		}()
+	}
+
+	require.Eventually(t, func() bool { return llm.inFlight.Load() == 2 }, time.Second, time.Millisecond)
+	close(llm.block)
+	wg.Wait()
+	require.Equal(t, int32(2), llm.peak.Load())
+}
+
+func TestModel_EstimatesAndReconcilesTokens(t *testing.T) {
+	t.Parallel()
+
+	llm := &mockLLM{usage: &llms.Usage{InputTokens: 60, OutputTokens: 40}}
+	m := New(llm, WithTokensPerMinute(150), WithTokenCounter(countWords))
+
+	msgs := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "0123456789")}
+
+	// Estimated at 10 prompt + 40 max tokens, charged 100 after the call.
+	_, err := m.GenerateContent(t.Context(), msgs, llms.WithMaxTokens(40))
+	require.NoError(t, err)
+
+	// 50 tokens left: a call estimated at 50 fits, one more does not.
+	release, err := m.Limiter().Acquire(t.Context(), 50)
+	require.NoError(t, err)
+	release(-1)
+
+	_, err = m.GenerateContent(shortContext(t), msgs, llms.WithMaxTokens(40))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
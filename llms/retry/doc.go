@@ -0,0 +1,12 @@
+// Package retry provides an llms.Model wrapper that retries failed calls with
+// a jittered exponential backoff. Errors are classified with an
+// llms.ErrorMapper, and only transient failures such as rate limits, timeouts
+// and unavailable providers are retried. Delays requested by the provider
+// through llms.ErrorDetailRetryAfter, which is set from the Retry-After header
+// of OpenAI and Anthropic error responses, take precedence over the backoff up
+// to its maximum delay.
+//
+// The same backoff is available at the HTTP level through
+// httputil.RetryTransport, which can be passed to any provider that accepts a
+// custom HTTP client.
+package retry
@@ -0,0 +1,50 @@
+package retry
+
+import (
+	"github.com/sayerxofficial/langchaingo/httputil"
+	"github.com/sayerxofficial/langchaingo/llms"
+)
+
+const defaultMaxAttempts = 3
+
+// Option is a functional argument that configures the retrying Model.
+type Option func(*options)
+
+type options struct {
+	maxAttempts int
+	backoff     httputil.Backoff
+	retryCodes  []llms.ErrorCode
+	errorMapper *llms.ErrorMapper
+}
+
+// WithMaxAttempts sets the maximum number of attempts, including the first
+// one. Defaults to 3.
+func WithMaxAttempts(n int) Option {
+	return func(o *options) {
+		o.maxAttempts = n
+	}
+}
+
+// WithBackoff sets the backoff used between attempts. Defaults to
+// httputil.DefaultBackoff.
+func WithBackoff(backoff httputil.Backoff) Option {
+	return func(o *options) {
+		o.backoff = backoff
+	}
+}
+
+// WithRetryCodes sets the error codes that are retried. Defaults to rate
+// limit, timeout and provider unavailable errors.
+func WithRetryCodes(codes ...llms.ErrorCode) Option {
+	return func(o *options) {
+		o.retryCodes = codes
+	}
+}
+
+// WithErrorMapper sets the mapper used to classify errors that are not
+// already an *llms.Error.
+func WithErrorMapper(mapper *llms.ErrorMapper) Option {
+	return func(o *options) {
+		o.errorMapper = mapper
+	}
+}
@@ -0,0 +1,112 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/sayerxofficial/langchaingo/httputil"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/llms/streaming"
+)
+
+// Model is an llms.Model wrapper that retries calls failing with a transient
+// error.
+type Model struct {
+	llm  llms.Model
+	opts options
+}
+
+// assert that `Model` implements the `llms.Model` interface.
+var _ llms.Model = (*Model)(nil)
+
+// New wraps a Model and retries its failed calls.
+func New(llm llms.Model, opts ...Option) *Model {
+	o := options{
+		maxAttempts: defaultMaxAttempts,
+		backoff:     httputil.DefaultBackoff,
+		retryCodes: []llms.ErrorCode{
+			llms.ErrCodeRateLimit,
+			llms.ErrCodeTimeout,
+			llms.ErrCodeProviderUnavailable,
+		},
+		errorMapper: llms.NewErrorMapper("retry"),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Model{
+		llm:  llm,
+		opts: o,
+	}
+}
+
+// Call is a simplified interface for a text-only Model, generating a single
+// string response from a single string prompt.
+//
+// Deprecated: this method is retained for backwards compatibility. Use the
+// more general [GenerateContent] instead. You can also use
+// the [GenerateFromSinglePrompt] function which provides a similar capability
+// to Call and is built on top of the new interface.
+func (m *Model) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+// GenerateContent calls the wrapped model and retries transient failures. A
+// streamed call is not retried once a chunk has been delivered to the
+// caller's streaming function, since the caller can't take it back.
+func (m *Model) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint:lll
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	var streamed atomic.Bool
+	if opts.StreamingFunc != nil {
+		streamingFunc := opts.StreamingFunc
+		options = append(slices.Clip(options), llms.WithStreamingFunc(func(ctx context.Context, chunk streaming.Chunk) error {
+			streamed.Store(true)
+			return streamingFunc(ctx, chunk)
+		}))
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := m.llm.GenerateContent(ctx, messages, options...)
+		if err == nil {
+			return resp, nil
+		}
+
+		err = m.opts.errorMapper.WrapError(err)
+		if attempt+1 >= m.opts.maxAttempts || streamed.Load() || ctx.Err() != nil || !m.shouldRetry(err) {
+			return nil, err
+		}
+
+		if sleepErr := httputil.Sleep(ctx, m.delay(err, attempt)); sleepErr != nil {
+			return nil, err
+		}
+	}
+}
+
+// shouldRetry reports whether err is one of the configured retryable codes.
+func (m *Model) shouldRetry(err error) bool {
+	var llmErr *llms.Error
+	if !errors.As(err, &llmErr) {
+		return false
+	}
+	return slices.Contains(m.opts.retryCodes, llmErr.Code)
+}
+
+// delay returns how long to wait before the next attempt, preferring the
+// delay requested by the provider up to the maximum delay of the backoff.
+func (m *Model) delay(err error, attempt int) time.Duration {
+	var llmErr *llms.Error
+	if errors.As(err, &llmErr) {
+		if d, ok := llmErr.RetryAfter(); ok {
+			return min(d, m.opts.backoff.MaxDelay())
+		}
+	}
+	return m.opts.backoff.Delay(attempt)
+}
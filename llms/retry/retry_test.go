@@ -0,0 +1,137 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sayerxofficial/langchaingo/httputil"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/llms/streaming"
+
+	"github.com/stretchr/testify/require"
+)
+
+// not synchronized, don't use concurrently!
+type mockLLM struct {
+	called int
+	errs   []error
+	stream bool
+}
+
+func (m *mockLLM) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func (m *mockLLM) GenerateContent(ctx context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	var opts llms.CallOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	m.called++
+	if m.stream {
+		if err := streaming.CallWithText(ctx, opts.StreamingFunc, "partial"); err != nil {
+			return nil, err
+		}
+	}
+	if len(m.errs) > 0 {
+		err := m.errs[0]
+		m.errs = m.errs[1:]
+		return nil, err
+	}
+	return &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: "done"}},
+	}, nil
+}
+
+var fastBackoff = httputil.Backoff{Initial: time.Millisecond, Max: time.Millisecond}
+
+func TestModel_RetriesTransientErrors(t *testing.T) {
+	t.Parallel()
+
+	llm := &mockLLM{errs: []error{
+		errors.New("429 Too Many Requests"),
+		llms.NewError(llms.ErrCodeProviderUnavailable, "test", "overloaded"),
+	}}
+	m := New(llm, WithBackoff(fastBackoff))
+
+	resp, err := m.GenerateContent(t.Context(), nil)
+	require.NoError(t, err)
+	require.Equal(t, "done", resp.Choices[0].Content)
+	require.Equal(t, 3, llm.called)
+}
+
+func TestModel_DoesNotRetryPermanentErrors(t *testing.T) {
+	t.Parallel()
+
+	llm := &mockLLM{errs: []error{errors.New("401 unauthorized")}}
+	m := New(llm, WithBackoff(fastBackoff))
+
+	_, err := m.GenerateContent(t.Context(), nil)
+	require.True(t, llms.IsAuthenticationError(err))
+	require.Equal(t, 1, llm.called)
+}
+
+func TestModel_MaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	rateLimit := llms.NewError(llms.ErrCodeRateLimit, "test", "slow down")
+	llm := &mockLLM{errs: []error{rateLimit, rateLimit, rateLimit}}
+	m := New(llm, WithBackoff(fastBackoff), WithMaxAttempts(2))
+
+	_, err := m.GenerateContent(t.Context(), nil)
+	require.True(t, llms.IsRateLimitError(err))
+	require.Equal(t, 2, llm.called)
+}
+
+func TestModel_HonorsRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	rateLimit := llms.NewError(llms.ErrCodeRateLimit, "test", "slow down").
+		WithDetail(llms.ErrorDetailRetryAfter, time.Hour)
+	llm := &mockLLM{errs: []error{rateLimit}}
+	m := New(llm)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := m.GenerateContent(ctx, nil)
+	require.True(t, llms.IsRateLimitError(err))
+	require.Equal(t, 1, llm.called)
+}
+
+func TestModel_CapsRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	// The delay comes from the Retry-After header of the provider response,
+	// and is capped by the maximum delay of the backoff.
+	rateLimit := &httputil.RetryAfterError{Err: errors.New("429 Too Many Requests"), Delay: time.Hour}
+	llm := &mockLLM{errs: []error{rateLimit}}
+	m := New(llm, WithBackoff(fastBackoff))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	resp, err := m.GenerateContent(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, "done", resp.Choices[0].Content)
+	require.Equal(t, 2, llm.called)
+}
+
+func TestModel_NoRetryAfterStreamStarted(t *testing.T) {
+	t.Parallel()
+
+	llm := &mockLLM{stream: true, errs: []error{llms.NewError(llms.ErrCodeTimeout, "test", "stream dropped")}}
+	m := New(llm, WithBackoff(fastBackoff))
+
+	var chunks int
+	_, err := m.GenerateContent(t.Context(), nil, llms.WithStreamingFunc(func(context.Context, streaming.Chunk) error {
+		chunks++
+		return nil
+	}))
+	require.True(t, llms.IsTimeoutError(err))
+	require.Equal(t, 1, llm.called)
+	require.Equal(t, 1, chunks)
+}
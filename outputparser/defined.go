@@ -5,8 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/sayerxofficial/langchaingo/jsonschema"
 	"github.com/sayerxofficial/langchaingo/llms"
 	"github.com/sayerxofficial/langchaingo/schema"
 )
@@ -22,7 +27,8 @@ type Defined[T any] struct {
 // a given schema, as defined by struct field names and types. Tagging the
 // field with "json" will explicitly use that value as the field name. Tagging
 // with "describe" will add a line comment for the LLM to understand how to
-// generate data, helpful when the field's name is insufficient.
+// generate data, helpful when the field's name is insufficient. The format
+// instructions and JSONSchema are both built by jsonschema.Reflect.
 func NewDefined[T any](source T) (Defined[T], error) {
 	var empty Defined[T]
 
@@ -30,23 +36,15 @@ func NewDefined[T any](source T) (Defined[T], error) {
 	if k := sourceType.Kind(); k != reflect.Struct {
 		return empty, fmt.Errorf("expected a struct; got %s", k)
 	}
-	numFields := sourceType.NumField()
-	if numFields == 0 {
+	if sourceType.NumField() == 0 {
 		return empty, errors.New("schema source has no fields")
 	}
 
-	var result bytes.Buffer
-	switch sourceType.Kind() { // nolint:exhaustive
-	case reflect.Struct:
-		data, err := marshalStruct(sourceType, "_Root")
-		if err != nil {
-			return empty, err
-		}
-		result.Write(data)
-	default:
-		return empty, fmt.Errorf("unable to marshal '%s' field type", sourceType.Kind())
+	def, err := jsonschema.Reflect(source)
+	if err != nil {
+		return empty, err
 	}
-	return Defined[T]{result.String()}, nil
+	return Defined[T]{string(marshalInterface(def, sourceType, "_Root"))}, nil
 }
 
 var _ schema.OutputParser[any] = Defined[any]{}
@@ -79,6 +77,13 @@ func (p Defined[T]) ParseWithPrompt(text string, _ llms.PromptValue) (T, error)
 	return p.Parse(text)
 }
 
+// JSONSchema returns the JSON Schema of T, built by jsonschema.Reflect from
+// the same struct tags as the format instructions. It can be passed to
+// llms.WithResponseSchema or used as the parameters of a tool.
+func (p Defined[T]) JSONSchema() (jsonschema.Definition, error) {
+	return jsonschema.Reflect(new(T))
+}
+
 // Type returns the string type key uniquely identifying this class of parser.
 func (p Defined[T]) Type() string {
 	return "defined_parser"
@@ -86,54 +91,40 @@ func (p Defined[T]) Type() string {
 
 const numStructs = 8 // ~5 struct-interfaces per schema in a medium-complexity case
 
-func marshalStruct(vType reflect.Type, name string) ([]byte, error) { // nolint:funlen,cyclop
+// marshalInterface describes an object schema as a TypeScript interface,
+// followed by the interfaces of its nested objects. t is the Go type the
+// schema was reflected from, which gives the order of the properties and the
+// names of their types.
+func marshalInterface(def jsonschema.Definition, t reflect.Type, name string) []byte {
 	var b bytes.Buffer
 	b.WriteString("interface ")
 	b.WriteString(name)
 	b.WriteString(" {\n")
 	moreStructs := make([][]byte, 0, numStructs)
-	for i := 0; i < vType.NumField(); i++ {
-		field := vType.Field(i)
+	names, fields := structFields(t)
+	for _, prop := range names {
+		propDef, ok := def.Properties[prop]
+		if !ok {
+			continue
+		}
+		field := fields[prop]
 		b.WriteString("\t")
-		name := field.Tag.Get("json")
-		if name == "" {
-			name = field.Name
+		b.WriteString(prop)
+		if !slices.Contains(def.Required, prop) {
+			b.WriteString("?")
 		}
-		b.WriteString(name)
 		b.WriteString(": ")
-		typeName := field.Type.Name()
-		if typeName == "" {
-			typeName = field.Name
-		}
-		switch field.Type.Kind() { // nolint:exhaustive
-		case reflect.Struct:
-			marshaled, err := marshalStruct(field.Type, typeName)
-			if err != nil {
-				return []byte{}, err
-			}
-			moreStructs = append(moreStructs, marshaled)
-			b.WriteString(typeName)
-		case reflect.Array, reflect.Slice:
-			elemType := field.Type.Elem()
-			switch elemType.Kind() { // nolint:exhaustive
-			case reflect.Struct:
-				marshaled, err := marshalStruct(elemType, typeName)
-				if err != nil {
-					return []byte{}, err
-				}
-				moreStructs = append(moreStructs, marshaled)
-				b.WriteString(typeName)
-			default:
-				b.WriteString(field.Type.Elem().Kind().String())
-			}
-			b.WriteString("[]")
-		default:
-			b.WriteString(typeName)
+		// Nested types are named after their Go type, or after the field
+		// for unnamed types.
+		name := field.Type.Name()
+		if name == "" {
+			name = field.Name
 		}
+		b.WriteString(typeName(propDef, field.Type, name, &moreStructs))
 		b.WriteString(";")
-		if describe := field.Tag.Get("describe"); describe != "" {
+		if propDef.Description != "" {
 			b.WriteString(" // ")
-			b.WriteString(describe)
+			b.WriteString(propDef.Description)
 		}
 		b.WriteString("\n")
 	}
@@ -142,5 +133,95 @@ func marshalStruct(vType reflect.Type, name string) ([]byte, error) { // nolint:
 		b.WriteString("\n")
 		b.Write(more)
 	}
-	return b.Bytes(), nil
+	return b.Bytes()
+}
+
+// typeName returns the TypeScript type of a schema reflected from the Go type
+// t. Basic types keep their Go names, and objects with properties are named
+// name, with their interfaces appended to moreStructs.
+func typeName(def jsonschema.Definition, t reflect.Type, name string, moreStructs *[][]byte) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var ts string
+	switch {
+	case len(def.Enum) > 0:
+		values := make([]string, len(def.Enum))
+		for i, v := range def.Enum {
+			values[i] = strconv.Quote(v)
+		}
+		ts = strings.Join(values, " | ")
+	case def.Type == jsonschema.Object && len(def.Properties) > 0:
+		*moreStructs = append(*moreStructs, marshalInterface(def, t, name))
+		ts = name
+	case def.Type == jsonschema.Object:
+		values := "any"
+		if additional, ok := def.AdditionalProperties.(jsonschema.Definition); ok {
+			values = typeName(additional, t.Elem(), name+"Value", moreStructs)
+		}
+		ts = "Record<string, " + values + ">"
+	case def.Type == jsonschema.Array:
+		items := "any"
+		if def.Items != nil {
+			items = typeName(*def.Items, t.Elem(), name, moreStructs)
+		}
+		if strings.Contains(items, " ") {
+			items = "(" + items + ")"
+		}
+		ts = items + "[]"
+	case def.Type == "":
+		ts = "any"
+	case t.PkgPath() == "" && t.Name() != "":
+		ts = t.Name()
+	default:
+		ts = string(def.Type)
+	}
+	if def.Nullable {
+		ts += " | null"
+	}
+	return ts
+}
+
+// structFields returns the names of the JSON properties of struct type t in
+// field order, and the fields they come from. Fields of embedded structs
+// without a json name are promoted, as jsonschema.Reflect does.
+func structFields(t reflect.Type) ([]string, map[string]reflect.StructField) {
+	names := make([]string, 0, t.NumField())
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				promoted, promotedFields := structFields(ft)
+				for _, name := range promoted {
+					if _, ok := fields[name]; !ok {
+						names = append(names, name)
+					}
+				}
+				maps.Copy(fields, promotedFields)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, ok := fields[name]; !ok {
+			names = append(names, name)
+		}
+		fields[name] = field
+	}
+	return names, fields
 }
@@ -82,6 +82,42 @@ interface Shape {
 interface Foods {
 	name: string;
 	temp: int; // temperature usually served at
+}`,
+		},
+		"struct field named after its type": {
+			input: struct {
+				Outline []Shape `json:"outline"`
+				Inner   Shape   `json:"inner"`
+			}{},
+			expected: `interface _Root {
+	outline: Outline[];
+	inner: Shape;
+}
+interface Outline {
+	shapeName: string; // shape name
+	numSides: int; // number of sides
+}
+interface Shape {
+	shapeName: string; // shape name
+	numSides: int; // number of sides
+}`,
+		},
+		"optional, nullable, map and enum fields": {
+			input: struct {
+				Kind   string         `json:"kind" enum:"circle,square"`
+				Parent *Shape         `json:"parent"`
+				Scores map[string]int `json:"scores"`
+				Note   string         `json:"note,omitempty" describe:"free text"`
+			}{},
+			expected: `interface _Root {
+	kind: "circle" | "square";
+	parent: Parent | null;
+	scores: Record<string, int>;
+	note?: string; // free text
+}
+interface Parent {
+	shapeName: string; // shape name
+	numSides: int; // number of sides
 }`,
 		},
 	}
@@ -131,3 +167,29 @@ func TestDefinedParse(t *testing.T) {
 		}
 	}
 }
+
+func TestDefinedJSONSchema(t *testing.T) {
+	t.Parallel()
+	type Shape struct {
+		Name     string `json:"shapeName" describe:"shape name"`
+		NumSides int    `json:"numSides,omitempty" describe:"number of sides"`
+	}
+	parser, err := NewDefined(Shape{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	def, err := parser.JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := def.Properties["shapeName"].Description; got != "shape name" {
+		t.Errorf("got description '%s'; want 'shape name'", got)
+	}
+	if len(def.Required) != 1 || def.Required[0] != "shapeName" {
+		t.Errorf("got required %v; want [shapeName]", def.Required)
+	}
+	if err := def.Validate([]byte(`{"shapeName": "square", "numSides": 4}`)); err != nil {
+		t.Error(err)
+	}
+}
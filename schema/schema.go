@@ -6,6 +6,10 @@ type AgentAction struct {
 	ToolInput string
 	Log       string
 	ToolID    string
+	// TurnID identifies the model response that planned the action, when
+	// several actions can be planned at once. Actions planned by the same
+	// response share it.
+	TurnID string
 }
 
 // AgentStep is a step of the agent.
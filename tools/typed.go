@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/sayerxofficial/langchaingo/jsonschema"
+	"github.com/sayerxofficial/langchaingo/llms"
+)
+
+// ErrInvalidArguments is returned by a typed tool when the arguments of a
+// call do not match the schema of its input.
+var ErrInvalidArguments = errors.New("invalid tool arguments")
+
+// typedInputKey is the property that holds inputs that are not objects,
+// since function parameters must be an object.
+const typedInputKey = "input"
+
+// FunctionTool is a Tool that describes its input with a JSON schema. Agents
+// that call tools natively use the definition instead of a single string
+// argument, and pass the arguments of a call as JSON to Call.
+type FunctionTool interface {
+	Tool
+	// FunctionDefinition returns the definition of the tool as a function
+	// that can be passed to llms.WithTools.
+	FunctionDefinition() llms.FunctionDefinition
+}
+
+// Typed is a FunctionTool backed by a Go function. Its parameters are derived
+// from In with jsonschema.Reflect; the arguments of each call are validated
+// against them and decoded into In, and the result is encoded as JSON unless
+// Out is a string.
+type Typed[In, Out any] struct {
+	name        string
+	description string
+	fn          func(ctx context.Context, input In) (Out, error)
+	schema      jsonschema.Definition
+	wrapped     bool
+}
+
+var _ FunctionTool = (*Typed[struct{}, string])(nil)
+
+// NewTyped creates a typed tool that calls fn. In is usually a struct whose
+// fields are described with the tags documented in jsonschema.Reflect.
+// Inputs that are not structs or maps are passed to the model as a single
+// "input" property.
+func NewTyped[In, Out any](name, description string, fn func(ctx context.Context, input In) (Out, error)) (*Typed[In, Out], error) { //nolint:lll
+	schema, err := jsonschema.Reflect(new(In))
+	if err != nil {
+		return nil, fmt.Errorf("tool %s: %w", name, err)
+	}
+
+	t := &Typed[In, Out]{
+		name:        name,
+		description: description,
+		fn:          fn,
+		schema:      schema,
+	}
+	if schema.Type != jsonschema.Object {
+		t.wrapped = true
+		t.schema = jsonschema.Definition{
+			Type:       jsonschema.Object,
+			Properties: map[string]jsonschema.Definition{typedInputKey: schema},
+			Required:   []string{typedInputKey},
+		}
+	}
+	return t, nil
+}
+
+// IsSingleStringInput reports whether the parameters are those of a typed
+// tool taking a string: an object with the string in its "input" property.
+// Such tools also accept the raw string as input.
+func IsSingleStringInput(def jsonschema.Definition) bool {
+	input, ok := def.Properties[typedInputKey]
+	return ok && def.Type == jsonschema.Object && len(def.Properties) == 1 && input.Type == jsonschema.String
+}
+
+// Name returns the name of the tool.
+func (t *Typed[In, Out]) Name() string {
+	return t.name
+}
+
+// Description returns the description of the tool.
+func (t *Typed[In, Out]) Description() string {
+	return t.description
+}
+
+// FunctionDefinition returns the definition of the tool, with the JSON
+// schema of In as parameters.
+func (t *Typed[In, Out]) FunctionDefinition() llms.FunctionDefinition {
+	return llms.FunctionDefinition{
+		Name:        t.name,
+		Description: t.description,
+		Parameters:  t.schema,
+	}
+}
+
+// Call decodes the JSON arguments in input, calls the tool function and
+// returns its encoded result. Tools whose input is a string also accept the
+// raw string, as sent by agents that don't use function calling.
+func (t *Typed[In, Out]) Call(ctx context.Context, input string) (string, error) {
+	in, err := t.decode(input)
+	if err != nil {
+		return "", fmt.Errorf("tool %s: %w", t.name, err)
+	}
+
+	out, err := t.fn(ctx, in)
+	if err != nil {
+		return "", err
+	}
+
+	if s, ok := any(out).(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return "", fmt.Errorf("tool %s: encode result: %w", t.name, err)
+	}
+	return string(data), nil
+}
+
+func (t *Typed[In, Out]) decode(input string) (In, error) {
+	var in In
+	data := []byte(input)
+
+	if err := t.schema.Validate(data); err != nil {
+		if t.wrapped && reflect.TypeFor[In]().Kind() == reflect.String {
+			reflect.ValueOf(&in).Elem().SetString(input)
+			return in, nil
+		}
+		return in, fmt.Errorf("%w: %w", ErrInvalidArguments, err)
+	}
+
+	if t.wrapped {
+		var args map[string]json.RawMessage
+		if err := json.Unmarshal(data, &args); err != nil {
+			return in, fmt.Errorf("%w: %w", ErrInvalidArguments, err)
+		}
+		data = args[typedInputKey]
+	}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return in, fmt.Errorf("%w: %w", ErrInvalidArguments, err)
+	}
+	return in, nil
+}
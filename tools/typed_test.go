@@ -0,0 +1,124 @@
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/jsonschema"
+	"github.com/sayerxofficial/langchaingo/llms"
+	"github.com/sayerxofficial/langchaingo/tools"
+
+	"github.com/stretchr/testify/require"
+)
+
+type weatherInput struct {
+	City  string `json:"city" description:"the city to look up"`
+	Units string `json:"units,omitempty" enum:"celsius,fahrenheit"`
+}
+
+type weatherOutput struct {
+	Temperature float64 `json:"temperature"`
+	Units       string  `json:"units"`
+}
+
+func newWeatherTool(t *testing.T) *tools.Typed[weatherInput, weatherOutput] {
+	t.Helper()
+
+	tool, err := tools.NewTyped("weather", "Returns the current temperature.",
+		func(_ context.Context, in weatherInput) (weatherOutput, error) {
+			if in.City == "Atlantis" {
+				return weatherOutput{}, errors.New("unknown city")
+			}
+			units := in.Units
+			if units == "" {
+				units = "celsius"
+			}
+			return weatherOutput{Temperature: 21.5, Units: units}, nil
+		})
+	require.NoError(t, err)
+	return tool
+}
+
+func TestTyped_FunctionDefinition(t *testing.T) {
+	t.Parallel()
+
+	def := newWeatherTool(t).FunctionDefinition()
+	require.Equal(t, "weather", def.Name)
+	require.Equal(t, "Returns the current temperature.", def.Description)
+
+	params, ok := def.Parameters.(jsonschema.Definition)
+	require.True(t, ok)
+	require.Equal(t, jsonschema.Object, params.Type)
+	require.Equal(t, []string{"city"}, params.Required)
+	require.Equal(t, "the city to look up", params.Properties["city"].Description)
+	require.Equal(t, []string{"celsius", "fahrenheit"}, params.Properties["units"].Enum)
+	require.False(t, tools.IsSingleStringInput(params))
+}
+
+func TestTyped_Call(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	tool := newWeatherTool(t)
+
+	out, err := tool.Call(ctx, `{"city": "Paris", "units": "fahrenheit"}`)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"temperature": 21.5, "units": "fahrenheit"}`, out)
+
+	_, err = tool.Call(ctx, `{"units": "celsius"}`)
+	require.ErrorIs(t, err, tools.ErrInvalidArguments)
+	require.Contains(t, err.Error(), `missing required property "city"`)
+
+	_, err = tool.Call(ctx, `{"city": "Paris", "units": "kelvin"}`)
+	require.ErrorIs(t, err, tools.ErrInvalidArguments)
+
+	_, err = tool.Call(ctx, `{"city": "Atlantis"}`)
+	require.EqualError(t, err, "unknown city")
+}
+
+func TestTyped_StringInput(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+
+	tool, err := tools.NewTyped("shout", "Shouts the input.",
+		func(_ context.Context, in string) (string, error) {
+			return in + "!", nil
+		})
+	require.NoError(t, err)
+
+	params := tool.FunctionDefinition().Parameters.(jsonschema.Definition)
+	require.Equal(t, []string{"input"}, params.Required)
+	require.True(t, tools.IsSingleStringInput(params))
+
+	// Function calling agents send JSON arguments...
+	out, err := tool.Call(ctx, `{"input": "hello"}`)
+	require.NoError(t, err)
+	require.Equal(t, "hello!", out)
+
+	// ...while text agents send the raw input.
+	out, err = tool.Call(ctx, "hello")
+	require.NoError(t, err)
+	require.Equal(t, "hello!", out)
+}
+
+// ExampleNewTyped runs a typed tool in a plain tool-calling loop.
+func ExampleNewTyped() {
+	type input struct {
+		A int `json:"a"`
+		B int `json:"b"`
+	}
+	add, _ := tools.NewTyped("add", "Adds two numbers.",
+		func(_ context.Context, in input) (int, error) {
+			return in.A + in.B, nil
+		})
+
+	def := add.FunctionDefinition()
+	_ = llms.WithTools([]llms.Tool{{Type: "function", Function: &def}})
+
+	// A tool call returned by the model.
+	call := llms.ToolCall{FunctionCall: &llms.FunctionCall{Name: "add", Arguments: `{"a": 2, "b": 3}`}}
+	out, _ := add.Call(context.Background(), call.FunctionCall.Arguments)
+	fmt.Println(out)
+	// Output: 5
+}
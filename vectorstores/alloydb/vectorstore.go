@@ -157,6 +157,12 @@ func (vs *VectorStore) generateAddDocumentsQuery(id, content, embedding string,
 // query vector.
 func (vs *VectorStore) SimilaritySearch(ctx context.Context, query string, _ int, options ...vectorstores.Option) ([]schema.Document, error) {
 	opts := applyOpts(options...)
+	// Filters are SQL conditions, there is no translation of vectorstores.Filter.
+	if f, ok := vectorstores.AsFilter(opts.Filters); ok {
+		return nil, &vectorstores.UnsupportedFilterError{
+			Store: "alloydb", Operator: f.Operator, Reason: "filters are SQL conditions given as a string",
+		}
+	}
 	var documents []schema.Document
 	embedding, err := vs.embedder.EmbedQuery(ctx, query)
 	if err != nil {
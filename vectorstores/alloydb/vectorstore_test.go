@@ -13,6 +13,7 @@ import (
 	"github.com/sayerxofficial/langchaingo/llms/openai"
 	"github.com/sayerxofficial/langchaingo/schema"
 	"github.com/sayerxofficial/langchaingo/util/alloydbutil"
+	"github.com/sayerxofficial/langchaingo/vectorstores"
 	"github.com/sayerxofficial/langchaingo/vectorstores/alloydb"
 
 	"github.com/stretchr/testify/require"
@@ -299,3 +300,11 @@ func TestAddDocuments(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestSimilaritySearchUnsupportedFilter(t *testing.T) {
+	t.Parallel()
+
+	var vs alloydb.VectorStore
+	_, err := vs.SimilaritySearch(t.Context(), "cities", 1, vectorstores.WithFilters(vectorstores.Eq("source", "a.md")))
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+}
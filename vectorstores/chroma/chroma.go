@@ -21,6 +21,8 @@ var (
 	ErrUnexpectedResponseLength = errors.New("unexpected length of response")
 	ErrNewClient                = errors.New("error creating collection")
 	ErrAddDocument              = errors.New("error adding document")
+	ErrDeleteDocument           = errors.New("error deleting document")
+	ErrInvalidFilters           = errors.New("invalid filters")
 	ErrRemoveCollection         = errors.New("error resetting collection")
 	ErrUnsupportedOptions       = errors.New("unsupported options")
 )
@@ -40,7 +42,12 @@ type Store struct {
 	includes     []chromatypes.QueryEnum
 }
 
-var _ vectorstores.VectorStore = Store{}
+var (
+	_ vectorstores.VectorStore   = Store{}
+	_ vectorstores.Deleter       = Store{}
+	_ vectorstores.FilterDeleter = Store{}
+	_ vectorstores.Upserter      = Store{}
+)
 
 // New creates an active client connection to the (specified, or default) collection in the Chroma server
 // and returns the `Store` object needed by the other accessors.
@@ -98,16 +105,104 @@ func (s Store) AddDocuments(ctx context.Context,
 		return nil, ErrUnsupportedOptions
 	}
 
+	ids := make([]string, len(docs))
+	for docIdx := range docs {
+		ids[docIdx] = uuid.New().String() // TODO (noodnik2): find & use something more meaningful
+	}
+
+	texts, metadatas, err := s.documents(opts, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	col := s.collection
+	if _, addErr := col.Add(ctx, nil, metadatas, texts, ids); addErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAddDocument, addErr)
+	}
+	return ids, nil
+}
+
+// UpsertDocuments adds the text and metadata from the documents to the Chroma collection associated
+// with 'Store' under the given ids, replacing the documents already stored with the same ids.
+func (s Store) UpsertDocuments(ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) ([]string, error) {
+	opts := s.getOptions(options...)
+	if opts.Embedder != nil || opts.ScoreThreshold != 0 || opts.Filters != nil {
+		return nil, ErrUnsupportedOptions
+	}
+	if len(ids) != len(docs) {
+		return nil, vectorstores.ErrMismatchedIDs
+	}
+	if len(docs) == 0 {
+		return []string{}, nil
+	}
+
+	texts, metadatas, err := s.documents(opts, docs)
+	if err != nil {
+		return nil, err
+	}
+
+	col := s.collection
+	if _, upsertErr := col.Upsert(ctx, nil, metadatas, texts, ids); upsertErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAddDocument, upsertErr)
+	}
+	return append([]string(nil), ids...), nil
+}
+
+// Delete removes the documents with the given ids from the Chroma collection associated with 'Store'.
+// When a name space is set, only the documents of the name space are removed.
+func (s Store) Delete(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	opts := s.getOptions(options...)
+	opts.Filters = nil
+	where, err := s.getNamespacedFilter(opts)
+	if err != nil {
+		return err
+	}
+	if _, err := s.collection.Delete(ctx, ids, where, nil); err != nil {
+		return fmt.Errorf("%w: %w", ErrDeleteDocument, err)
+	}
+	return nil
+}
+
+// DeleteByFilter removes the documents matching the filter, a Chroma where clause or a vectorstores.Filter
+// like the ones given to WithFilters, from the Chroma collection associated with 'Store'.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrEmptyFilter
+	}
+	if where, ok := filter.(map[string]any); ok && len(where) == 0 {
+		return vectorstores.ErrEmptyFilter
+	}
+
+	opts := s.getOptions(options...)
+	opts.Filters = filter
+	where, err := s.getNamespacedFilter(opts)
+	if err != nil {
+		return err
+	}
+	if _, err := s.collection.Delete(ctx, nil, where, nil); err != nil {
+		return fmt.Errorf("%w: %w", ErrDeleteDocument, err)
+	}
+	return nil
+}
+
+// documents returns the texts and metadatas to store for the documents.
+func (s Store) documents(opts vectorstores.Options, docs []schema.Document) ([]string, []map[string]any, error) {
 	nameSpace := s.getNameSpace(opts)
 	if nameSpace != "" && s.nameSpaceKey == "" {
-		return nil, fmt.Errorf("%w: nameSpace without nameSpaceKey", ErrUnsupportedOptions)
+		return nil, nil, fmt.Errorf("%w: nameSpace without nameSpaceKey", ErrUnsupportedOptions)
 	}
 
-	ids := make([]string, len(docs))
 	texts := make([]string, len(docs))
 	metadatas := make([]map[string]any, len(docs))
 	for docIdx, doc := range docs {
-		ids[docIdx] = uuid.New().String() // TODO (noodnik2): find & use something more meaningful
 		texts[docIdx] = doc.PageContent
 		mc := make(map[string]any, 0)
 		maps.Copy(mc, doc.Metadata)
@@ -116,12 +211,7 @@ func (s Store) AddDocuments(ctx context.Context,
 			metadatas[docIdx][s.nameSpaceKey] = nameSpace
 		}
 	}
-
-	col := s.collection
-	if _, addErr := col.Add(ctx, nil, metadatas, texts, ids); addErr != nil {
-		return nil, fmt.Errorf("%w: %w", ErrAddDocument, addErr)
-	}
-	return ids, nil
+	return texts, metadatas, nil
 }
 
 func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments int,
@@ -139,7 +229,10 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 		return nil, stErr
 	}
 
-	filter := s.getNamespacedFilter(opts)
+	filter, err := s.getNamespacedFilter(opts)
+	if err != nil {
+		return nil, err
+	}
 	qr, queryErr := s.collection.Query(ctx, []string{query}, safeIntToInt32(numDocuments), filter, nil, s.includes)
 	if queryErr != nil {
 		return nil, queryErr
@@ -198,20 +291,26 @@ func (s Store) getNameSpace(opts vectorstores.Options) string {
 	return s.nameSpace
 }
 
-func (s Store) getNamespacedFilter(opts vectorstores.Options) map[string]any {
-	filter, _ := opts.Filters.(map[string]any)
+func (s Store) getNamespacedFilter(opts vectorstores.Options) (map[string]any, error) {
+	var filter map[string]any
+	if opts.Filters != nil {
+		var err error
+		if filter, err = whereFilter(opts.Filters); err != nil {
+			return nil, err
+		}
+	}
 
 	nameSpace := s.getNameSpace(opts)
 	if nameSpace == "" || s.nameSpaceKey == "" {
-		return filter
+		return filter, nil
 	}
 
 	nameSpaceFilter := map[string]any{s.nameSpaceKey: nameSpace}
 	if filter == nil {
-		return nameSpaceFilter
+		return nameSpaceFilter, nil
 	}
 
-	return map[string]any{"$and": []map[string]any{nameSpaceFilter, filter}}
+	return map[string]any{"$and": []map[string]any{nameSpaceFilter, filter}}, nil
 }
 
 func safeIntToInt32(n int) int32 {
@@ -615,6 +615,52 @@ func TestChromaAsRetrieverWithMetadataFilters(t *testing.T) {
 	require.Contains(t, result, "purple", "expected purple in result")
 }
 
+func TestChromaDeleteAndUpsert(t *testing.T) {
+	httprr.SkipIfNoCredentialsAndRecordingMissing(t, "CHROMA_URL")
+	rr := httprr.OpenForTest(t, http.DefaultTransport)
+	_ = rr // Chroma client doesn't support custom HTTP clients
+	if !rr.Recording() {
+		t.Parallel()
+	}
+
+	testChromaURL, openaiAPIKey := getValues(t)
+	e := createOpenAIEmbedder(t)
+
+	s, err := chroma.New(
+		chroma.WithOpenAIAPIKey(openaiAPIKey),
+		chroma.WithChromaURL(testChromaURL),
+		chroma.WithDistanceFunction(chromatypes.COSINE),
+		chroma.WithNameSpace(getTestNameSpace()),
+		chroma.WithEmbedder(e),
+	)
+	require.NoError(t, err)
+
+	defer cleanupTestArtifacts(t, s)
+
+	ctx := t.Context()
+	_, err = s.UpsertDocuments(ctx, []string{"tokyo", "potato"}, []schema.Document{
+		{PageContent: "tokyo", Metadata: map[string]any{"type": "city"}},
+		{PageContent: "potato", Metadata: map[string]any{"type": "vegetable"}},
+	})
+	require.NoError(t, err)
+	_, err = s.UpsertDocuments(ctx, []string{"tokyo"}, []schema.Document{
+		{PageContent: "kyoto", Metadata: map[string]any{"type": "city"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := s.SimilaritySearch(ctx, "japan", 2)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Equal(t, "kyoto", docs[0].PageContent)
+
+	require.NoError(t, s.DeleteByFilter(ctx, map[string]any{"type": "vegetable"}))
+	require.NoError(t, s.Delete(ctx, []string{"tokyo"}))
+
+	docs, err = s.SimilaritySearch(ctx, "japan", 2)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+}
+
 func getValues(t *testing.T) (string, string) {
 	t.Helper()
 	testctr.SkipIfDockerNotAvailable(t)
@@ -0,0 +1,56 @@
+package chroma
+
+import (
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+)
+
+// whereFilter returns the Chroma where clause for the filters given to
+// WithFilters, which are a where clause or a vectorstores.Filter.
+func whereFilter(filters any) (map[string]any, error) {
+	if f, ok := vectorstores.AsFilter(filters); ok {
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+		return translateFilter(f)
+	}
+	where, ok := filters.(map[string]any)
+	if !ok {
+		return nil, ErrInvalidFilters
+	}
+	return where, nil
+}
+
+// translateFilter translates f into a Chroma where clause. Chroma only
+// compares numbers, and doesn't match documents without the key with Ne or
+// Nin. It has no equivalent for Not, Exists and Contains.
+func translateFilter(f vectorstores.Filter) (map[string]any, error) {
+	switch f.Operator {
+	case vectorstores.FilterAnd, vectorstores.FilterOr:
+		clauses := make([]map[string]any, 0, len(f.Filters))
+		for _, operand := range f.Filters {
+			clause, err := translateFilter(operand)
+			if err != nil {
+				return nil, err
+			}
+			clauses = append(clauses, clause)
+		}
+		// Chroma requires at least two clauses in $and and $or.
+		if len(clauses) == 1 {
+			return clauses[0], nil
+		}
+		return map[string]any{"$" + string(f.Operator): clauses}, nil
+	case vectorstores.FilterEq, vectorstores.FilterNe:
+		return map[string]any{f.Key: map[string]any{"$" + string(f.Operator): f.Value}}, nil
+	case vectorstores.FilterIn, vectorstores.FilterNin:
+		return map[string]any{f.Key: map[string]any{"$" + string(f.Operator): f.Values()}}, nil
+	case vectorstores.FilterGt, vectorstores.FilterGte, vectorstores.FilterLt, vectorstores.FilterLte:
+		if _, ok := vectorstores.FilterNumber(f.Value); !ok {
+			return nil, &vectorstores.UnsupportedFilterError{
+				Store: "chroma", Operator: f.Operator, Reason: "only numbers can be compared",
+			}
+		}
+		return map[string]any{f.Key: map[string]any{"$" + string(f.Operator): f.Value}}, nil
+	default:
+		return nil, &vectorstores.UnsupportedFilterError{Store: "chroma", Operator: f.Operator}
+	}
+}
@@ -0,0 +1,54 @@
+package chroma
+
+import (
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhereFilter(t *testing.T) {
+	t.Parallel()
+
+	where, err := whereFilter(vectorstores.And(
+		vectorstores.Eq("source", "a.md"),
+		vectorstores.Or(vectorstores.Gte("year", 2020)),
+		vectorstores.Nin("lang", "go", "rust"),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"$and": []map[string]any{
+		{"source": map[string]any{"$eq": "a.md"}},
+		{"year": map[string]any{"$gte": 2020}},
+		{"lang": map[string]any{"$nin": []any{"go", "rust"}}},
+	}}, where)
+
+	native := map[string]any{"source": "a.md"}
+	where, err = whereFilter(native)
+	require.NoError(t, err)
+	assert.Equal(t, native, where)
+
+	_, err = whereFilter("source")
+	require.ErrorIs(t, err, ErrInvalidFilters)
+	_, err = whereFilter(vectorstores.Not(vectorstores.Eq("source", "a.md")))
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+	_, err = whereFilter(vectorstores.Gt("source", "a"))
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+}
+
+func TestGetNamespacedFilter(t *testing.T) {
+	t.Parallel()
+
+	s := Store{nameSpaceKey: "ns", nameSpace: "docs"}
+	where, err := s.getNamespacedFilter(vectorstores.Options{Filters: vectorstores.Exists("source")})
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+	assert.Nil(t, where)
+
+	where, err = s.getNamespacedFilter(vectorstores.Options{Filters: vectorstores.Ne("source", "a.md")})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"$and": []map[string]any{
+		{"ns": "docs"},
+		{"source": map[string]any{"$ne": "a.md"}},
+	}}, where)
+}
@@ -2,6 +2,7 @@ package cloudsql_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"github.com/sayerxofficial/langchaingo/internal/httprr"
 	"github.com/sayerxofficial/langchaingo/schema"
 	"github.com/sayerxofficial/langchaingo/util/cloudsqlutil"
+	"github.com/sayerxofficial/langchaingo/vectorstores"
 	"github.com/sayerxofficial/langchaingo/vectorstores/cloudsql"
 )
 
@@ -264,3 +266,13 @@ func TestAddDocuments(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestSimilaritySearchUnsupportedFilter(t *testing.T) {
+	t.Parallel()
+
+	var vs cloudsql.VectorStore
+	_, err := vs.SimilaritySearch(t.Context(), "cities", 1, vectorstores.WithFilters(vectorstores.Eq("source", "a.md")))
+	if !errors.Is(err, vectorstores.ErrUnsupportedFilter) {
+		t.Fatalf("got %v, want %v", err, vectorstores.ErrUnsupportedFilter)
+	}
+}
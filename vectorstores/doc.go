@@ -4,9 +4,17 @@ and querying documents as vector embeddings.
 
 The main components of this package are:
 
-- VectorStore interface: a common interface for saving and querying vector embeddings of documents.
-- Options: a set of options for similarity search and document addition.
-- Retriever: a retriever for vector stores that implements the schema.Retriever interface.
+  - VectorStore interface: a common interface for saving and querying vector embeddings of documents.
+  - Deleter, FilterDeleter and Upserter: optional interfaces, detected by type assertion, for stores
+    that can delete documents by id or by filter and store documents under caller-supplied ids.
+  - Filter: a portable metadata filter built with Eq, In, Gt, And, Or and the other constructors,
+    that pgvector, Qdrant, Chroma, Pinecone, Milvus, OpenSearch, Redis and the in-memory store
+    translate to their native filters.
+  - MaxMarginalRelevanceSearch: a search returning relevant but diverse documents, that Qdrant and
+    the in-memory store run natively and that pgvector runs from the embeddings of its results.
+  - Options: a set of options for similarity search and document addition.
+  - Retriever: a retriever for vector stores that implements the schema.Retriever interface, built
+    with ToRetriever or ToMMRRetriever.
 
 The package provides a flexible way to handle different types of vector stores
 by using the VectorStore interface as an abstraction.
@@ -0,0 +1,413 @@
+package vectorstores
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// FilterOperator is the operator of a Filter.
+type FilterOperator string
+
+const (
+	// FilterEq matches documents whose metadata value equals the value.
+	FilterEq FilterOperator = "eq"
+	// FilterNe matches documents whose metadata value differs from the value,
+	// including documents without the key.
+	FilterNe FilterOperator = "ne"
+	// FilterIn matches documents whose metadata value is one of the values.
+	FilterIn FilterOperator = "in"
+	// FilterNin matches documents whose metadata value is none of the values,
+	// including documents without the key.
+	FilterNin FilterOperator = "nin"
+	// FilterGt matches documents whose metadata value is greater than the value.
+	FilterGt FilterOperator = "gt"
+	// FilterGte matches documents whose metadata value is greater than or equal
+	// to the value.
+	FilterGte FilterOperator = "gte"
+	// FilterLt matches documents whose metadata value is less than the value.
+	FilterLt FilterOperator = "lt"
+	// FilterLte matches documents whose metadata value is less than or equal to
+	// the value.
+	FilterLte FilterOperator = "lte"
+	// FilterExists matches documents that have the key.
+	FilterExists FilterOperator = "exists"
+	// FilterContains matches documents whose metadata value is a list that
+	// contains the value.
+	FilterContains FilterOperator = "contains"
+	// FilterAnd matches documents matching all of the filters.
+	FilterAnd FilterOperator = "and"
+	// FilterOr matches documents matching any of the filters.
+	FilterOr FilterOperator = "or"
+	// FilterNot matches documents that don't match the filter.
+	FilterNot FilterOperator = "not"
+)
+
+var (
+	// ErrInvalidFilter is returned when a Filter is malformed.
+	ErrInvalidFilter = errors.New("invalid filter")
+	// ErrUnsupportedFilter is returned when a store can't translate a Filter.
+	ErrUnsupportedFilter = errors.New("unsupported filter")
+)
+
+// Filter is a portable metadata filter. Stores that support it accept it in
+// WithFilters in place of their native filter format and translate it, so
+// that an application can switch stores without rewriting its filters. Build
+// filters with Eq, In, And and the other constructors, for example:
+//
+//	vectorstores.And(
+//		vectorstores.Eq("source", "README.md"),
+//		vectorstores.Gte("year", 2020),
+//	)
+//
+// Values are strings, numbers and booleans. Comparisons with Gt, Gte, Lt and
+// Lte are numeric, and some stores also compare strings.
+type Filter struct {
+	Operator FilterOperator
+	// Key is the metadata key the filter applies to. It is empty for And, Or
+	// and Not.
+	Key string
+	// Value is the value to compare with. It is a []any for In and Nin.
+	Value any
+	// Filters are the operands of And, Or and Not.
+	Filters []Filter
+}
+
+// Eq returns a filter matching documents whose value for key equals value.
+func Eq(key string, value any) Filter {
+	return Filter{Operator: FilterEq, Key: key, Value: value}
+}
+
+// Ne returns a filter matching documents whose value for key differs from
+// value, including documents without the key.
+func Ne(key string, value any) Filter {
+	return Filter{Operator: FilterNe, Key: key, Value: value}
+}
+
+// In returns a filter matching documents whose value for key is one of values.
+func In(key string, values ...any) Filter {
+	return Filter{Operator: FilterIn, Key: key, Value: values}
+}
+
+// Nin returns a filter matching documents whose value for key is none of
+// values, including documents without the key.
+func Nin(key string, values ...any) Filter {
+	return Filter{Operator: FilterNin, Key: key, Value: values}
+}
+
+// Gt returns a filter matching documents whose value for key is greater than
+// value.
+func Gt(key string, value any) Filter {
+	return Filter{Operator: FilterGt, Key: key, Value: value}
+}
+
+// Gte returns a filter matching documents whose value for key is greater than
+// or equal to value.
+func Gte(key string, value any) Filter {
+	return Filter{Operator: FilterGte, Key: key, Value: value}
+}
+
+// Lt returns a filter matching documents whose value for key is less than
+// value.
+func Lt(key string, value any) Filter {
+	return Filter{Operator: FilterLt, Key: key, Value: value}
+}
+
+// Lte returns a filter matching documents whose value for key is less than or
+// equal to value.
+func Lte(key string, value any) Filter {
+	return Filter{Operator: FilterLte, Key: key, Value: value}
+}
+
+// Exists returns a filter matching documents that have key.
+func Exists(key string) Filter {
+	return Filter{Operator: FilterExists, Key: key}
+}
+
+// Contains returns a filter matching documents whose value for key is a list
+// containing value.
+func Contains(key string, value any) Filter {
+	return Filter{Operator: FilterContains, Key: key, Value: value}
+}
+
+// And returns a filter matching documents that match all of filters.
+func And(filters ...Filter) Filter {
+	return Filter{Operator: FilterAnd, Filters: filters}
+}
+
+// Or returns a filter matching documents that match any of filters.
+func Or(filters ...Filter) Filter {
+	return Filter{Operator: FilterOr, Filters: filters}
+}
+
+// Not returns a filter matching documents that don't match filter.
+func Not(filter Filter) Filter {
+	return Filter{Operator: FilterNot, Filters: []Filter{filter}}
+}
+
+// AsFilter returns the Filter given to WithFilters, as a Filter or a *Filter.
+// It reports false if filters is something else, such as a store specific
+// filter.
+func AsFilter(filters any) (Filter, bool) {
+	switch f := filters.(type) {
+	case Filter:
+		return f, true
+	case *Filter:
+		if f != nil {
+			return *f, true
+		}
+	}
+	return Filter{}, false
+}
+
+// Values returns the values of an In or Nin filter.
+func (f Filter) Values() []any {
+	if values, ok := f.Value.([]any); ok {
+		return values
+	}
+	return nil
+}
+
+// Validate checks that the filter and its operands are well formed. It
+// returns an error wrapping ErrInvalidFilter otherwise.
+func (f Filter) Validate() error { //nolint:cyclop
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s: %s", ErrInvalidFilter, f.Operator, fmt.Sprintf(format, args...))
+	}
+
+	switch f.Operator {
+	case FilterAnd, FilterOr:
+		if len(f.Filters) == 0 {
+			return invalid("needs at least one filter")
+		}
+	case FilterNot:
+		if len(f.Filters) != 1 {
+			return invalid("needs exactly one filter")
+		}
+	case FilterEq, FilterNe, FilterContains:
+		if !isFilterScalar(f.Value) {
+			return invalid("unsupported value %v of type %T", f.Value, f.Value)
+		}
+	case FilterIn, FilterNin:
+		values, ok := f.Value.([]any)
+		if !ok || len(values) == 0 {
+			return invalid("needs at least one value")
+		}
+		for _, v := range values {
+			if !isFilterScalar(v) {
+				return invalid("unsupported value %v of type %T", v, v)
+			}
+		}
+	case FilterGt, FilterGte, FilterLt, FilterLte:
+		if _, ok := FilterNumber(f.Value); !ok {
+			if _, ok := f.Value.(string); !ok {
+				return invalid("can only compare numbers and strings, got %T", f.Value)
+			}
+		}
+	case FilterExists:
+	default:
+		return fmt.Errorf("%w: unknown operator %q", ErrInvalidFilter, f.Operator)
+	}
+
+	switch f.Operator { //nolint:exhaustive
+	case FilterAnd, FilterOr, FilterNot:
+		for _, operand := range f.Filters {
+			if err := operand.Validate(); err != nil {
+				return err
+			}
+		}
+	default:
+		if f.Key == "" {
+			return invalid("missing key")
+		}
+	}
+	return nil
+}
+
+// Match reports whether metadata matches the filter. Stores that filter
+// documents in memory use it.
+func (f Filter) Match(metadata map[string]any) bool { //nolint:cyclop
+	value, ok := metadata[f.Key]
+
+	switch f.Operator {
+	case FilterEq:
+		return ok && filterEqual(value, f.Value)
+	case FilterNe:
+		return !ok || !filterEqual(value, f.Value)
+	case FilterIn:
+		return ok && filterContains(f.Values(), value)
+	case FilterNin:
+		return !ok || !filterContains(f.Values(), value)
+	case FilterGt, FilterGte, FilterLt, FilterLte:
+		if !ok {
+			return false
+		}
+		cmp, comparable := filterCompare(value, f.Value)
+		if !comparable {
+			return false
+		}
+		switch f.Operator { //nolint:exhaustive
+		case FilterGt:
+			return cmp > 0
+		case FilterGte:
+			return cmp >= 0
+		case FilterLt:
+			return cmp < 0
+		default:
+			return cmp <= 0
+		}
+	case FilterExists:
+		return ok
+	case FilterContains:
+		if !ok {
+			return false
+		}
+		list := reflect.ValueOf(value)
+		if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+			return false
+		}
+		for i := range list.Len() {
+			if filterEqual(list.Index(i).Interface(), f.Value) {
+				return true
+			}
+		}
+		return false
+	case FilterAnd:
+		for _, operand := range f.Filters {
+			if !operand.Match(metadata) {
+				return false
+			}
+		}
+		return true
+	case FilterOr:
+		for _, operand := range f.Filters {
+			if operand.Match(metadata) {
+				return true
+			}
+		}
+		return false
+	case FilterNot:
+		return len(f.Filters) == 1 && !f.Filters[0].Match(metadata)
+	default:
+		return false
+	}
+}
+
+// UnsupportedFilterError is returned by a store that can't translate a
+// filter, because the store has no equivalent for an operator or a value.
+type UnsupportedFilterError struct {
+	// Store is the name of the store.
+	Store string
+	// Operator is the operator that can't be translated.
+	Operator FilterOperator
+	// Reason explains why, if the operator is supported for other values.
+	Reason string
+}
+
+func (e *UnsupportedFilterError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("%s: %s does not support the %q operator: %s", ErrUnsupportedFilter, e.Store, e.Operator, e.Reason)
+	}
+	return fmt.Sprintf("%s: %s does not support the %q operator", ErrUnsupportedFilter, e.Store, e.Operator)
+}
+
+func (e *UnsupportedFilterError) Unwrap() error {
+	return ErrUnsupportedFilter
+}
+
+// FilterNumber returns v as a float64 if it is a number.
+func FilterNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+func isFilterScalar(v any) bool {
+	if _, ok := FilterNumber(v); ok {
+		return true
+	}
+	switch v.(type) {
+	case string, bool:
+		return true
+	default:
+		return false
+	}
+}
+
+// filterEqual compares two values, numbers by value whatever their type.
+func filterEqual(a, b any) bool {
+	if x, ok := FilterNumber(a); ok {
+		y, ok := FilterNumber(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func filterContains(values []any, value any) bool {
+	for _, v := range values {
+		if filterEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterCompare compares two numbers or two strings. It reports false if the
+// values can't be compared.
+func filterCompare(a, b any) (int, bool) {
+	if x, ok := FilterNumber(a); ok {
+		y, ok := FilterNumber(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+	x, ok := a.(string)
+	if !ok {
+		return 0, false
+	}
+	y, ok := b.(string)
+	if !ok {
+		return 0, false
+	}
+	switch {
+	case x < y:
+		return -1, true
+	case x > y:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
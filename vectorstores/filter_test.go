@@ -0,0 +1,121 @@
+package vectorstores_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterMatch(t *testing.T) {
+	t.Parallel()
+
+	meta := map[string]any{
+		"source": "README.md",
+		"year":   2021,
+		"score":  0.5,
+		"draft":  false,
+		"tags":   []any{"go", "llm"},
+	}
+
+	tests := []struct {
+		name   string
+		filter vectorstores.Filter
+		want   bool
+	}{
+		{"eq", vectorstores.Eq("source", "README.md"), true},
+		{"eq mismatch", vectorstores.Eq("source", "main.go"), false},
+		{"eq number types", vectorstores.Eq("year", 2021.0), true},
+		{"eq missing", vectorstores.Eq("author", "x"), false},
+		{"ne", vectorstores.Ne("source", "main.go"), true},
+		{"ne missing", vectorstores.Ne("author", "x"), true},
+		{"in", vectorstores.In("year", 2020, 2021), true},
+		{"in mismatch", vectorstores.In("year", 2019, 2020), false},
+		{"nin", vectorstores.Nin("year", 2019, 2020), true},
+		{"nin missing", vectorstores.Nin("author", "x"), true},
+		{"gt", vectorstores.Gt("year", 2020), true},
+		{"gt equal", vectorstores.Gt("year", 2021), false},
+		{"gte", vectorstores.Gte("year", 2021), true},
+		{"lt", vectorstores.Lt("score", 0.6), true},
+		{"lte", vectorstores.Lte("score", 0.4), false},
+		{"lt string", vectorstores.Lt("source", "Z"), true},
+		{"gt mismatched types", vectorstores.Gt("source", 1), false},
+		{"exists", vectorstores.Exists("draft"), true},
+		{"exists missing", vectorstores.Exists("author"), false},
+		{"contains", vectorstores.Contains("tags", "go"), true},
+		{"contains mismatch", vectorstores.Contains("tags", "rust"), false},
+		{"contains not a list", vectorstores.Contains("source", "README.md"), false},
+		{"and", vectorstores.And(vectorstores.Eq("draft", false), vectorstores.Gte("year", 2021)), true},
+		{"and mismatch", vectorstores.And(vectorstores.Eq("draft", true), vectorstores.Gte("year", 2021)), false},
+		{"or", vectorstores.Or(vectorstores.Eq("draft", true), vectorstores.Gte("year", 2021)), true},
+		{"not", vectorstores.Not(vectorstores.Eq("draft", true)), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.NoError(t, tt.filter.Validate())
+			assert.Equal(t, tt.want, tt.filter.Match(meta))
+		})
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		filter vectorstores.Filter
+	}{
+		{"unknown operator", vectorstores.Filter{Operator: "like", Key: "a", Value: "b"}},
+		{"missing key", vectorstores.Eq("", "b")},
+		{"unsupported value", vectorstores.Eq("a", map[string]any{"b": 1})},
+		{"empty in", vectorstores.In("a")},
+		{"compare bool", vectorstores.Gt("a", true)},
+		{"empty and", vectorstores.And()},
+		{"nested", vectorstores.Or(vectorstores.Eq("a", 1), vectorstores.Nin("b"))},
+		{"not without operand", vectorstores.Filter{Operator: vectorstores.FilterNot}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.ErrorIs(t, tt.filter.Validate(), vectorstores.ErrInvalidFilter)
+		})
+	}
+}
+
+func TestAsFilter(t *testing.T) {
+	t.Parallel()
+
+	f := vectorstores.Eq("a", 1)
+
+	got, ok := vectorstores.AsFilter(f)
+	require.True(t, ok)
+	assert.Equal(t, f, got)
+
+	got, ok = vectorstores.AsFilter(&f)
+	require.True(t, ok)
+	assert.Equal(t, f, got)
+
+	_, ok = vectorstores.AsFilter(map[string]any{"a": 1})
+	assert.False(t, ok)
+
+	_, ok = vectorstores.AsFilter((*vectorstores.Filter)(nil))
+	assert.False(t, ok)
+}
+
+func TestUnsupportedFilterError(t *testing.T) {
+	t.Parallel()
+
+	var err error = &vectorstores.UnsupportedFilterError{Store: "chroma", Operator: vectorstores.FilterExists}
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+	assert.Equal(t, `unsupported filter: chroma does not support the "exists" operator`, err.Error())
+
+	var unsupported *vectorstores.UnsupportedFilterError
+	require.True(t, errors.As(err, &unsupported))
+	assert.Equal(t, vectorstores.FilterExists, unsupported.Operator)
+}
@@ -1,11 +1,15 @@
 package inmemory
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
+	"maps"
 	"slices"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/sayerxofficial/langchaingo/embeddings"
 	"github.com/sayerxofficial/langchaingo/schema"
@@ -18,6 +22,17 @@ var (
 	ErrEmbedderWrongNumberVectors = errors.New("number of vectors from embedder does not match number of documents")
 	ErrInvalidScoreThreshold      = errors.New("score threshold must be between 0 and 1")
 	ErrUnsupportedOptions         = errors.New("unsupported options")
+	ErrInvalidFilters             = errors.New("invalid filters")
+	ErrVectorSizeMismatch         = errors.New("vector size does not match the size of the stored vectors")
+)
+
+var (
+	_ vectorstores.VectorStore   = (*Store)(nil)
+	_ vectorstores.Deleter       = (*Store)(nil)
+	_ vectorstores.FilterDeleter = (*Store)(nil)
+	_ vectorstores.Upserter      = (*Store)(nil)
+
+	_ vectorstores.MaxMarginalRelevanceSearcher = (*Store)(nil)
 )
 
 // Store is a struct that holds the in-memory vector store.
@@ -25,11 +40,23 @@ type Store struct {
 	sync.RWMutex
 
 	// HNSW index
-	index      *hnsw.Graph[uint32]
+	index    *hnsw.Graph[uint32]
+	content  map[uint32]string
+	meta     map[uint32]map[string]any
+	embedder embeddings.Embedder
+
+	// vectorSize is the size of the stored vectors, 0 until it is set by
+	// WithVectorSize or the first vector added.
 	vectorSize int
-	content    map[uint32]string
-	meta       map[uint32]map[string]any
-	embedder   embeddings.Embedder
+
+	// embedderName is checked by Load against the name of the saved store.
+	embedderName string
+
+	// ids maps the keys of the index to the ids returned to callers, and
+	// keys is its reverse. Ids are unique within a name space.
+	ids        map[uint32]string
+	keys       map[docKey]uint32
+	namespaces map[uint32]string
 
 	// HNSW index parameters
 	m              int
@@ -39,6 +66,25 @@ type Store struct {
 	// size limit of the store
 	sizeLimit int
 	lastID    uint32
+
+	// changes counts the changes of the store, so that autosave only saves
+	// it when it changed since savedChanges. saveMu guards savedChanges and
+	// serializes the saves to the autosave file.
+	changes      uint64
+	savedChanges uint64
+	saveMu       sync.Mutex
+
+	autosavePath     string
+	autosaveInterval time.Duration
+	autosaveStop     chan struct{}
+	autosaveDone     chan struct{}
+	closeOnce        sync.Once
+}
+
+// docKey identifies a document by its name space and id.
+type docKey struct {
+	namespace string
+	id        string
 }
 
 // New returns a new InMemory store with options.
@@ -50,37 +96,27 @@ func New(ctx context.Context, opts ...Option) (*Store, error) {
 	store := applyOptions(opts)
 
 	// Initialize the HNSW graph
-	store.index = hnsw.NewGraph[uint32]()
-
-	// Configure graph parameters
-	store.index.M = store.m
-	store.index.Ml = 0.5 // Default parameter in the library
-
-	// Set the distance function to cosine distance
-	store.index.Distance = hnsw.CosineDistance
-
-	// Set efSearch parameter
-	store.index.EfSearch = store.efSearch
+	store.index = store.newGraph()
 
 	// Initialize maps
 	store.content = make(map[uint32]string)
 	store.meta = make(map[uint32]map[string]any)
+	store.ids = make(map[uint32]string)
+	store.keys = make(map[docKey]uint32)
+	store.namespaces = make(map[uint32]string)
 
+	store.startAutosave()
 	return store, nil
 }
 
-// AddDocuments adds documents to the in-memory store
-// and returns the ids of the added documents.
+// AddDocuments adds documents to the in-memory store, in the name space given
+// with WithNameSpace if any, and returns the ids of the added documents.
 func (s *Store) AddDocuments(
 	ctx context.Context,
 	docs []schema.Document,
 	options ...vectorstores.Option,
 ) ([]string, error) {
 	opts := s.getOptions(options...)
-	if opts.NameSpace != "" {
-		// in-memory store does not support these options
-		return nil, ErrUnsupportedOptions
-	}
 
 	docs = s.deduplicate(ctx, opts, docs)
 
@@ -103,81 +139,411 @@ func (s *Store) AddDocuments(
 		return nil, ErrEmbedderWrongNumberVectors
 	}
 
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.checkVectorsLocked(vectors); err != nil {
+		return nil, err
+	}
+
 	ids := make([]string, len(vectors))
 	for i, vec := range vectors {
-		s.Lock()
+		key := s.nextKey(opts.NameSpace)
+		ids[i] = strconv.FormatUint(uint64(key), 10)
+		if err := s.addLocked(key, docKey{namespace: opts.NameSpace, id: ids[i]}, docs[i], vec); err != nil {
+			return nil, err
+		}
+	}
 
-		id := s.lastID + 1
+	return ids, nil
+}
 
-		s.index.Add(hnsw.MakeNode(id, vec))
-		s.lastID = id
+// UpsertDocuments adds documents to the in-memory store under the given ids,
+// replacing the documents already stored with the same ids in the name space.
+func (s *Store) UpsertDocuments(
+	ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) ([]string, error) {
+	opts := s.getOptions(options...)
+	if len(ids) != len(docs) {
+		return nil, vectorstores.ErrMismatchedIDs
+	}
 
-		s.content[id] = docs[i].PageContent
-		s.meta[id] = docs[i].Metadata
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
 
-		s.Unlock()
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
 
-		ids[i] = strconv.FormatUint(uint64(id), 10)
+	vectors, err := embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
 	}
 
-	return ids, nil
+	if len(vectors) != len(docs) {
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	// The vectors are checked before the replaced documents are removed, so
+	// that a refused upsert leaves the store as it was.
+	if err := s.checkVectorsLocked(vectors); err != nil {
+		return nil, err
+	}
+
+	replaced := make([]uint32, 0, len(ids))
+	for _, id := range ids {
+		if key, ok := s.keys[docKey{namespace: opts.NameSpace, id: id}]; ok {
+			replaced = append(replaced, key)
+		}
+	}
+	s.removeLocked(replaced)
+
+	for i, vec := range vectors {
+		dk := docKey{namespace: opts.NameSpace, id: ids[i]}
+		if key, ok := s.keys[dk]; ok {
+			// The same id is given more than once, the last document wins.
+			s.removeLocked([]uint32{key})
+		}
+		if err := s.addLocked(s.nextKey(opts.NameSpace), dk, docs[i], vec); err != nil {
+			return nil, err
+		}
+	}
+
+	return slices.Clone(ids), nil
+}
+
+// Delete removes the documents with the given ids from the name space.
+func (s *Store) Delete(_ context.Context, ids []string, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+
+	s.Lock()
+	defer s.Unlock()
+
+	keys := make([]uint32, 0, len(ids))
+	for _, id := range ids {
+		if key, ok := s.keys[docKey{namespace: opts.NameSpace, id: id}]; ok {
+			keys = append(keys, key)
+		}
+	}
+	s.removeLocked(keys)
+
+	return nil
+}
+
+// DeleteByFilter removes the documents of the name space whose metadata
+// matches the filter, which is a map[string]any or a vectorstores.Filter like
+// the ones given to WithFilters.
+func (s *Store) DeleteByFilter(_ context.Context, filter any, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+
+	if filters, ok := filter.(map[string]any); ok && len(filters) == 0 {
+		return vectorstores.ErrEmptyFilter
+	}
+	match, err := metadataMatcher(filter)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	keys := make([]uint32, 0)
+	for key, meta := range s.meta {
+		if s.namespaces[key] == opts.NameSpace && match(meta) {
+			keys = append(keys, key)
+		}
+	}
+	s.removeLocked(keys)
+
+	return nil
 }
 
+// SimilaritySearch returns the documents of the name space nearest to the
+// query, that match the filters and score threshold if any. The search keeps
+// expanding until it finds numDocuments matching documents or runs out of
+// documents, so selective filters don't return fewer results than there are
+// matching documents.
 func (s *Store) SimilaritySearch(
 	ctx context.Context,
 	query string,
 	numDocuments int,
 	options ...vectorstores.Option,
 ) ([]schema.Document, error) {
+	vec, accept, threshold, err := s.prepareSearch(ctx, query, options)
+	if err != nil {
+		return nil, err
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	neighbors := s.searchLocked(vec, numDocuments, threshold, accept)
+	docs := make([]schema.Document, 0, len(neighbors))
+	for _, n := range neighbors {
+		docs = append(docs, s.documentLocked(n.key, n.score))
+	}
+	return docs, nil
+}
+
+// MaxMarginalRelevanceSearch returns k documents of the name space selected
+// by maximal marginal relevance among the fetchK documents nearest to the
+// query, using the vectors of the index. It takes the same options as
+// SimilaritySearch. See vectorstores.MaxMarginalRelevanceSearch.
+func (s *Store) MaxMarginalRelevanceSearch(
+	ctx context.Context,
+	query string,
+	k, fetchK int,
+	lambda float32,
+	options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	if lambda < 0 || lambda > 1 {
+		return nil, vectorstores.ErrInvalidLambda
+	}
+	vec, accept, threshold, err := s.prepareSearch(ctx, query, options)
+	if err != nil {
+		return nil, err
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	neighbors := s.searchLocked(vec, max(k, fetchK), threshold, accept)
+	vectors := make([][]float32, 0, len(neighbors))
+	for _, n := range neighbors {
+		value, _ := s.index.Lookup(n.key)
+		vectors = append(vectors, value)
+	}
+	selected := vectorstores.MaxMarginalRelevance(vec, vectors, k, lambda)
+	docs := make([]schema.Document, 0, len(selected))
+	for _, i := range selected {
+		docs = append(docs, s.documentLocked(neighbors[i].key, neighbors[i].score))
+	}
+	return docs, nil
+}
+
+// prepareSearch embeds the query and returns its vector, the function
+// accepting the keys of the documents to search and the score threshold.
+func (s *Store) prepareSearch(
+	ctx context.Context,
+	query string,
+	options []vectorstores.Option,
+) ([]float32, func(uint32) bool, float32, error) {
 	opts := s.getOptions(options...)
-	if opts.NameSpace != "" {
-		// in-memory store does not support these options
-		return nil, ErrUnsupportedOptions
+	if opts.ScoreThreshold < 0 || opts.ScoreThreshold > 1 {
+		return nil, nil, 0, ErrInvalidScoreThreshold
 	}
 
-	var filters map[string]any
-	if f, ok := opts.Filters.(map[string]any); ok {
-		filters = f
+	var match func(map[string]any) bool
+	if opts.Filters != nil {
+		var err error
+		if match, err = metadataMatcher(opts.Filters); err != nil {
+			return nil, nil, 0, err
+		}
 	}
 
 	embedder := s.embedder
 	if opts.Embedder != nil {
 		embedder = opts.Embedder
 	}
-	embedderData, err := embedder.EmbedQuery(ctx, query)
+	vec, err := embedder.EmbedQuery(ctx, query)
 	if err != nil {
-		return nil, err
+		return nil, nil, 0, err
 	}
 
-	// this returns a slice of Node objects that contain both the key and the vector
-	neighbors := s.index.Search(embedderData, numDocuments)
+	// accept must be called with the lock held.
+	accept := func(key uint32) bool {
+		return s.namespaces[key] == opts.NameSpace && (match == nil || match(s.meta[key]))
+	}
+	return vec, accept, opts.ScoreThreshold, nil
+}
 
-	docs := make([]schema.Document, 0, len(neighbors))
-	for _, n := range neighbors {
-		s.RLock()
+// neighbor is a key of the index with its similarity score to a query.
+type neighbor struct {
+	key   uint32
+	score float32
+}
 
-		// calculate similarity score as 1 - cosine distance
-		similarity := 1.0 - float64(hnsw.CosineDistance(embedderData, n.Value))
+// searchLocked returns the k keys nearest to vec among the accepted ones
+// with a score of at least threshold, by decreasing score. The HNSW graph
+// can't filter while it searches, so its neighbors are only used when the
+// nearest ones are all accepted, as in a search without filter. Otherwise the
+// neighbors it returns can miss accepted documents further away, and the
+// accepted documents are scanned to find the true nearest ones. The caller
+// must hold the lock.
+func (s *Store) searchLocked(vec []float32, k int, threshold float32, accept func(uint32) bool) []neighbor {
+	if k <= 0 {
+		return []neighbor{}
+	}
 
-		doc := schema.Document{
-			PageContent: s.content[n.Key],
-			Metadata:    s.meta[n.Key],
-			Score:       float32(similarity),
+	// Like the ef parameter of HNSW, searching more candidates than k
+	// improves the recall of the k nearest ones.
+	results := s.index.Search(vec, max(k, s.efSearch))
+	candidates := make([]neighbor, 0, len(results))
+	for _, n := range results {
+		candidates = append(candidates, neighbor{key: n.Key, score: similarity(vec, n.Value)})
+	}
+	candidates = nearest(candidates, len(candidates))
+
+	found := make([]neighbor, 0, k)
+	for _, n := range candidates {
+		if n.score < threshold {
+			return found
+		}
+		if !accept(n.key) {
+			return s.scanLocked(vec, k, threshold, accept)
 		}
-		s.RUnlock()
+		if found = append(found, n); len(found) == k {
+			return found
+		}
+	}
+	if len(candidates) < s.index.Len() {
+		return s.scanLocked(vec, k, threshold, accept)
+	}
+	return found
+}
 
-		docs = append(docs, doc)
+// scanLocked returns the k keys nearest to vec among the accepted ones with
+// a score of at least threshold, by decreasing score, comparing vec with every
+// accepted document. The caller must hold the lock.
+func (s *Store) scanLocked(vec []float32, k int, threshold float32, accept func(uint32) bool) []neighbor {
+	found := make([]neighbor, 0, k)
+	for key := range s.content {
+		if !accept(key) {
+			continue
+		}
+		value, ok := s.index.Lookup(key)
+		if !ok {
+			continue
+		}
+		if score := similarity(vec, value); score >= threshold {
+			found = append(found, neighbor{key: key, score: score})
+		}
 	}
+	return nearest(found, k)
+}
 
-	docs = applyFilters(docs, filters)
-	docs, err = applyScoreThreshold(docs, opts.ScoreThreshold)
-	if err != nil {
-		return nil, err
+// documentLocked returns the document stored under key. The caller must hold
+// the lock.
+func (s *Store) documentLocked(key uint32, score float32) schema.Document {
+	return schema.Document{
+		PageContent: s.content[key],
+		Metadata:    s.meta[key],
+		Score:       score,
 	}
+}
 
-	// already sorted by increasing distance, so reverse to get highest similarity first
-	slices.Reverse(docs)
-	return docs, nil
+// similarity returns the similarity score of two vectors, as 1 - cosine
+// distance.
+func similarity(a, b []float32) float32 {
+	return float32(1.0 - float64(hnsw.CosineDistance(a, b)))
+}
+
+// nearest sorts the neighbors by decreasing score and returns the first k.
+func nearest(neighbors []neighbor, k int) []neighbor {
+	slices.SortStableFunc(neighbors, func(a, b neighbor) int {
+		return cmp.Compare(b.score, a.score)
+	})
+	return neighbors[:min(k, len(neighbors))]
+}
+
+// nextKey returns the next free key of the index. Keys are never reused, and
+// skip the values that would collide with an id given to UpsertDocuments in
+// the name space. The caller must hold the lock.
+func (s *Store) nextKey(namespace string) uint32 {
+	for {
+		s.lastID++
+		if _, ok := s.keys[docKey{namespace: namespace, id: strconv.FormatUint(uint64(s.lastID), 10)}]; !ok {
+			return s.lastID
+		}
+	}
+}
+
+// checkVectorsLocked returns an error wrapping ErrVectorSizeMismatch if the
+// vectors don't all have the size of the stored vectors, or the same size if
+// the store has none yet. The caller must hold the lock.
+func (s *Store) checkVectorsLocked(vectors [][]float32) error {
+	size := s.vectorSize
+	for _, vec := range vectors {
+		if size == 0 {
+			size = len(vec)
+		}
+		if len(vec) != size {
+			return fmt.Errorf("%w: got %d, want %d", ErrVectorSizeMismatch, len(vec), size)
+		}
+	}
+	return nil
+}
+
+// addLocked adds a document to the index under key, with the name space and
+// id of dk. The first vector added sets the vector size of the store unless
+// WithVectorSize did. The caller must hold the lock.
+func (s *Store) addLocked(key uint32, dk docKey, doc schema.Document, vec []float32) error {
+	if err := s.checkVectorsLocked([][]float32{vec}); err != nil {
+		return err
+	}
+	s.vectorSize = len(vec)
+
+	s.index.Add(hnsw.MakeNode(key, vec))
+
+	s.content[key] = doc.PageContent
+	s.meta[key] = doc.Metadata
+	s.ids[key] = dk.id
+	s.keys[dk] = key
+	s.namespaces[key] = dk.namespace
+	s.changes++
+	return nil
+}
+
+// removeLocked removes the documents with the given keys. The HNSW graph is
+// rebuilt from the remaining nodes, because deleting nodes in place can leave
+// its upper layers without an entry point. The caller must hold the lock.
+func (s *Store) removeLocked(keys []uint32) {
+	if len(keys) == 0 {
+		return
+	}
+
+	for _, key := range keys {
+		delete(s.keys, docKey{namespace: s.namespaces[key], id: s.ids[key]})
+		delete(s.ids, key)
+		delete(s.namespaces, key)
+		delete(s.content, key)
+		delete(s.meta, key)
+	}
+
+	index := s.newGraph()
+	for _, key := range slices.Sorted(maps.Keys(s.content)) {
+		if vec, ok := s.index.Lookup(key); ok {
+			index.Add(hnsw.MakeNode(key, vec))
+		}
+	}
+	s.index = index
+	s.changes++
+}
+
+// newGraph returns an empty HNSW graph configured for the store.
+func (s *Store) newGraph() *hnsw.Graph[uint32] {
+	index := hnsw.NewGraph[uint32]()
+
+	// Configure graph parameters
+	index.M = s.m
+	index.Ml = 0.5 // Default parameter in the library
+
+	// Set the distance function to cosine distance
+	index.Distance = hnsw.CosineDistance
+
+	// Set efSearch parameter
+	index.EfSearch = s.efSearch
+
+	return index
 }
 
 // getOptions applies given options to default Options and returns it
@@ -211,30 +577,21 @@ func (s *Store) deduplicate(
 	return filtered
 }
 
-// applyScoreThreshold applies the score threshold to the given slice of documents.
-func applyScoreThreshold(docs []schema.Document, threshold float32) ([]schema.Document, error) {
-	if threshold < 0 || threshold > 1 {
-		return nil, ErrInvalidScoreThreshold
-	}
-
-	filtered := make([]schema.Document, 0, len(docs))
-	for _, doc := range docs {
-		if doc.Score >= threshold {
-			filtered = append(filtered, doc)
+// metadataMatcher returns a function reporting whether metadata matches the
+// filters, which are a map[string]any of values or a vectorstores.Filter.
+func metadataMatcher(filters any) (func(map[string]any) bool, error) {
+	if f, ok := vectorstores.AsFilter(filters); ok {
+		if err := f.Validate(); err != nil {
+			return nil, err
 		}
+		return f.Match, nil
 	}
-	return filtered, nil
-}
-
-// applyFilters applies the filters to the given slice of documents.
-func applyFilters(docs []schema.Document, filters map[string]any) []schema.Document {
-	filtered := make([]schema.Document, 0, len(docs))
-	for _, doc := range docs {
-		if matchesFilters(doc.Metadata, filters) {
-			filtered = append(filtered, doc)
-		}
+	if f, ok := filters.(map[string]any); ok {
+		return func(meta map[string]any) bool {
+			return matchesFilters(meta, f)
+		}, nil
 	}
-	return filtered
+	return nil, ErrInvalidFilters
 }
 
 // matchesFilters returns true if the given metadata matches the filters.
@@ -2,6 +2,7 @@ package inmemory_test
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
@@ -507,3 +508,288 @@ func TestDeduplicater(t *testing.T) {
 	require.Equal(t, "potato", docs[0].PageContent)
 	require.Equal(t, "vegetable", docs[0].Metadata["type"])
 }
+
+func TestDeleteAndUpsert(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store, err := inmemory.New(ctx, inmemory.WithEmbedder(&mockEmbedder{}), inmemory.WithVectorSize(3))
+	require.NoError(t, err)
+
+	var _ vectorstores.Deleter = store
+	var _ vectorstores.FilterDeleter = store
+	var _ vectorstores.Upserter = store
+
+	ids, err := store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "similar1", Metadata: map[string]any{"source": "a.go"}},
+		{PageContent: "similar2", Metadata: map[string]any{"source": "b.go"}},
+		{PageContent: "different", Metadata: map[string]any{"source": "b.go"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 3)
+
+	// deleting the best match leaves the other ones searchable
+	require.NoError(t, store.Delete(ctx, []string{ids[0], "unknown"}))
+	docs, err := store.SimilaritySearch(ctx, "similar", 3)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"similar2", "different"}, pageContents(docs))
+
+	require.NoError(t, store.DeleteByFilter(ctx, map[string]any{"source": "b.go"}))
+	docs, err = store.SimilaritySearch(ctx, "similar", 3)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+
+	require.ErrorIs(t, store.DeleteByFilter(ctx, map[string]any{}), vectorstores.ErrEmptyFilter)
+	require.ErrorIs(t, store.DeleteByFilter(ctx, "source"), inmemory.ErrInvalidFilters)
+
+	// upserting the same ids replaces the documents
+	upserted, err := store.UpsertDocuments(ctx, []string{"a.go#0", "a.go#1"}, []schema.Document{
+		{PageContent: "similar1", Metadata: map[string]any{"version": 1}},
+		{PageContent: "different", Metadata: map[string]any{"version": 1}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a.go#0", "a.go#1"}, upserted)
+
+	_, err = store.UpsertDocuments(ctx, []string{"a.go#0"}, []schema.Document{
+		{PageContent: "similar2", Metadata: map[string]any{"version": 2}},
+	})
+	require.NoError(t, err)
+
+	docs, err = store.SimilaritySearch(ctx, "similar", 3)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"similar2", "different"}, pageContents(docs))
+	for _, doc := range docs {
+		if doc.PageContent == "similar2" {
+			require.Equal(t, 2, doc.Metadata["version"])
+		}
+	}
+
+	_, err = store.UpsertDocuments(ctx, []string{"a.go#0"}, nil)
+	require.ErrorIs(t, err, vectorstores.ErrMismatchedIDs)
+
+	require.NoError(t, store.Delete(ctx, []string{"a.go#0", "a.go#1"}))
+	docs, err = store.SimilaritySearch(ctx, "similar", 3)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+}
+
+func TestSimilaritySearchWithFilter(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store, err := inmemory.New(ctx, inmemory.WithEmbedder(&mockEmbedder{}), inmemory.WithVectorSize(3))
+	require.NoError(t, err)
+
+	_, err = store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "similar1", Metadata: map[string]any{"year": 2019, "tags": []any{"go"}}},
+		{PageContent: "similar2", Metadata: map[string]any{"year": 2022, "tags": []any{"go", "llm"}}},
+		{PageContent: "different", Metadata: map[string]any{"year": 2024}},
+	})
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(ctx, "similar", 3,
+		vectorstores.WithFilters(vectorstores.Or(
+			vectorstores.Contains("tags", "llm"),
+			vectorstores.Gt("year", 2023),
+		)))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"similar2", "different"}, pageContents(docs))
+
+	_, err = store.SimilaritySearch(ctx, "similar", 3, vectorstores.WithFilters(vectorstores.In("year")))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidFilter)
+
+	require.NoError(t, store.DeleteByFilter(ctx, vectorstores.Not(vectorstores.Exists("tags"))))
+	docs, err = store.SimilaritySearch(ctx, "similar", 3)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"similar1", "similar2"}, pageContents(docs))
+}
+
+// rankEmbedder embeds "doc N" further away from the query as N grows.
+type rankEmbedder struct{}
+
+func (rankEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = rankEmbedder{}.EmbedQuery(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (rankEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	var n int
+	_, _ = fmt.Sscanf(text, "doc %d", &n)
+	return []float32{1, float32(n) / 100, 0}, nil
+}
+
+func TestSimilaritySearchSelectiveFilter(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store, err := inmemory.New(ctx, inmemory.WithEmbedder(rankEmbedder{}), inmemory.WithVectorSize(3))
+	require.NoError(t, err)
+
+	docs := make([]schema.Document, 0, 500)
+	for i := range 500 {
+		docs = append(docs, schema.Document{
+			PageContent: fmt.Sprintf("doc %d", i),
+			Metadata:    map[string]any{"n": i},
+		})
+	}
+	_, err = store.AddDocuments(ctx, docs)
+	require.NoError(t, err)
+
+	// the matching documents are the furthest from the query
+	results, err := store.SimilaritySearch(ctx, "doc 0", 3, vectorstores.WithFilters(vectorstores.Gte("n", 490)))
+	require.NoError(t, err)
+	require.Equal(t, []string{"doc 490", "doc 491", "doc 492"}, pageContents(results))
+
+	results, err = store.SimilaritySearch(ctx, "doc 0", 5, vectorstores.WithFilters(vectorstores.Gte("n", 497)))
+	require.NoError(t, err)
+	require.Equal(t, []string{"doc 497", "doc 498", "doc 499"}, pageContents(results))
+
+	results, err = store.SimilaritySearch(ctx, "doc 0", 3, vectorstores.WithFilters(map[string]any{"n": 250}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"doc 250"}, pageContents(results))
+
+	results, err = store.SimilaritySearch(ctx, "doc 0", 3)
+	require.NoError(t, err)
+	require.Equal(t, []string{"doc 0", "doc 1", "doc 2"}, pageContents(results))
+	for i := 1; i < len(results); i++ {
+		require.Greater(t, results[i-1].Score, results[i].Score)
+	}
+}
+
+func TestNameSpaces(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store, err := inmemory.New(ctx, inmemory.WithEmbedder(&mockEmbedder{}), inmemory.WithVectorSize(3))
+	require.NoError(t, err)
+
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "similar1"}})
+	require.NoError(t, err)
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "similar2"}}, vectorstores.WithNameSpace("a"))
+	require.NoError(t, err)
+	_, err = store.UpsertDocuments(ctx, []string{"1"}, []schema.Document{{PageContent: "different"}},
+		vectorstores.WithNameSpace("b"))
+	require.NoError(t, err)
+
+	search := func(options ...vectorstores.Option) []string {
+		docs, err := store.SimilaritySearch(ctx, "similar", 3, options...)
+		require.NoError(t, err)
+		return pageContents(docs)
+	}
+	require.Equal(t, []string{"similar1"}, search())
+	require.Equal(t, []string{"similar2"}, search(vectorstores.WithNameSpace("a")))
+	require.Equal(t, []string{"different"}, search(vectorstores.WithNameSpace("b")))
+
+	// ids are scoped to their name space
+	require.NoError(t, store.Delete(ctx, []string{"1"}, vectorstores.WithNameSpace("a")))
+	require.Equal(t, []string{"similar1"}, search())
+	require.Equal(t, []string{"different"}, search(vectorstores.WithNameSpace("b")))
+
+	require.NoError(t, store.Delete(ctx, []string{"1"}, vectorstores.WithNameSpace("b")))
+	require.Empty(t, search(vectorstores.WithNameSpace("b")))
+	require.Equal(t, []string{"similar1"}, search())
+}
+
+// vectorEmbedder embeds the texts it knows with their vector.
+type vectorEmbedder map[string][]float32
+
+func (e vectorEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = e.EmbedQuery(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (e vectorEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return e[text], nil
+}
+
+func TestMaxMarginalRelevanceSearch(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	embedder := vectorEmbedder{
+		"tokyo":       {1, 0.25, 0},
+		"tokyo again": {1, 0.2, 0},
+		"kyoto":       {0.6, 1, 0},
+		"potato":      {-0.2, 1, 0},
+		"city":        {1, 0.3, 0},
+	}
+	store, err := inmemory.New(ctx, inmemory.WithEmbedder(embedder), inmemory.WithVectorSize(3))
+	require.NoError(t, err)
+	_, err = store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "tokyo", Metadata: map[string]any{"city": true}},
+		{PageContent: "tokyo again", Metadata: map[string]any{"city": true}},
+		{PageContent: "kyoto", Metadata: map[string]any{"city": true}},
+		{PageContent: "potato"},
+	})
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(ctx, "city", 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"tokyo", "tokyo again"}, pageContents(docs))
+
+	docs, err = vectorstores.MaxMarginalRelevanceSearch(ctx, store, "city", 2, 3, 0.5)
+	require.NoError(t, err)
+	require.Equal(t, []string{"tokyo", "kyoto"}, pageContents(docs))
+
+	// potato is only selected when it is fetched.
+	docs, err = vectorstores.MaxMarginalRelevanceSearch(ctx, store, "city", 2, 4, 0.5)
+	require.NoError(t, err)
+	require.Equal(t, []string{"tokyo", "potato"}, pageContents(docs))
+
+	docs, err = vectorstores.MaxMarginalRelevanceSearch(ctx, store, "city", 2, 4, 0.5,
+		vectorstores.WithFilters(vectorstores.Exists("city")))
+	require.NoError(t, err)
+	require.Equal(t, []string{"tokyo", "kyoto"}, pageContents(docs))
+
+	docs, err = vectorstores.ToMMRRetriever(store, 2, 3, 0.5).GetRelevantDocuments(ctx, "city")
+	require.NoError(t, err)
+	require.Equal(t, []string{"tokyo", "kyoto"}, pageContents(docs))
+
+	_, err = store.MaxMarginalRelevanceSearch(ctx, "city", 2, 3, -1)
+	require.ErrorIs(t, err, vectorstores.ErrInvalidLambda)
+}
+
+func pageContents(docs []schema.Document) []string {
+	contents := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		contents = append(contents, doc.PageContent)
+	}
+	return contents
+}
+
+func TestVectorSize(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	embedder := vectorEmbedder{"a": {1, 0, 0}, "b": {0, 1, 0}, "short": {1, 0}}
+
+	// Without WithVectorSize, the first vector sets the size.
+	store, err := inmemory.New(ctx, inmemory.WithEmbedder(embedder))
+	require.NoError(t, err)
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "a"}})
+	require.NoError(t, err)
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "b"}, {PageContent: "short"}})
+	require.ErrorIs(t, err, inmemory.ErrVectorSizeMismatch)
+
+	// A refused upsert doesn't remove the documents it would replace.
+	ids, err := store.UpsertDocuments(ctx, []string{"x"}, []schema.Document{{PageContent: "b"}})
+	require.NoError(t, err)
+	_, err = store.UpsertDocuments(ctx, ids, []schema.Document{{PageContent: "short"}})
+	require.ErrorIs(t, err, inmemory.ErrVectorSizeMismatch)
+	docs, err := store.SimilaritySearch(ctx, "b", 3)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "a"}, pageContents(docs))
+
+	store, err = inmemory.New(ctx, inmemory.WithEmbedder(embedder), inmemory.WithVectorSize(2))
+	require.NoError(t, err)
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "a"}})
+	require.ErrorIs(t, err, inmemory.ErrVectorSizeMismatch)
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "short"}})
+	require.NoError(t, err)
+}
@@ -1,14 +1,19 @@
 package inmemory
 
-import "github.com/sayerxofficial/langchaingo/embeddings"
+import (
+	"time"
+
+	"github.com/sayerxofficial/langchaingo/embeddings"
+)
 
 const (
 	defaultM              = 16
 	defaultEfConstruction = 64
 	defaultEfSearch       = 64
 
-	defaultVectorSize = 128
-	defaultSize       = 128
+	defaultSize = 128
+
+	defaultAutosaveInterval = time.Minute
 )
 
 // Option is a function type that can be used to modify the client.
@@ -18,7 +23,6 @@ type Option func(p *Store)
 // m: he max number of connections per layer (16 by default).
 // efConstruction: the size of the dynamic candidate list for constructing the graph (64 by default).
 // efSearch: the size of the dynamic candidate list for search (64 by default).
-// vectorSize: the size of the vector (128 by default).
 func WithHNSWOptions(m, efConstruction, efSearch int) Option {
 	return func(s *Store) {
 		s.m = m
@@ -34,7 +38,9 @@ func WithSize(limit int) Option {
 	}
 }
 
-// WithVectorSize is an option for setting the size of the vector (128 by default).
+// WithVectorSize is an option for setting the size of the vectors. By
+// default it is the size of the first vector added to the store. Vectors of
+// another size are refused with ErrVectorSizeMismatch.
 func WithVectorSize(size int) Option {
 	return func(s *Store) {
 		s.vectorSize = size
@@ -48,6 +54,29 @@ func WithEmbedder(e embeddings.Embedder) Option {
 	}
 }
 
+// WithEmbedderName is an option for naming the embedder, for example after
+// its model. Load refuses a snapshot saved with another embedder name, since
+// its vectors can't be compared with the ones of the embedder.
+func WithEmbedderName(name string) Option {
+	return func(s *Store) {
+		s.embedderName = name
+	}
+}
+
+// WithAutosave is an option for saving the store to the file at path every
+// interval (every minute if interval is not positive), when it changed since
+// it was last saved. Close stops the autosave
+// and saves the latest changes. The file can be restored with LoadFile.
+func WithAutosave(path string, interval time.Duration) Option {
+	return func(s *Store) {
+		s.autosavePath = path
+		s.autosaveInterval = interval
+		if interval <= 0 {
+			s.autosaveInterval = defaultAutosaveInterval
+		}
+	}
+}
+
 func applyOptions(opts []Option) *Store {
 	s := &Store{
 		lastID:   0,
@@ -59,8 +88,7 @@ func applyOptions(opts []Option) *Store {
 		efConstruction: defaultEfConstruction,
 		efSearch:       defaultEfSearch,
 
-		vectorSize: defaultVectorSize,
-		sizeLimit:  defaultSize,
+		sizeLimit: defaultSize,
 	}
 
 	for _, opt := range opts {
@@ -0,0 +1,233 @@
+package inmemory
+
+import (
+	"bufio"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var (
+	// ErrInvalidSnapshot is returned by Load when the data is not a snapshot
+	// written by Save, or was written by an incompatible version.
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+	// ErrSnapshotMismatch is returned by Load when the snapshot holds vectors
+	// of another size than the one set with WithVectorSize, or was saved by a
+	// store with another embedder name.
+	ErrSnapshotMismatch = errors.New("snapshot does not match the store options")
+)
+
+// snapshotVersion is the version of the snapshot format written by Save.
+const snapshotVersion = 1
+
+// snapshot is the part of a saved store that precedes its HNSW graph.
+type snapshot struct {
+	Version    int
+	VectorSize int
+	Embedder   string
+	LastID     uint32
+	Content    map[uint32]string
+	Meta       map[uint32]map[string]any
+	IDs        map[uint32]string
+	Namespaces map[uint32]string
+}
+
+func init() { //nolint:gochecknoinits
+	// Types of the metadata values that documents commonly have, in
+	// addition to the basic types that gob registers.
+	gob.Register(map[string]any{})
+	gob.Register([]any{})
+	gob.Register([]string{})
+}
+
+// Save writes a snapshot of the store to w: its documents, their vectors, the
+// HNSW graph and the state needed to keep assigning new ids, so that Load can
+// restore the store without embedding the documents again. Metadata values
+// must be encodable with encoding/gob, and types other than the basic ones,
+// []any, []string and map[string]any must be registered with gob.Register.
+func (s *Store) Save(w io.Writer) error {
+	s.RLock()
+	defer s.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	err := gob.NewEncoder(bw).Encode(snapshot{
+		Version:    snapshotVersion,
+		VectorSize: s.vectorSize,
+		Embedder:   s.embedderName,
+		LastID:     s.lastID,
+		Content:    s.content,
+		Meta:       s.meta,
+		IDs:        s.ids,
+		Namespaces: s.namespaces,
+	})
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+	if err := s.index.Export(bw); err != nil {
+		return fmt.Errorf("export graph: %w", err)
+	}
+	return bw.Flush()
+}
+
+// Load returns a store restored from a snapshot written by Save, configured
+// with opts like New. The options must set the same embedder name as the one
+// of the saved store and, if they set a vector size, the size of its vectors,
+// or Load returns an error wrapping ErrSnapshotMismatch.
+func Load(r io.Reader, opts ...Option) (*Store, error) {
+	store := applyOptions(opts)
+
+	// The snapshot and the graph are read from the same buffered reader,
+	// which gob uses as is since it is an io.ByteReader.
+	br := bufio.NewReader(r)
+	var snap snapshot
+	if err := gob.NewDecoder(br).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+	}
+	if snap.Version != snapshotVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, snap.Version)
+	}
+	if snap.VectorSize != 0 && store.vectorSize != 0 && snap.VectorSize != store.vectorSize {
+		return nil, fmt.Errorf("%w: vector size is %d, store has %d",
+			ErrSnapshotMismatch, snap.VectorSize, store.vectorSize)
+	}
+	if snap.Embedder != store.embedderName {
+		return nil, fmt.Errorf("%w: embedder is %q, store has %q",
+			ErrSnapshotMismatch, snap.Embedder, store.embedderName)
+	}
+
+	store.index = store.newGraph()
+	if err := store.index.Import(br); err != nil {
+		return nil, fmt.Errorf("%w: import graph: %w", ErrInvalidSnapshot, err)
+	}
+	// Import restores the parameters of the saved graph, use the ones of the
+	// options instead.
+	store.index.M = store.m
+	store.index.EfSearch = store.efSearch
+
+	store.lastID = snap.LastID
+	store.content = nonNil(snap.Content)
+	store.meta = nonNil(snap.Meta)
+	store.ids = nonNil(snap.IDs)
+	store.namespaces = nonNil(snap.Namespaces)
+	store.keys = make(map[docKey]uint32, len(store.ids))
+	for key, id := range store.ids {
+		store.keys[docKey{namespace: store.namespaces[key], id: id}] = key
+	}
+	if store.index.Len() != len(store.content) {
+		return nil, fmt.Errorf("%w: graph has %d nodes for %d documents",
+			ErrInvalidSnapshot, store.index.Len(), len(store.content))
+	}
+	for key := range store.content {
+		vec, ok := store.index.Lookup(key)
+		if !ok || len(vec) != snap.VectorSize {
+			return nil, fmt.Errorf("%w: document %d has no vector of size %d",
+				ErrInvalidSnapshot, key, snap.VectorSize)
+		}
+	}
+	if snap.VectorSize != 0 {
+		store.vectorSize = snap.VectorSize
+	}
+
+	store.startAutosave()
+	return store, nil
+}
+
+// LoadFile returns a store restored from a snapshot file, such as the one
+// written by WithAutosave. See Load.
+func LoadFile(path string, opts ...Option) (*Store, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f, opts...)
+}
+
+// Close stops the autosave enabled with WithAutosave, and saves the store a
+// last time if it changed since the previous save. It does nothing for other
+// stores.
+func (s *Store) Close() error {
+	if s.autosavePath == "" {
+		return nil
+	}
+	s.closeOnce.Do(func() {
+		close(s.autosaveStop)
+		<-s.autosaveDone
+	})
+	return s.saveIfChanged()
+}
+
+// startAutosave starts saving the store periodically if WithAutosave is set.
+func (s *Store) startAutosave() {
+	if s.autosavePath == "" {
+		return
+	}
+	s.autosaveStop = make(chan struct{})
+	s.autosaveDone = make(chan struct{})
+
+	go func() {
+		defer close(s.autosaveDone)
+
+		ticker := time.NewTicker(s.autosaveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.autosaveStop:
+				return
+			case <-ticker.C:
+				if err := s.saveIfChanged(); err != nil {
+					slog.Warn("autosave of the in-memory store failed", "path", s.autosavePath, "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// saveIfChanged saves the store to the autosave file if it changed since it
+// was last saved there. The saves of the autosave and of Close don't overlap.
+func (s *Store) saveIfChanged() error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.RLock()
+	changes := s.changes
+	s.RUnlock()
+	if changes == s.savedChanges {
+		return nil
+	}
+	if err := s.saveFile(s.autosavePath); err != nil {
+		return err
+	}
+	s.savedChanges = changes
+	return nil
+}
+
+// saveFile saves the store to path, replacing the file only once the
+// snapshot is complete.
+func (s *Store) saveFile(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".inmemory-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := s.Save(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func nonNil[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return make(map[K]V)
+	}
+	return m
+}
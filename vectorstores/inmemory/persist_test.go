@@ -0,0 +1,133 @@
+package inmemory_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+	"github.com/sayerxofficial/langchaingo/vectorstores/inmemory"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	opts := []inmemory.Option{
+		inmemory.WithEmbedder(&mockEmbedder{}),
+		inmemory.WithVectorSize(3),
+		inmemory.WithEmbedderName("mock"),
+	}
+	store, err := inmemory.New(ctx, opts...)
+	require.NoError(t, err)
+
+	ids, err := store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "similar1", Metadata: map[string]any{
+			"source": "a.md", "tags": []any{"go"}, "authors": []string{"ann", "bob"},
+		}},
+		{PageContent: "different", Metadata: map[string]any{"source": "b.md", "year": 2024}},
+	})
+	require.NoError(t, err)
+	_, err = store.UpsertDocuments(ctx, []string{"c.md#0"}, []schema.Document{{PageContent: "similar2"}},
+		vectorstores.WithNameSpace("c"))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, store.Save(&buf))
+
+	loaded, err := inmemory.Load(bytes.NewReader(buf.Bytes()), opts...)
+	require.NoError(t, err)
+
+	docs, err := loaded.SimilaritySearch(ctx, "similar", 3)
+	require.NoError(t, err)
+	require.Equal(t, []string{"similar1", "different"}, pageContents(docs))
+	require.Equal(t, map[string]any{
+		"source": "a.md", "tags": []any{"go"}, "authors": []string{"ann", "bob"},
+	}, docs[0].Metadata)
+	require.Equal(t, 2024, docs[1].Metadata["year"])
+
+	// ids and name spaces survive, and new ids don't collide with saved ones
+	require.NoError(t, loaded.Delete(ctx, ids[:1]))
+	require.NoError(t, loaded.Delete(ctx, []string{"c.md#0"}, vectorstores.WithNameSpace("c")))
+	newIDs, err := loaded.AddDocuments(ctx, []schema.Document{{PageContent: "similar2"}})
+	require.NoError(t, err)
+	require.NotContains(t, ids, newIDs[0])
+	docs, err = loaded.SimilaritySearch(ctx, "similar", 3)
+	require.NoError(t, err)
+	require.Equal(t, []string{"similar2", "different"}, pageContents(docs))
+	docs, err = loaded.SimilaritySearch(ctx, "similar", 3, vectorstores.WithNameSpace("c"))
+	require.NoError(t, err)
+	require.Empty(t, docs)
+
+	_, err = inmemory.Load(bytes.NewReader(buf.Bytes()), inmemory.WithVectorSize(4), inmemory.WithEmbedderName("mock"))
+	require.ErrorIs(t, err, inmemory.ErrSnapshotMismatch)
+	_, err = inmemory.Load(bytes.NewReader(buf.Bytes()), inmemory.WithVectorSize(3), inmemory.WithEmbedderName("other"))
+	require.ErrorIs(t, err, inmemory.ErrSnapshotMismatch)
+
+	// Without WithVectorSize, the store keeps the size of the saved vectors.
+	loaded, err = inmemory.Load(bytes.NewReader(buf.Bytes()),
+		inmemory.WithEmbedder(vectorEmbedder{"short": {1, 0}}), inmemory.WithEmbedderName("mock"))
+	require.NoError(t, err)
+	_, err = loaded.AddDocuments(ctx, []schema.Document{{PageContent: "short"}})
+	require.ErrorIs(t, err, inmemory.ErrVectorSizeMismatch)
+	_, err = inmemory.Load(bytes.NewReader([]byte("not a snapshot")), opts...)
+	require.ErrorIs(t, err, inmemory.ErrInvalidSnapshot)
+}
+
+func TestSaveAndLoadEmpty(t *testing.T) {
+	t.Parallel()
+
+	store, err := inmemory.New(t.Context(), inmemory.WithEmbedder(&mockEmbedder{}))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, store.Save(&buf))
+
+	loaded, err := inmemory.Load(&buf, inmemory.WithEmbedder(&mockEmbedder{}))
+	require.NoError(t, err)
+	docs, err := loaded.SimilaritySearch(t.Context(), "similar", 3)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+}
+
+func TestAutosave(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), "store.bin")
+	opts := []inmemory.Option{
+		inmemory.WithEmbedder(&mockEmbedder{}),
+		inmemory.WithVectorSize(3),
+		inmemory.WithAutosave(path, 10*time.Millisecond),
+	}
+	store, err := inmemory.New(ctx, opts...)
+	require.NoError(t, err)
+
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "similar1"}})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		loaded, err := inmemory.LoadFile(path, inmemory.WithEmbedder(&mockEmbedder{}), inmemory.WithVectorSize(3))
+		if err != nil {
+			return false
+		}
+		docs, err := loaded.SimilaritySearch(ctx, "similar", 3)
+		return err == nil && len(docs) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Close saves the changes made since the last autosave
+	_, err = store.AddDocuments(ctx, []schema.Document{{PageContent: "similar2"}})
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+	require.NoError(t, store.Close())
+
+	loaded, err := inmemory.LoadFile(path, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, loaded.Close()) })
+	docs, err := loaded.SimilaritySearch(ctx, "similar", 3)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"similar1", "similar2"}, pageContents(docs))
+}
@@ -0,0 +1,72 @@
+package milvus
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+)
+
+// filterExpr translates f into a boolean expression on the metadata field.
+// Milvus doesn't match documents without the key with Ne or Nin, and has no
+// equivalent for Exists.
+//
+//nolint:cyclop
+func (s Store) filterExpr(f vectorstores.Filter) (string, error) {
+	switch f.Operator {
+	case vectorstores.FilterAnd, vectorstores.FilterOr, vectorstores.FilterNot:
+		exprs := make([]string, 0, len(f.Filters))
+		for _, operand := range f.Filters {
+			expr, err := s.filterExpr(operand)
+			if err != nil {
+				return "", err
+			}
+			exprs = append(exprs, expr)
+		}
+		switch f.Operator { //nolint:exhaustive
+		case vectorstores.FilterAnd:
+			return "(" + strings.Join(exprs, " && ") + ")", nil
+		case vectorstores.FilterOr:
+			return "(" + strings.Join(exprs, " || ") + ")", nil
+		default:
+			return "(not " + exprs[0] + ")", nil
+		}
+	case vectorstores.FilterExists:
+		return "", &vectorstores.UnsupportedFilterError{Store: "milvus", Operator: f.Operator}
+	}
+
+	key, err := json.Marshal(f.Key)
+	if err != nil {
+		return "", err
+	}
+	field := fmt.Sprintf("%s[%s]", s.metaField, key)
+
+	value, err := json.Marshal(f.Value)
+	if err != nil {
+		return "", err
+	}
+
+	switch f.Operator { //nolint:exhaustive
+	case vectorstores.FilterEq:
+		return fmt.Sprintf("%s == %s", field, value), nil
+	case vectorstores.FilterNe:
+		return fmt.Sprintf("%s != %s", field, value), nil
+	case vectorstores.FilterIn:
+		return fmt.Sprintf("%s in %s", field, value), nil
+	case vectorstores.FilterNin:
+		return fmt.Sprintf("%s not in %s", field, value), nil
+	case vectorstores.FilterGt:
+		return fmt.Sprintf("%s > %s", field, value), nil
+	case vectorstores.FilterGte:
+		return fmt.Sprintf("%s >= %s", field, value), nil
+	case vectorstores.FilterLt:
+		return fmt.Sprintf("%s < %s", field, value), nil
+	case vectorstores.FilterLte:
+		return fmt.Sprintf("%s <= %s", field, value), nil
+	case vectorstores.FilterContains:
+		return fmt.Sprintf("json_contains(%s, %s)", field, value), nil
+	default:
+		return "", &vectorstores.UnsupportedFilterError{Store: "milvus", Operator: f.Operator}
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"strconv"
+	"strings"
 
 	"github.com/sayerxofficial/langchaingo/embeddings"
 	"github.com/sayerxofficial/langchaingo/schema"
@@ -40,8 +42,15 @@ type Store struct {
 	skipFlushOnWrite bool
 }
 
+// idKey is the metadata key holding the ids given to UpsertDocuments, since
+// the primary key of the collection is generated by Milvus.
+const idKey = "_id"
+
 var (
-	_ vectorstores.VectorStore = Store{}
+	_ vectorstores.VectorStore   = Store{}
+	_ vectorstores.Deleter       = Store{}
+	_ vectorstores.FilterDeleter = Store{}
+	_ vectorstores.Upserter      = Store{}
 
 	ErrEmbedderWrongNumberVectors = errors.New(
 		"number of vectors from embedder does not match number of documents",
@@ -197,6 +206,100 @@ func (s *Store) load(ctx context.Context) error {
 func (s Store) AddDocuments(ctx context.Context, docs []schema.Document,
 	_ ...vectorstores.Option,
 ) ([]string, error) {
+	metadatas := make([]map[string]any, len(docs))
+	for i, doc := range docs {
+		metadatas[i] = doc.Metadata
+	}
+	return s.insertDocuments(ctx, docs, metadatas)
+}
+
+// UpsertDocuments adds the documents to the Milvus collection associated with 'Store' under the
+// given ids, replacing the documents already stored with the same ids. The ids are kept in the
+// "_id" metadata key, since the primary key of the collection is generated by Milvus, and the key
+// is removed from the metadata of the documents returned by searches. The documents stored before
+// are deleted once the new ones are inserted, so a failure never loses a document, but searches
+// running in between can return both.
+func (s Store) UpsertDocuments(ctx context.Context, ids []string, docs []schema.Document,
+	_ ...vectorstores.Option,
+) ([]string, error) {
+	if len(ids) != len(docs) {
+		return nil, vectorstores.ErrMismatchedIDs
+	}
+	if len(docs) == 0 {
+		return []string{}, nil
+	}
+
+	metadatas := make([]map[string]any, len(docs))
+	for i, doc := range docs {
+		metadata := make(map[string]any, len(doc.Metadata)+1)
+		maps.Copy(metadata, doc.Metadata)
+		metadata[idKey] = ids[i]
+		metadatas[i] = metadata
+	}
+	pks, err := s.insertDocuments(ctx, docs, metadatas)
+	if err != nil {
+		return nil, err
+	}
+
+	expr, err := s.idExpr(ids)
+	if err != nil {
+		return nil, err
+	}
+	pkExpr, err := s.primaryKeyExpr(pks)
+	if err != nil {
+		return nil, err
+	}
+	// Without the primary keys of the new documents, the old ones cannot be
+	// told apart from them, so they are kept.
+	if pkExpr != "" {
+		expr = fmt.Sprintf("%s && not (%s)", expr, pkExpr)
+		if err := s.client.Delete(ctx, s.collectionName, s.partitionName, expr); err != nil {
+			return nil, err
+		}
+	}
+	return append([]string(nil), ids...), nil
+}
+
+// Delete deletes the documents with the given ids from the Milvus collection associated with 'Store'.
+// The ids are either primary keys returned by AddDocuments or ids given to UpsertDocuments.
+func (s Store) Delete(ctx context.Context, ids []string, _ ...vectorstores.Option) error {
+	if len(ids) == 0 || !s.collectionExists {
+		return nil
+	}
+
+	idExpr, err := s.idExpr(ids)
+	if err != nil {
+		return err
+	}
+	pkExpr, err := s.primaryKeyExpr(ids)
+	if err != nil {
+		return err
+	}
+	exprs := []string{idExpr}
+	if pkExpr != "" {
+		exprs = append(exprs, pkExpr)
+	}
+
+	return s.client.Delete(ctx, s.collectionName, s.partitionName, strings.Join(exprs, " || "))
+}
+
+// DeleteByFilter deletes the documents matching the filter, a boolean expression or a vectorstores.Filter
+// like the ones given to WithFilters, from the Milvus collection associated with 'Store'.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, _ ...vectorstores.Option) error {
+	expr, err := s.getFilters(vectorstores.Options{Filters: filter})
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(expr) == "" {
+		return vectorstores.ErrEmptyFilter
+	}
+	if !s.collectionExists {
+		return nil
+	}
+	return s.client.Delete(ctx, s.collectionName, s.partitionName, expr)
+}
+
+func (s Store) insertDocuments(ctx context.Context, docs []schema.Document, metadatas []map[string]any) ([]string, error) {
 	texts := make([]string, 0, len(docs))
 	for _, doc := range docs {
 		texts = append(texts, doc.PageContent)
@@ -217,14 +320,14 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document,
 	colsData := make([]interface{}, 0, len(docs))
 	for i, doc := range docs {
 		docMap := map[string]any{
-			s.metaField:   doc.Metadata,
+			s.metaField:   metadatas[i],
 			s.textField:   doc.PageContent,
 			s.vectorField: vectors[i],
 		}
 		colsData = append(colsData, docMap)
 	}
 
-	_, err = s.client.InsertRows(ctx, s.collectionName, s.partitionName, colsData)
+	pks, err := s.client.InsertRows(ctx, s.collectionName, s.partitionName, colsData)
 	if err != nil {
 		return nil, err
 	}
@@ -233,7 +336,63 @@ func (s Store) AddDocuments(ctx context.Context, docs []schema.Document,
 			return nil, err
 		}
 	}
-	return nil, nil
+	if pks == nil {
+		return nil, nil
+	}
+
+	ids := make([]string, pks.Len())
+	for i := range ids {
+		pk, err := pks.Get(i)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = fmt.Sprint(pk)
+	}
+	return ids, nil
+}
+
+// idExpr returns an expression matching the documents upserted with the given ids.
+func (s Store) idExpr(ids []string) (string, error) {
+	values, err := json.Marshal(ids)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`%s["%s"] in %s`, s.metaField, idKey, values), nil
+}
+
+// primaryKeyExpr returns an expression matching the documents whose primary key is one of
+// the ids, or an empty string if none of the ids can be a primary key.
+func (s Store) primaryKeyExpr(ids []string) (string, error) {
+	var pks any = ids
+	if s.primaryKeyType() == entity.FieldTypeInt64 {
+		ints := make([]int64, 0, len(ids))
+		for _, id := range ids {
+			if pk, err := strconv.ParseInt(id, 10, 64); err == nil {
+				ints = append(ints, pk)
+			}
+		}
+		if len(ints) == 0 {
+			return "", nil
+		}
+		pks = ints
+	}
+
+	values, err := json.Marshal(pks)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s in %s", s.primaryField, values), nil
+}
+
+func (s Store) primaryKeyType() entity.FieldType {
+	if s.schema != nil {
+		for _, f := range s.schema.Fields {
+			if f.PrimaryKey {
+				return f.DataType
+			}
+		}
+	}
+	return entity.FieldTypeInt64
 }
 
 func (s *Store) getSearchFields() []string {
@@ -286,6 +445,7 @@ func (s Store) convertResultToDocument(searchResult []client.SearchResult) ([]sc
 			if err := json.Unmarshal(metaStr, &doc.Metadata); err != nil {
 				return nil, err
 			}
+			delete(doc.Metadata, idKey)
 			doc.Score = res.Scores[i]
 			docs = append(docs, doc)
 		}
@@ -338,8 +498,15 @@ func (s Store) SimilaritySearch(ctx context.Context, query string, numDocuments
 	return s.convertResultToDocument(searchResult)
 }
 
-// getFilters return metadata filters.
+// getFilters return metadata filters, as a boolean expression or a
+// vectorstores.Filter translated to one.
 func (s Store) getFilters(opts vectorstores.Options) (string, error) {
+	if f, ok := vectorstores.AsFilter(opts.Filters); ok {
+		if err := f.Validate(); err != nil {
+			return "", err
+		}
+		return s.filterExpr(f)
+	}
 	if opts.Filters != nil {
 		if filters, ok := opts.Filters.(string); ok {
 			return filters, nil
@@ -0,0 +1,135 @@
+package milvus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+
+	"github.com/milvus-io/milvus-sdk-go/v2/client"
+	"github.com/milvus-io/milvus-sdk-go/v2/entity"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient records the rows inserted and the delete expressions.
+type fakeClient struct {
+	client.Client
+
+	rows    []any
+	deletes []string
+}
+
+func (c *fakeClient) InsertRows(_ context.Context, _, _ string, rows []any) (entity.Column, error) {
+	pks := make([]int64, len(rows))
+	for i := range rows {
+		pks[i] = int64(len(c.rows) + i + 1)
+	}
+	c.rows = append(c.rows, rows...)
+	return entity.NewColumnInt64("pk", pks), nil
+}
+
+func (c *fakeClient) Flush(context.Context, string, bool, ...client.FlushOption) error {
+	return nil
+}
+
+func (c *fakeClient) Delete(_ context.Context, _, _ string, expr string) error {
+	c.deletes = append(c.deletes, expr)
+	return nil
+}
+
+type fakeEmbedder struct{}
+
+func (fakeEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i := range texts {
+		vectors[i] = []float32{0.1, 0.2}
+	}
+	return vectors, nil
+}
+
+func (fakeEmbedder) EmbedQuery(context.Context, string) ([]float32, error) {
+	return []float32{0.1, 0.2}, nil
+}
+
+func TestDeleteAndUpsertUnit(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	fc := &fakeClient{}
+	idx, err := entity.NewIndexAUTOINDEX(entity.L2)
+	require.NoError(t, err)
+	store, err := applyClientOptions(WithEmbedder(fakeEmbedder{}), WithIndex(idx))
+	require.NoError(t, err)
+	store.client = fc
+	store.loaded = true
+	store.collectionExists = true
+
+	ids, err := store.AddDocuments(ctx, []schema.Document{{PageContent: "tokyo"}, {PageContent: "kyoto"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", "2"}, ids)
+
+	upserted, err := store.UpsertDocuments(ctx, []string{"a.md#0"}, []schema.Document{
+		{PageContent: "potato", Metadata: map[string]any{"type": "vegetable"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a.md#0"}, upserted)
+	require.Equal(t, map[string]any{"type": "vegetable", "_id": "a.md#0"}, fc.rows[2].(map[string]any)["meta"])
+
+	_, err = store.UpsertDocuments(ctx, []string{"a", "b"}, nil)
+	require.ErrorIs(t, err, vectorstores.ErrMismatchedIDs)
+
+	require.NoError(t, store.Delete(ctx, []string{"1", "a.md#0"}))
+	require.NoError(t, store.DeleteByFilter(ctx, `meta["type"] == "vegetable"`))
+	require.ErrorIs(t, store.DeleteByFilter(ctx, ""), vectorstores.ErrEmptyFilter)
+	require.ErrorIs(t, store.DeleteByFilter(ctx, 1), ErrInvalidFilters)
+
+	require.Equal(t, []string{
+		`meta["_id"] in ["a.md#0"] && not (pk in [3])`,
+		`meta["_id"] in ["1","a.md#0"] || pk in [1]`,
+		`meta["type"] == "vegetable"`,
+	}, fc.deletes)
+}
+
+func TestConvertResultToDocumentStripsID(t *testing.T) {
+	t.Parallel()
+
+	s := Store{textField: "text", metaField: "meta"}
+	docs, err := s.convertResultToDocument([]client.SearchResult{{
+		ResultCount: 1,
+		Scores:      []float32{0.9},
+		Fields: client.ResultSet{
+			entity.NewColumnVarChar("text", []string{"potato"}),
+			entity.NewColumnJSONBytes("meta", [][]byte{[]byte(`{"_id":"a.md#0","type":"vegetable"}`)}),
+		},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, []schema.Document{{
+		PageContent: "potato",
+		Metadata:    map[string]any{"type": "vegetable"},
+		Score:       0.9,
+	}}, docs)
+}
+
+func TestGetFiltersTranslatesFilter(t *testing.T) {
+	t.Parallel()
+
+	s := Store{metaField: "meta"}
+	expr, err := s.getFilters(vectorstores.Options{Filters: vectorstores.And(
+		vectorstores.Eq("source", "a.md"),
+		vectorstores.Or(vectorstores.Gte("year", 2020), vectorstores.Nin("lang", "go", "rust")),
+		vectorstores.Not(vectorstores.Contains("tags", "draft")),
+	)})
+	require.NoError(t, err)
+	require.Equal(t,
+		`(meta["source"] == "a.md" && (meta["year"] >= 2020 || meta["lang"] not in ["go","rust"]) && `+
+			`(not json_contains(meta["tags"], "draft")))`,
+		expr)
+
+	_, err = s.getFilters(vectorstores.Options{Filters: vectorstores.Exists("source")})
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+
+	expr, err = s.getFilters(vectorstores.Options{Filters: `meta["source"] == "a.md"`})
+	require.NoError(t, err)
+	require.Equal(t, `meta["source"] == "a.md"`, expr)
+}
@@ -0,0 +1,149 @@
+package vectorstores
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/sayerxofficial/langchaingo/schema"
+)
+
+var (
+	// ErrMMRNotSupported is returned by MaxMarginalRelevanceSearch for stores
+	// that can neither search with maximal marginal relevance nor return the
+	// embeddings of the documents they find.
+	ErrMMRNotSupported = errors.New("vector store does not support maximal marginal relevance search")
+	// ErrInvalidLambda is returned when the lambda of a maximal marginal
+	// relevance search is not between 0 and 1.
+	ErrInvalidLambda = errors.New("lambda must be between 0 and 1")
+)
+
+// EmbeddingSearchResult is the result of a similarity search that returns
+// the embeddings of the documents found.
+type EmbeddingSearchResult struct {
+	// Query is the embedding of the query.
+	Query []float32
+	// Documents are the documents found, most similar first.
+	Documents []schema.Document
+	// Embeddings are the embeddings of the documents, in the same order.
+	Embeddings [][]float32
+}
+
+// EmbeddingSearcher is implemented by vector stores that can return the
+// embeddings of the documents found by a similarity search. It takes the same
+// options as SimilaritySearch.
+type EmbeddingSearcher interface {
+	SimilaritySearchWithEmbeddings(ctx context.Context, query string, numDocuments int, options ...Option) (EmbeddingSearchResult, error) //nolint:lll
+}
+
+// MaxMarginalRelevanceSearcher is implemented by vector stores that search
+// with maximal marginal relevance natively. See MaxMarginalRelevanceSearch.
+type MaxMarginalRelevanceSearcher interface {
+	MaxMarginalRelevanceSearch(ctx context.Context, query string, k, fetchK int, lambda float32, options ...Option) ([]schema.Document, error) //nolint:lll
+}
+
+// MaxMarginalRelevanceSearch returns k documents relevant to the query that
+// are also diverse, so that near-duplicate chunks don't crowd out the other
+// ones. It fetches the fetchK documents most similar to the query, then
+// selects documents one at a time, maximizing
+//
+//	lambda * similarity(query, doc) - (1 - lambda) * max(similarity(doc, selected))
+//
+// A lambda of 1 ranks by relevance only, and a lambda of 0 by diversity only.
+// Stores implementing MaxMarginalRelevanceSearcher use their own
+// implementation, other stores must implement EmbeddingSearcher or
+// ErrMMRNotSupported is returned. The options are the ones of
+// SimilaritySearch.
+func MaxMarginalRelevanceSearch(
+	ctx context.Context,
+	store VectorStore,
+	query string,
+	k, fetchK int,
+	lambda float32,
+	options ...Option,
+) ([]schema.Document, error) {
+	if lambda < 0 || lambda > 1 {
+		return nil, ErrInvalidLambda
+	}
+	fetchK = max(fetchK, k)
+
+	if searcher, ok := store.(MaxMarginalRelevanceSearcher); ok {
+		return searcher.MaxMarginalRelevanceSearch(ctx, query, k, fetchK, lambda, options...)
+	}
+	searcher, ok := store.(EmbeddingSearcher)
+	if !ok {
+		return nil, ErrMMRNotSupported
+	}
+
+	result, err := searcher.SimilaritySearchWithEmbeddings(ctx, query, fetchK, options...)
+	if err != nil {
+		return nil, err
+	}
+	selected := MaxMarginalRelevance(result.Query, result.Embeddings, k, lambda)
+	docs := make([]schema.Document, 0, len(selected))
+	for _, i := range selected {
+		docs = append(docs, result.Documents[i])
+	}
+	return docs, nil
+}
+
+// MaxMarginalRelevance returns the indexes of the k embeddings selected by
+// maximal marginal relevance for the query, in the order they were selected.
+// Similarities are cosine similarities. See MaxMarginalRelevanceSearch.
+func MaxMarginalRelevance(query []float32, embeddings [][]float32, k int, lambda float32) []int {
+	k = min(k, len(embeddings))
+	if k <= 0 {
+		return []int{}
+	}
+
+	relevance := make([]float64, len(embeddings))
+	for i, embedding := range embeddings {
+		relevance[i] = cosineSimilarity(query, embedding)
+	}
+	// redundancy holds the highest similarity of each embedding with the
+	// selected ones.
+	redundancy := make([]float64, len(embeddings))
+	for i := range redundancy {
+		redundancy[i] = math.Inf(-1)
+	}
+	isSelected := make([]bool, len(embeddings))
+
+	selected := make([]int, 0, k)
+	for len(selected) < k {
+		best, bestScore := -1, math.Inf(-1)
+		for i := range embeddings {
+			if isSelected[i] {
+				continue
+			}
+			score := float64(lambda) * relevance[i]
+			if len(selected) > 0 {
+				score -= float64(1-lambda) * redundancy[i]
+			}
+			if best < 0 || score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		selected = append(selected, best)
+		isSelected[best] = true
+		for i, embedding := range embeddings {
+			if !isSelected[i] {
+				redundancy[i] = max(redundancy[i], cosineSimilarity(embeddings[best], embedding))
+			}
+		}
+	}
+	return selected
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range min(len(a), len(b)) {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
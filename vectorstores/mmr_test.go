@@ -0,0 +1,163 @@
+package vectorstores_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/schema"
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxMarginalRelevance(t *testing.T) {
+	t.Parallel()
+
+	query := []float32{1, 0.3}
+	embeddings := [][]float32{
+		{1, 0.25},
+		{1, 0.2},
+		{0.6, 1},
+		{-0.2, 1},
+	}
+
+	// Relevance only.
+	assert.Equal(t, []int{0, 1, 2}, vectorstores.MaxMarginalRelevance(query, embeddings, 3, 1))
+	// The near duplicate of the first embedding is selected last.
+	assert.Equal(t, []int{0, 3, 2}, vectorstores.MaxMarginalRelevance(query, embeddings, 3, 0.5))
+	// Diversity only, after the most relevant embedding.
+	assert.Equal(t, []int{0, 3}, vectorstores.MaxMarginalRelevance(query, embeddings, 2, 0))
+
+	assert.Equal(t, []int{0, 1, 2, 3}, vectorstores.MaxMarginalRelevance(query, embeddings, 10, 1))
+	assert.Empty(t, vectorstores.MaxMarginalRelevance(query, embeddings, 0, 0.5))
+	assert.Empty(t, vectorstores.MaxMarginalRelevance(query, nil, 3, 0.5))
+}
+
+func TestMaxMarginalRelevanceSearch(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	store := &embeddingStore{docs: []embeddedDocument{
+		{doc: schema.Document{PageContent: "tokyo"}, embedding: []float32{1, 0.25}},
+		{doc: schema.Document{PageContent: "tokyo again"}, embedding: []float32{1, 0.2}},
+		{doc: schema.Document{PageContent: "kyoto"}, embedding: []float32{0.6, 1}},
+		{doc: schema.Document{PageContent: "potato"}, embedding: []float32{-0.2, 1}},
+	}}
+
+	docs, err := vectorstores.MaxMarginalRelevanceSearch(ctx, store, "tokyo", 2, 3, 0.5)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tokyo", "kyoto"}, contents(docs))
+	assert.Equal(t, 3, store.fetched)
+
+	// fetchK is at least k.
+	docs, err = vectorstores.MaxMarginalRelevanceSearch(ctx, store, "tokyo", 4, 1, 1)
+	require.NoError(t, err)
+	assert.Len(t, docs, 4)
+	assert.Equal(t, 4, store.fetched)
+
+	_, err = vectorstores.MaxMarginalRelevanceSearch(ctx, store, "tokyo", 2, 3, 1.5)
+	require.ErrorIs(t, err, vectorstores.ErrInvalidLambda)
+
+	_, err = vectorstores.MaxMarginalRelevanceSearch(ctx, plainStore{}, "tokyo", 2, 3, 0.5)
+	require.ErrorIs(t, err, vectorstores.ErrMMRNotSupported)
+
+	native := &nativeStore{}
+	docs, err = vectorstores.MaxMarginalRelevanceSearch(ctx, native, "tokyo", 2, 5, 0.25)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"native"}, contents(docs))
+	assert.Equal(t, [3]any{2, 5, float32(0.25)}, native.args)
+}
+
+func TestToMMRRetriever(t *testing.T) {
+	t.Parallel()
+
+	store := &embeddingStore{docs: []embeddedDocument{
+		{doc: schema.Document{PageContent: "tokyo"}, embedding: []float32{1, 0.25}},
+		{doc: schema.Document{PageContent: "tokyo again"}, embedding: []float32{1, 0.2}},
+		{doc: schema.Document{PageContent: "kyoto"}, embedding: []float32{0.6, 1}},
+	}}
+
+	docs, err := vectorstores.ToRetriever(store, 2).GetRelevantDocuments(t.Context(), "tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tokyo", "tokyo again"}, contents(docs))
+
+	docs, err = vectorstores.ToMMRRetriever(store, 2, 3, 0.5).GetRelevantDocuments(t.Context(), "tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tokyo", "kyoto"}, contents(docs))
+}
+
+func contents(docs []schema.Document) []string {
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+	return texts
+}
+
+// plainStore is a vector store that only supports plain similarity search.
+type plainStore struct{}
+
+func (plainStore) AddDocuments(context.Context, []schema.Document, ...vectorstores.Option) ([]string, error) {
+	return []string{}, nil
+}
+
+func (plainStore) SimilaritySearch(context.Context, string, int, ...vectorstores.Option) ([]schema.Document, error) {
+	return []schema.Document{}, nil
+}
+
+type embeddedDocument struct {
+	doc       schema.Document
+	embedding []float32
+}
+
+// embeddingStore returns its documents in order, with their embeddings and
+// the embedding [1, 0.3] for every query.
+type embeddingStore struct {
+	plainStore
+	docs    []embeddedDocument
+	fetched int
+}
+
+func (s *embeddingStore) SimilaritySearch(
+	ctx context.Context,
+	query string,
+	numDocuments int,
+	options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	result, err := s.SimilaritySearchWithEmbeddings(ctx, query, numDocuments, options...)
+	return result.Documents, err
+}
+
+func (s *embeddingStore) SimilaritySearchWithEmbeddings(
+	_ context.Context,
+	_ string,
+	numDocuments int,
+	_ ...vectorstores.Option,
+) (vectorstores.EmbeddingSearchResult, error) {
+	s.fetched = numDocuments
+	result := vectorstores.EmbeddingSearchResult{Query: []float32{1, 0.3}}
+	for _, d := range s.docs[:min(numDocuments, len(s.docs))] {
+		result.Documents = append(result.Documents, d.doc)
+		result.Embeddings = append(result.Embeddings, d.embedding)
+	}
+	return result, nil
+}
+
+// nativeStore records the arguments of its own maximal marginal relevance
+// search.
+type nativeStore struct {
+	embeddingStore
+	args [3]any
+}
+
+func (s *nativeStore) MaxMarginalRelevanceSearch(
+	_ context.Context,
+	_ string,
+	k, fetchK int,
+	lambda float32,
+	_ ...vectorstores.Option,
+) ([]schema.Document, error) {
+	s.args = [3]any{k, fetchK, lambda}
+	return []schema.Document{{PageContent: "native"}}, nil
+}
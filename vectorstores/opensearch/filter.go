@@ -0,0 +1,104 @@
+package opensearch
+
+import (
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+)
+
+// metadataField is the field of the documents holding their metadata.
+const metadataField = "metadata"
+
+// filterQuery returns the query clause for the filters given to WithFilters,
+// which are a query DSL clause or a vectorstores.Filter.
+func filterQuery(filters any) (map[string]any, error) {
+	if f, ok := vectorstores.AsFilter(filters); ok {
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+		return translateFilter(f)
+	}
+	query, ok := filters.(map[string]any)
+	if !ok {
+		return nil, ErrInvalidFilters
+	}
+	return query, nil
+}
+
+// translateFilter translates f into a query clause on the metadata of the
+// documents. Strings are matched on the keyword sub-field that dynamic
+// mapping adds to text fields.
+//
+//nolint:cyclop
+func translateFilter(f vectorstores.Filter) (map[string]any, error) {
+	switch f.Operator {
+	case vectorstores.FilterAnd, vectorstores.FilterOr, vectorstores.FilterNot:
+		clauses := make([]any, 0, len(f.Filters))
+		for _, operand := range f.Filters {
+			clause, err := translateFilter(operand)
+			if err != nil {
+				return nil, err
+			}
+			clauses = append(clauses, clause)
+		}
+		switch f.Operator { //nolint:exhaustive
+		case vectorstores.FilterAnd:
+			return boolQuery("filter", clauses...), nil
+		case vectorstores.FilterOr:
+			return boolQuery("should", clauses...), nil
+		default:
+			return boolQuery("must_not", clauses...), nil
+		}
+	case vectorstores.FilterEq, vectorstores.FilterContains:
+		// A term query on an array matches any of its elements.
+		return termQuery(f.Key, f.Value), nil
+	case vectorstores.FilterNe:
+		return boolQuery("must_not", termQuery(f.Key, f.Value)), nil
+	case vectorstores.FilterIn:
+		return termsQuery(f.Key, f.Values()), nil
+	case vectorstores.FilterNin:
+		return boolQuery("must_not", termsQuery(f.Key, f.Values())), nil
+	case vectorstores.FilterGt, vectorstores.FilterGte, vectorstores.FilterLt, vectorstores.FilterLte:
+		return map[string]any{"range": map[string]any{
+			field(f.Key, f.Value): map[string]any{string(f.Operator): f.Value},
+		}}, nil
+	case vectorstores.FilterExists:
+		return map[string]any{"exists": map[string]any{"field": metadataField + "." + f.Key}}, nil
+	default:
+		return nil, &vectorstores.UnsupportedFilterError{Store: "opensearch", Operator: f.Operator}
+	}
+}
+
+// field returns the field to match value with.
+func field(key string, value any) string {
+	if _, ok := value.(string); ok {
+		return metadataField + "." + key + ".keyword"
+	}
+	return metadataField + "." + key
+}
+
+func termQuery(key string, value any) map[string]any {
+	return map[string]any{"term": map[string]any{field(key, value): value}}
+}
+
+// termsQuery matches one of values, with a single terms query if they are
+// all matched on the same field.
+func termsQuery(key string, values []any) map[string]any {
+	name := field(key, values[0])
+	for _, v := range values[1:] {
+		if field(key, v) != name {
+			clauses := make([]any, 0, len(values))
+			for _, v := range values {
+				clauses = append(clauses, termQuery(key, v))
+			}
+			return boolQuery("should", clauses...)
+		}
+	}
+	return map[string]any{"terms": map[string]any{name: values}}
+}
+
+func boolQuery(occur string, clauses ...any) map[string]any {
+	query := map[string]any{occur: clauses}
+	if occur == "should" {
+		query["minimum_should_match"] = 1
+	}
+	return map[string]any{"bool": query}
+}
@@ -0,0 +1,54 @@
+package opensearch
+
+import (
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterQuery(t *testing.T) {
+	t.Parallel()
+
+	query, err := filterQuery(vectorstores.And(
+		vectorstores.Eq("source", "a.md"),
+		vectorstores.Lt("year", 2020),
+		vectorstores.Or(vectorstores.In("lang", "go", "rust"), vectorstores.Not(vectorstores.Exists("draft"))),
+		vectorstores.Nin("version", 1, "2"),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"bool": map[string]any{"filter": []any{
+		map[string]any{"term": map[string]any{"metadata.source.keyword": "a.md"}},
+		map[string]any{"range": map[string]any{"metadata.year": map[string]any{"lt": 2020}}},
+		map[string]any{"bool": map[string]any{
+			"should": []any{
+				map[string]any{"terms": map[string]any{"metadata.lang.keyword": []any{"go", "rust"}}},
+				map[string]any{"bool": map[string]any{"must_not": []any{
+					map[string]any{"exists": map[string]any{"field": "metadata.draft"}},
+				}}},
+			},
+			"minimum_should_match": 1,
+		}},
+		map[string]any{"bool": map[string]any{"must_not": []any{
+			map[string]any{"bool": map[string]any{
+				"should": []any{
+					map[string]any{"term": map[string]any{"metadata.version": 1}},
+					map[string]any{"term": map[string]any{"metadata.version.keyword": "2"}},
+				},
+				"minimum_should_match": 1,
+			}},
+		}}},
+	}}}, query)
+
+	native := map[string]any{"term": map[string]any{"metadata.source.keyword": "a.md"}}
+	query, err = filterQuery(native)
+	require.NoError(t, err)
+	assert.Equal(t, native, query)
+
+	_, err = filterQuery("source")
+	require.ErrorIs(t, err, ErrInvalidFilters)
+	_, err = filterQuery(vectorstores.In("lang"))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidFilter)
+}
@@ -33,6 +33,9 @@ var (
 	ErrAssertingMetadata = errors.New(
 		"couldn't assert metadata to map",
 	)
+	// ErrInvalidFilters is returned when the filters are neither a query
+	// clause nor a vectorstores.Filter.
+	ErrInvalidFilters = errors.New("invalid filters")
 )
 
 // New creates and returns a vectorstore object for Opensearch
@@ -88,7 +91,9 @@ func (s Store) AddDocuments(
 }
 
 // SimilaritySearch creates a vector embedding from the query using the embedder
-// and queries to find the most similar documents.
+// and queries to find the most similar documents. The filters given to
+// WithFilters, a query clause or a vectorstores.Filter, restrict the documents
+// returned.
 func (s Store) SimilaritySearch(
 	ctx context.Context,
 	query string,
@@ -102,17 +107,32 @@ func (s Store) SimilaritySearch(
 		return nil, err
 	}
 
-	searchPayload := map[string]interface{}{
-		"size": numDocuments,
-		"query": map[string]interface{}{
-			"knn": map[string]interface{}{
-				"contentVector": map[string]interface{}{
-					"vector": queryVector,
-					"k":      numDocuments,
-				},
+	searchQuery := map[string]interface{}{
+		"knn": map[string]interface{}{
+			"contentVector": map[string]interface{}{
+				"vector": queryVector,
+				"k":      numDocuments,
 			},
 		},
 	}
+	if opts.Filters != nil {
+		filter, err := filterQuery(opts.Filters)
+		if err != nil {
+			return nil, err
+		}
+		// Filter the nearest neighbors, which works with every engine.
+		searchQuery = map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must":   []interface{}{searchQuery},
+				"filter": []interface{}{filter},
+			},
+		}
+	}
+
+	searchPayload := map[string]interface{}{
+		"size":  numDocuments,
+		"query": searchQuery,
+	}
 
 	buf := new(bytes.Buffer)
 	if err := json.NewEncoder(buf).Encode(searchPayload); err != nil {
@@ -36,6 +36,9 @@ func WithScoreThreshold(scoreThreshold float32) Option {
 // filters retrieve exactly the number of nearest-neighbors results that match the filters. In
 // most cases the search latency will be lower than unfiltered searches
 // See https://docs.pinecone.io/docs/metadata-filtering
+//
+// The filters are either in the native format of the store, or a Filter that
+// the store translates to it.
 func WithFilters(filters any) Option {
 	return func(o *Options) {
 		o.Filters = filters
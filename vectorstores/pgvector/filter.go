@@ -0,0 +1,148 @@
+package pgvector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+)
+
+// sqlFilter translates a vectorstores.Filter into a condition on a metadata
+// column. Keys and values are passed as query arguments, numbered after the
+// arguments of the query the condition is part of.
+type sqlFilter struct {
+	column string
+	args   []any
+}
+
+func newSQLFilter(column string, args []any) *sqlFilter {
+	return &sqlFilter{column: column, args: args}
+}
+
+// arg adds a query argument and returns its placeholder.
+func (b *sqlFilter) arg(v any) string {
+	b.args = append(b.args, v)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// jsonArg adds a query argument holding v as JSON and returns its placeholder.
+func (b *sqlFilter) jsonArg(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return b.arg(string(data)) + "::jsonb", nil
+}
+
+//nolint:cyclop
+func (b *sqlFilter) translate(f vectorstores.Filter) (string, error) {
+	if f.Operator == vectorstores.FilterAnd || f.Operator == vectorstores.FilterOr || f.Operator == vectorstores.FilterNot {
+		conditions := make([]string, 0, len(f.Filters))
+		for _, operand := range f.Filters {
+			condition, err := b.translate(operand)
+			if err != nil {
+				return "", err
+			}
+			conditions = append(conditions, condition)
+		}
+		switch f.Operator { //nolint:exhaustive
+		case vectorstores.FilterAnd:
+			return "(" + strings.Join(conditions, " AND ") + ")", nil
+		case vectorstores.FilterOr:
+			return "(" + strings.Join(conditions, " OR ") + ")", nil
+		default:
+			// Conditions on a missing key are NULL, which NOT keeps NULL.
+			return "(NOT coalesce(" + conditions[0] + ", false))", nil
+		}
+	}
+
+	field := fmt.Sprintf("(%s::jsonb -> %s::text)", b.column, b.arg(f.Key))
+
+	switch f.Operator { //nolint:exhaustive
+	case vectorstores.FilterExists:
+		return field + " IS NOT NULL", nil
+	case vectorstores.FilterIn, vectorstores.FilterNin:
+		values := make([]string, 0, len(f.Values()))
+		for _, v := range f.Values() {
+			value, err := b.jsonArg(v)
+			if err != nil {
+				return "", err
+			}
+			values = append(values, value)
+		}
+		condition := fmt.Sprintf("%s = ANY(ARRAY[%s])", field, strings.Join(values, ", "))
+		if f.Operator == vectorstores.FilterNin {
+			return fmt.Sprintf("(NOT coalesce(%s, false))", condition), nil
+		}
+		return condition, nil
+	case vectorstores.FilterContains:
+		value, err := b.jsonArg([]any{f.Value})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(jsonb_typeof(%s) = 'array' AND %s @> %s)", field, field, value), nil
+	}
+
+	value, err := b.jsonArg(f.Value)
+	if err != nil {
+		return "", err
+	}
+	switch f.Operator { //nolint:exhaustive
+	case vectorstores.FilterEq:
+		return fmt.Sprintf("%s = %s", field, value), nil
+	case vectorstores.FilterNe:
+		return fmt.Sprintf("%s IS DISTINCT FROM %s", field, value), nil
+	}
+
+	var op string
+	switch f.Operator { //nolint:exhaustive
+	case vectorstores.FilterGt:
+		op = ">"
+	case vectorstores.FilterGte:
+		op = ">="
+	case vectorstores.FilterLt:
+		op = "<"
+	case vectorstores.FilterLte:
+		op = "<="
+	default:
+		return "", &vectorstores.UnsupportedFilterError{Store: "pgvector", Operator: f.Operator}
+	}
+	// jsonb orders values of different types, so only compare like types.
+	return fmt.Sprintf("(jsonb_typeof(%s) = jsonb_typeof(%s) AND %s %s %s)", field, value, field, op, value), nil
+}
+
+// filterCondition returns the SQL condition for the filters given to
+// WithFilters on column, and the query arguments extended with the ones of
+// the condition. Filters are a map[string]any of values or a
+// vectorstores.Filter.
+func (s Store) filterCondition(filters any, column string, args []any) (string, []any, error) {
+	if f, ok := vectorstores.AsFilter(filters); ok {
+		if err := f.Validate(); err != nil {
+			return "", nil, err
+		}
+		b := newSQLFilter(column, args)
+		condition, err := b.translate(f)
+		if err != nil {
+			return "", nil, err
+		}
+		return condition, b.args, nil
+	}
+
+	var m map[string]any
+	if filters != nil {
+		var ok bool
+		if m, ok = filters.(map[string]any); !ok {
+			return "", nil, ErrInvalidFilters
+		}
+	}
+	conditions := make([]string, 0, len(m))
+	for k, v := range m {
+		args = append(args, k, fmt.Sprint(v))
+		conditions = append(conditions, fmt.Sprintf("(%s ->> $%d::text) = $%d::text", column, len(args)-1, len(args)))
+	}
+	if len(conditions) == 0 {
+		return "TRUE", args, nil
+	}
+	return strings.Join(conditions, " AND "), args, nil
+}
@@ -0,0 +1,53 @@
+package pgvector
+
+import (
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLFilterNot(t *testing.T) {
+	t.Parallel()
+
+	// Documents without the key match the negated condition.
+	tests := []struct {
+		name   string
+		filter vectorstores.Filter
+		want   string
+		args   []any
+	}{
+		{
+			name:   "eq",
+			filter: vectorstores.Not(vectorstores.Eq("source", "a.md")),
+			want:   "(NOT coalesce((cmetadata::jsonb -> $2::text) = $3::jsonb, false))",
+			args:   []any{"query", "source", `"a.md"`},
+		},
+		{
+			name:   "gt",
+			filter: vectorstores.Not(vectorstores.Gt("year", 2020)),
+			want: "(NOT coalesce((jsonb_typeof((cmetadata::jsonb -> $2::text)) = jsonb_typeof($3::jsonb) AND " +
+				"(cmetadata::jsonb -> $2::text) > $3::jsonb), false))",
+			args: []any{"query", "year", "2020"},
+		},
+		{
+			name:   "in",
+			filter: vectorstores.Not(vectorstores.In("lang", "go", "rust")),
+			want:   "(NOT coalesce((cmetadata::jsonb -> $2::text) = ANY(ARRAY[$3::jsonb, $4::jsonb]), false))",
+			args:   []any{"query", "lang", `"go"`, `"rust"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := newSQLFilter("cmetadata", []any{"query"})
+			condition, err := b.translate(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, condition)
+			assert.Equal(t, tt.args, b.args)
+		})
+	}
+}
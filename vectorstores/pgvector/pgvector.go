@@ -35,6 +35,7 @@ var (
 	ErrInvalidScoreThreshold      = errors.New("score threshold must be between 0 and 1")
 	ErrInvalidFilters             = errors.New("invalid filters")
 	ErrUnsupportedOptions         = errors.New("unsupported options")
+	ErrIDConflict                 = errors.New("document id conflict")
 )
 
 // PGXConn represents both a pgx.Conn and pgxpool.Pool conn.
@@ -72,7 +73,14 @@ type HNSWIndex struct {
 	distanceFunction string
 }
 
-var _ vectorstores.VectorStore = Store{}
+var (
+	_ vectorstores.VectorStore   = Store{}
+	_ vectorstores.Deleter       = Store{}
+	_ vectorstores.FilterDeleter = Store{}
+	_ vectorstores.Upserter      = Store{}
+
+	_ vectorstores.EmbeddingSearcher = Store{}
+)
 
 // New creates a new Store with options.
 func New(ctx context.Context, opts ...Option) (Store, error) {
@@ -269,6 +277,123 @@ func (s Store) AddDocuments(
 	return ids, s.conn.SendBatch(ctx, b).Close()
 }
 
+// UpsertDocuments adds documents to the Postgres collection associated with
+// 'Store', or to the collection named by the name space option, under the
+// given ids, replacing the documents already stored with the same ids. Ids
+// that are not UUIDs are mapped to a UUID derived from the collection name and
+// the id. It fails with ErrIDConflict if an id belongs to a document of
+// another collection, or if the collection does not exist.
+func (s Store) UpsertDocuments(
+	ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) ([]string, error) {
+	opts := s.getOptions(options...)
+	if opts.ScoreThreshold != 0 || opts.Filters != nil {
+		return nil, ErrUnsupportedOptions
+	}
+	if len(ids) != len(docs) {
+		return nil, vectorstores.ErrMismatchedIDs
+	}
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.PageContent)
+	}
+
+	embedder := s.embedder
+	if opts.Embedder != nil {
+		embedder = opts.Embedder
+	}
+	vectors, err := embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vectors) != len(docs) {
+		return nil, ErrEmbedderWrongNumberVectors
+	}
+
+	// A document of another collection with the same id is left untouched,
+	// and so is a missing collection: no row is written then.
+	b := &pgx.Batch{}
+	sql := fmt.Sprintf(`INSERT INTO %s (uuid, document, embedding, cmetadata, collection_id)
+		SELECT $1::uuid, $2::varchar, $3::vector, $4::json, uuid FROM %s WHERE name = $5
+		ON CONFLICT (uuid) DO UPDATE SET
+		document = EXCLUDED.document, embedding = EXCLUDED.embedding, cmetadata = EXCLUDED.cmetadata
+		WHERE %s.collection_id = EXCLUDED.collection_id`,
+		s.embeddingTableName, s.collectionTableName, s.embeddingTableName)
+
+	nameSpace := s.getNameSpace(opts)
+	stored := make([]string, len(docs))
+	for docIdx, doc := range docs {
+		id := vectorstores.StableUUID(nameSpace, ids[docIdx])
+		stored[docIdx] = id
+		b.Queue(sql, id, doc.PageContent, pgvector.NewVector(vectors[docIdx]), doc.Metadata, nameSpace)
+	}
+
+	results := s.conn.SendBatch(ctx, b)
+	for docIdx := range docs {
+		tag, err := results.Exec()
+		if err != nil {
+			results.Close()
+			return nil, err
+		}
+		if tag.RowsAffected() == 0 {
+			results.Close()
+			return nil, fmt.Errorf("%w: %s in collection %s", ErrIDConflict, ids[docIdx], nameSpace)
+		}
+	}
+	return stored, results.Close()
+}
+
+// Delete removes the documents with the given ids from the collection, or
+// from the collection named by the name space option.
+func (s Store) Delete(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	opts := s.getOptions(options...)
+	nameSpace := s.getNameSpace(opts)
+	documentIDs := make([]string, len(ids))
+	for i, id := range ids {
+		documentIDs[i] = vectorstores.StableUUID(nameSpace, id)
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s
+WHERE collection_id = (SELECT uuid FROM %s WHERE name = $1) AND uuid = ANY($2::uuid[])`,
+		s.embeddingTableName, s.collectionTableName)
+	_, err := s.conn.Exec(ctx, sql, nameSpace, documentIDs)
+	return err
+}
+
+// DeleteByFilter removes the documents whose metadata matches the filter from
+// the collection, or from the collection named by the name space option. The
+// filter is a map[string]any or a vectorstores.Filter like the ones given to
+// WithFilters.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	opts := s.getOptions(options...)
+	if filter == nil {
+		return ErrInvalidFilters
+	}
+	if filters, ok := filter.(map[string]any); ok && len(filters) == 0 {
+		return vectorstores.ErrEmptyFilter
+	}
+
+	whereQuery, args, err := s.filterCondition(filter, "cmetadata", []any{s.getNameSpace(opts)})
+	if err != nil {
+		return err
+	}
+
+	sql := fmt.Sprintf(`DELETE FROM %s
+WHERE collection_id = (SELECT uuid FROM %s WHERE name = $1) AND %s`,
+		s.embeddingTableName, s.collectionTableName, whereQuery)
+	_, err = s.conn.Exec(ctx, sql, args...)
+	return err
+}
+
 //nolint:cyclop
 func (s Store) SimilaritySearch(
 	ctx context.Context,
@@ -276,15 +401,40 @@ func (s Store) SimilaritySearch(
 	numDocuments int,
 	options ...vectorstores.Option,
 ) ([]schema.Document, error) {
-	opts := s.getOptions(options...)
-	collectionName := s.getNameSpace(opts)
-	scoreThreshold, err := s.getScoreThreshold(opts)
+	result, err := s.similaritySearch(ctx, query, numDocuments, false, options...)
 	if err != nil {
 		return nil, err
 	}
-	filter, err := s.getFilters(opts)
+	return result.Documents, nil
+}
+
+// SimilaritySearchWithEmbeddings performs a similarity search like
+// SimilaritySearch, and also returns the embeddings of the query and of the
+// documents found, so that vectorstores.MaxMarginalRelevanceSearch can be used
+// with the store.
+func (s Store) SimilaritySearchWithEmbeddings(
+	ctx context.Context,
+	query string,
+	numDocuments int,
+	options ...vectorstores.Option,
+) (vectorstores.EmbeddingSearchResult, error) {
+	return s.similaritySearch(ctx, query, numDocuments, true, options...)
+}
+
+//nolint:funlen
+func (s Store) similaritySearch(
+	ctx context.Context,
+	query string,
+	numDocuments int,
+	withEmbeddings bool,
+	options ...vectorstores.Option,
+) (vectorstores.EmbeddingSearchResult, error) {
+	var result vectorstores.EmbeddingSearchResult
+	opts := s.getOptions(options...)
+	collectionName := s.getNameSpace(opts)
+	scoreThreshold, err := s.getScoreThreshold(opts)
 	if err != nil {
-		return nil, err
+		return result, err
 	}
 	embedder := s.embedder
 	if opts.Embedder != nil {
@@ -292,20 +442,23 @@ func (s Store) SimilaritySearch(
 	}
 	embedderData, err := embedder.EmbedQuery(ctx, query)
 	if err != nil {
-		return nil, err
+		return result, err
 	}
-	whereQuerys := make([]string, 0)
+	dims := len(embedderData)
+	filterQuery, args, err := s.filterCondition(opts.Filters, "data.cmetadata",
+		[]any{dims, pgvector.NewVector(embedderData), numDocuments})
+	if err != nil {
+		return result, err
+	}
+	whereQuerys := []string{filterQuery}
 	if scoreThreshold != 0 {
 		whereQuerys = append(whereQuerys, fmt.Sprintf("data.distance < %f", 1-scoreThreshold))
 	}
-	for k, v := range filter {
-		whereQuerys = append(whereQuerys, fmt.Sprintf("(data.cmetadata ->> '%s') = '%s'", k, v))
-	}
 	whereQuery := strings.Join(whereQuerys, " AND ")
-	if len(whereQuery) == 0 {
-		whereQuery = "TRUE"
+	embeddingColumn := ""
+	if withEmbeddings {
+		embeddingColumn = ",\n\tdata.embedding"
 	}
-	dims := len(embedderData)
 	sql := fmt.Sprintf(`WITH filtered_embedding_dims AS MATERIALIZED (
     SELECT
         *
@@ -319,7 +472,7 @@ func (s Store) SimilaritySearch(
 SELECT
 	data.document,
 	data.cmetadata,
-	(1 - data.distance) AS score
+	(1 - data.distance) AS score%s
 FROM (
 	SELECT
 		filtered_embedding_dims.*,
@@ -330,24 +483,33 @@ FROM (
 WHERE %s
 ORDER BY
 	data.distance
-LIMIT $3`, s.embeddingTableName,
+LIMIT $3`, s.embeddingTableName, embeddingColumn,
 		s.collectionTableName, s.collectionTableName, s.collectionTableName, collectionName,
 		whereQuery)
-	rows, err := s.conn.Query(ctx, sql, dims, pgvector.NewVector(embedderData), numDocuments)
+	rows, err := s.conn.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, err
+		return result, err
 	}
 	defer rows.Close()
 
-	docs := make([]schema.Document, 0)
+	result.Query = embedderData
+	result.Documents = make([]schema.Document, 0)
 	for rows.Next() {
 		doc := schema.Document{}
-		if err := rows.Scan(&doc.PageContent, &doc.Metadata, &doc.Score); err != nil {
-			return nil, err
+		dest := []any{&doc.PageContent, &doc.Metadata, &doc.Score}
+		var embedding pgvector.Vector
+		if withEmbeddings {
+			dest = append(dest, &embedding)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return result, err
+		}
+		result.Documents = append(result.Documents, doc)
+		if withEmbeddings {
+			result.Embeddings = append(result.Embeddings, embedding.Slice())
 		}
-		docs = append(docs, doc)
 	}
-	return docs, rows.Err()
+	return result, rows.Err()
 }
 
 //nolint:cyclop
@@ -358,18 +520,10 @@ func (s Store) Search(
 ) ([]schema.Document, error) {
 	opts := s.getOptions(options...)
 	collectionName := s.getNameSpace(opts)
-	filter, err := s.getFilters(opts)
+	whereQuery, args, err := s.filterCondition(opts.Filters, s.embeddingTableName+".cmetadata", []any{numDocuments})
 	if err != nil {
 		return nil, err
 	}
-	whereQuerys := make([]string, 0)
-	for k, v := range filter {
-		whereQuerys = append(whereQuerys, fmt.Sprintf("(%s.cmetadata ->> '%s') = '%s'", s.embeddingTableName, k, v))
-	}
-	whereQuery := strings.Join(whereQuerys, " AND ")
-	if len(whereQuery) == 0 {
-		whereQuery = "TRUE"
-	}
 	sql := fmt.Sprintf(`SELECT
 	%s.document,
 	%s.cmetadata
@@ -379,7 +533,7 @@ WHERE %s.name='%s' AND %s
 LIMIT $1`, s.embeddingTableName, s.embeddingTableName, s.embeddingTableName,
 		s.collectionTableName, s.embeddingTableName, s.collectionTableName, s.collectionTableName, collectionName,
 		whereQuery)
-	rows, err := s.conn.Query(ctx, sql, numDocuments)
+	rows, err := s.conn.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -446,18 +600,6 @@ func (s Store) getScoreThreshold(opts vectorstores.Options) (float32, error) {
 	return opts.ScoreThreshold, nil
 }
 
-// getFilters return metadata filters, now only support map[key]value pattern
-// TODO: should support more types like {"key1": {"key2":"values2"}} or {"key": ["value1", "values2"]}.
-func (s Store) getFilters(opts vectorstores.Options) (map[string]any, error) {
-	if opts.Filters != nil {
-		if filters, ok := opts.Filters.(map[string]any); ok {
-			return filters, nil
-		}
-		return nil, ErrInvalidFilters
-	}
-	return map[string]any{}, nil
-}
-
 func (s Store) deduplicate(
 	ctx context.Context,
 	opts vectorstores.Options,
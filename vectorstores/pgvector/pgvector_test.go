@@ -905,3 +905,231 @@ func TestWithAllOptions(t *testing.T) {
 	require.Equal(t, "tokyo", docs[0].PageContent)
 	require.Equal(t, "japan", docs[0].Metadata["country"])
 }
+
+// keywordEmbedder embeds texts by counting a few keywords, so that tests
+// that don't check relevance can run without an embedding API.
+type keywordEmbedder struct{}
+
+func (keywordEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = keywordEmbedder{}.EmbedQuery(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (keywordEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return []float32{
+		float32(strings.Count(text, "city")) + 0.1,
+		float32(strings.Count(text, "vegetable")) + 0.1,
+		float32(strings.Count(text, "fruit")) + 0.1,
+	}, nil
+}
+
+func TestDeleteAndUpsert(t *testing.T) {
+	t.Parallel()
+
+	pgvectorURL := preCheckEnvSetting(t)
+	ctx := t.Context()
+
+	conn, err := pgx.Connect(ctx, pgvectorURL)
+	require.NoError(t, err)
+
+	store, err := pgvector.New(
+		ctx,
+		pgvector.WithConn(conn),
+		pgvector.WithEmbedder(keywordEmbedder{}),
+		pgvector.WithPreDeleteCollection(true),
+		pgvector.WithCollectionName(makeNewCollectionName()),
+	)
+	require.NoError(t, err)
+
+	defer cleanupTestArtifacts(ctx, t, store, pgvectorURL)
+
+	ids, err := store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "tokyo is a city", Metadata: map[string]any{"source": "a.md"}},
+		{PageContent: "potato is a vegetable", Metadata: map[string]any{"source": "b.md"}},
+		{PageContent: "apple is a fruit", Metadata: map[string]any{"source": "b.md"}},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, store.Delete(ctx, ids[:1]))
+	docs, err := store.Search(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	require.NoError(t, store.DeleteByFilter(ctx, map[string]any{"source": "b.md"}))
+	docs, err = store.Search(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+
+	_, err = store.UpsertDocuments(ctx, []string{"a.md#0"}, []schema.Document{
+		{PageContent: "tokyo is a city", Metadata: map[string]any{"version": "1"}},
+	})
+	require.NoError(t, err)
+	upserted, err := store.UpsertDocuments(ctx, []string{"a.md#0"}, []schema.Document{
+		{PageContent: "kyoto is a city", Metadata: map[string]any{"version": "2"}},
+	})
+	require.NoError(t, err)
+
+	docs, err = store.Search(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "kyoto is a city", docs[0].PageContent)
+
+	require.NoError(t, store.Delete(ctx, []string{"a.md#0"}))
+	require.NoError(t, store.Delete(ctx, upserted))
+	docs, err = store.Search(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+}
+
+func TestUpsertCollections(t *testing.T) {
+	t.Parallel()
+
+	pgvectorURL := preCheckEnvSetting(t)
+	ctx := t.Context()
+
+	conn, err := pgx.Connect(ctx, pgvectorURL)
+	require.NoError(t, err)
+
+	newStore := func(name string) pgvector.Store {
+		store, err := pgvector.New(
+			ctx,
+			pgvector.WithConn(conn),
+			pgvector.WithEmbedder(keywordEmbedder{}),
+			pgvector.WithPreDeleteCollection(true),
+			pgvector.WithCollectionName(name),
+		)
+		require.NoError(t, err)
+		return store
+	}
+	secondName := makeNewCollectionName()
+	first, second := newStore(makeNewCollectionName()), newStore(secondName)
+	defer cleanupTestArtifacts(ctx, t, first, pgvectorURL)
+	defer cleanupTestArtifacts(ctx, t, second, pgvectorURL)
+
+	// The same id names a different document in each collection.
+	_, err = first.UpsertDocuments(ctx, []string{"a.md#0"}, []schema.Document{{PageContent: "tokyo is a city"}})
+	require.NoError(t, err)
+	_, err = second.UpsertDocuments(ctx, []string{"a.md#0"}, []schema.Document{{PageContent: "kyoto is a city"}})
+	require.NoError(t, err)
+
+	docs, err := first.Search(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "tokyo is a city", docs[0].PageContent)
+
+	// A UUID of another collection is not taken over.
+	ids, err := first.AddDocuments(ctx, []schema.Document{{PageContent: "potato is a vegetable"}})
+	require.NoError(t, err)
+	_, err = second.UpsertDocuments(ctx, ids, []schema.Document{{PageContent: "apple is a fruit"}})
+	require.ErrorIs(t, err, pgvector.ErrIDConflict)
+
+	// The name space option selects the collection of every method.
+	_, err = first.UpsertDocuments(ctx, []string{"b.md#0"}, []schema.Document{{PageContent: "apple is a fruit"}},
+		vectorstores.WithNameSpace(secondName))
+	require.NoError(t, err)
+	docs, err = second.Search(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	require.NoError(t, first.Delete(ctx, []string{"a.md#0", "b.md#0"}, vectorstores.WithNameSpace(secondName)))
+	docs, err = second.Search(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+	docs, err = first.Search(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+}
+
+func TestSimilaritySearchWithFilter(t *testing.T) {
+	t.Parallel()
+
+	pgvectorURL := preCheckEnvSetting(t)
+	ctx := t.Context()
+
+	conn, err := pgx.Connect(ctx, pgvectorURL)
+	require.NoError(t, err)
+
+	store, err := pgvector.New(
+		ctx,
+		pgvector.WithConn(conn),
+		pgvector.WithEmbedder(keywordEmbedder{}),
+		pgvector.WithPreDeleteCollection(true),
+		pgvector.WithCollectionName(makeNewCollectionName()),
+	)
+	require.NoError(t, err)
+
+	defer cleanupTestArtifacts(ctx, t, store, pgvectorURL)
+
+	_, err = store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "tokyo is a city", Metadata: map[string]any{"population": 14, "tags": []any{"asia"}}},
+		{PageContent: "paris is a city", Metadata: map[string]any{"population": 2, "tags": []any{"europe"}}},
+		{PageContent: "potato is a vegetable", Metadata: map[string]any{"color": "brown"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := store.SimilaritySearch(ctx, "city", 3, vectorstores.WithFilters(
+		vectorstores.And(vectorstores.Gt("population", 5), vectorstores.Contains("tags", "asia")),
+	))
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "tokyo is a city", docs[0].PageContent)
+
+	docs, err = store.Search(ctx, 10, vectorstores.WithFilters(
+		vectorstores.Or(vectorstores.In("population", 2, 3), vectorstores.Not(vectorstores.Exists("tags"))),
+	))
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	require.NoError(t, store.DeleteByFilter(ctx, vectorstores.Ne("color", "brown")))
+	docs, err = store.Search(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "potato is a vegetable", docs[0].PageContent)
+}
+
+func TestMaxMarginalRelevanceSearch(t *testing.T) {
+	t.Parallel()
+
+	pgvectorURL := preCheckEnvSetting(t)
+	ctx := t.Context()
+
+	conn, err := pgx.Connect(ctx, pgvectorURL)
+	require.NoError(t, err)
+
+	store, err := pgvector.New(
+		ctx,
+		pgvector.WithConn(conn),
+		pgvector.WithEmbedder(keywordEmbedder{}),
+		pgvector.WithPreDeleteCollection(true),
+		pgvector.WithCollectionName(makeNewCollectionName()),
+	)
+	require.NoError(t, err)
+
+	defer cleanupTestArtifacts(ctx, t, store, pgvectorURL)
+
+	_, err = store.AddDocuments(ctx, []schema.Document{
+		{PageContent: "tokyo is a city"},
+		{PageContent: "paris is a city"},
+		{PageContent: "a city of vegetable farms"},
+		{PageContent: "potato is a vegetable"},
+	})
+	require.NoError(t, err)
+
+	result, err := store.SimilaritySearchWithEmbeddings(ctx, "city", 3)
+	require.NoError(t, err)
+	require.Len(t, result.Documents, 3)
+	require.Len(t, result.Embeddings, 3)
+	require.InDeltaSlice(t, []float32{1.1, 0.1, 0.1}, result.Query, 1e-6)
+	require.InDeltaSlice(t, []float32{1.1, 0.1, 0.1}, result.Embeddings[0], 1e-6)
+
+	// The two documents about a city have the same embedding, so only one of
+	// them is selected.
+	docs, err := vectorstores.MaxMarginalRelevanceSearch(ctx, store, "city", 2, 3, 0.3)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	require.Contains(t, []string{"tokyo is a city", "paris is a city"}, docs[0].PageContent)
+	require.Equal(t, "a city of vegetable farms", docs[1].PageContent)
+}
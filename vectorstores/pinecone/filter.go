@@ -0,0 +1,46 @@
+package pinecone
+
+import (
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+)
+
+// translateFilter translates f into a Pinecone metadata filter. Pinecone
+// only compares numbers, and matches a list of strings with Contains. It has
+// no equivalent for Not.
+func translateFilter(f vectorstores.Filter) (map[string]any, error) {
+	switch f.Operator {
+	case vectorstores.FilterAnd, vectorstores.FilterOr:
+		clauses := make([]any, 0, len(f.Filters))
+		for _, operand := range f.Filters {
+			clause, err := translateFilter(operand)
+			if err != nil {
+				return nil, err
+			}
+			clauses = append(clauses, clause)
+		}
+		return map[string]any{"$" + string(f.Operator): clauses}, nil
+	case vectorstores.FilterEq, vectorstores.FilterNe:
+		return map[string]any{f.Key: map[string]any{"$" + string(f.Operator): f.Value}}, nil
+	case vectorstores.FilterIn, vectorstores.FilterNin:
+		return map[string]any{f.Key: map[string]any{"$" + string(f.Operator): f.Values()}}, nil
+	case vectorstores.FilterGt, vectorstores.FilterGte, vectorstores.FilterLt, vectorstores.FilterLte:
+		if _, ok := vectorstores.FilterNumber(f.Value); !ok {
+			return nil, &vectorstores.UnsupportedFilterError{
+				Store: "pinecone", Operator: f.Operator, Reason: "only numbers can be compared",
+			}
+		}
+		return map[string]any{f.Key: map[string]any{"$" + string(f.Operator): f.Value}}, nil
+	case vectorstores.FilterExists:
+		return map[string]any{f.Key: map[string]any{"$exists": true}}, nil
+	case vectorstores.FilterContains:
+		if _, ok := f.Value.(string); !ok {
+			return nil, &vectorstores.UnsupportedFilterError{
+				Store: "pinecone", Operator: f.Operator, Reason: "lists only hold strings",
+			}
+		}
+		// $in matches a list of strings holding any of the values.
+		return map[string]any{f.Key: map[string]any{"$in": []any{f.Value}}}, nil
+	default:
+		return nil, &vectorstores.UnsupportedFilterError{Store: "pinecone", Operator: f.Operator}
+	}
+}
@@ -43,6 +43,13 @@ type Store struct {
 	httpClient *http.Client
 }
 
+var (
+	_ vectorstores.VectorStore   = Store{}
+	_ vectorstores.Deleter       = Store{}
+	_ vectorstores.FilterDeleter = Store{}
+	_ vectorstores.Upserter      = Store{}
+)
+
 // New creates a new Store with options. Options for WithAPIKey, WithHost and WithEmbedder must be set.
 func New(opts ...Option) (Store, error) {
 	s, err := applyClientOptions(opts...)
@@ -66,6 +73,81 @@ func New(opts ...Option) (Store, error) {
 func (s Store) AddDocuments(ctx context.Context,
 	docs []schema.Document,
 	options ...vectorstores.Option,
+) ([]string, error) {
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.New().String()
+	}
+	return s.upsertDocuments(ctx, ids, docs, options...)
+}
+
+// UpsertDocuments creates vector embeddings from the documents using the embedder
+// and upserts the vectors to the pinecone index under the given ids, replacing the
+// vectors already stored with the same ids.
+func (s Store) UpsertDocuments(ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) ([]string, error) {
+	if len(ids) != len(docs) {
+		return nil, vectorstores.ErrMismatchedIDs
+	}
+	return s.upsertDocuments(ctx, append([]string(nil), ids...), docs, options...)
+}
+
+// Delete deletes the vectors with the given ids from the name space of the index.
+func (s Store) Delete(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	opts := s.getOptions(options...)
+
+	indexConn, err := s.client.Index(pinecone.NewIndexConnParams{
+		Host:      s.host,
+		Namespace: s.getNameSpace(opts),
+	})
+	if err != nil {
+		return err
+	}
+	defer indexConn.Close()
+
+	return indexConn.DeleteVectorsById(ctx, ids)
+}
+
+// DeleteByFilter deletes the vectors matching the metadata filter or the
+// vectorstores.Filter, like the ones given to WithFilters, from the name space
+// of the index.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrEmptyFilter
+	}
+	protoFilterStruct, err := s.createProtoStructFilter(filter)
+	if err != nil {
+		return err
+	}
+	if len(protoFilterStruct.GetFields()) == 0 {
+		return vectorstores.ErrEmptyFilter
+	}
+
+	opts := s.getOptions(options...)
+
+	indexConn, err := s.client.Index(pinecone.NewIndexConnParams{
+		Host:      s.host,
+		Namespace: s.getNameSpace(opts),
+	})
+	if err != nil {
+		return err
+	}
+	defer indexConn.Close()
+
+	return indexConn.DeleteVectorsByFilter(ctx, protoFilterStruct)
+}
+
+func (s Store) upsertDocuments(ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
 ) ([]string, error) {
 	opts := s.getOptions(options...)
 
@@ -105,19 +187,16 @@ func (s Store) AddDocuments(ctx context.Context,
 
 	pineconeVectors := make([]*pinecone.Vector, 0, len(vectors))
 
-	ids := make([]string, len(vectors))
 	for i := 0; i < len(vectors); i++ {
 		metadataStruct, err := structpb.NewStruct(metadatas[i])
 		if err != nil {
 			return nil, err
 		}
 
-		id := uuid.New().String()
-		ids[i] = id
 		pineconeVectors = append(
 			pineconeVectors,
 			&pinecone.Vector{
-				Id:       id,
+				Id:       ids[i],
 				Values:   &vectors[i],
 				Metadata: metadataStruct,
 			},
@@ -241,7 +320,20 @@ func (s Store) getOptions(options ...vectorstores.Option) vectorstores.Options {
 	return opts
 }
 
+// createProtoStructFilter converts a metadata filter, or a vectorstores.Filter
+// translated to one, to the filter of the Pinecone client.
 func (s Store) createProtoStructFilter(filter any) (*pinecone.MetadataFilter, error) {
+	if f, ok := vectorstores.AsFilter(filter); ok {
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+		translated, err := translateFilter(f)
+		if err != nil {
+			return nil, err
+		}
+		filter = translated
+	}
+
 	filterBytes, err := json.Marshal(filter)
 	if err != nil {
 		return nil, err
@@ -5,9 +5,11 @@ import (
 	"os"
 	"testing"
 
+	"github.com/sayerxofficial/langchaingo/schema"
 	"github.com/sayerxofficial/langchaingo/vectorstores"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // testEmbedder is a mock embedder for testing
@@ -516,6 +518,25 @@ func TestStoreImplementsVectorStore(t *testing.T) {
 	var _ vectorstores.VectorStore = &Store{}
 }
 
+func TestDeleteAndUpsertValidation(t *testing.T) {
+	t.Parallel()
+
+	store := Store{embedder: &testEmbedder{}, textKey: "text"}
+
+	var _ vectorstores.Deleter = store
+	var _ vectorstores.FilterDeleter = store
+	var _ vectorstores.Upserter = store
+
+	_, err := store.UpsertDocuments(t.Context(), []string{"a", "b"}, []schema.Document{{PageContent: "doc"}})
+	assert.ErrorIs(t, err, vectorstores.ErrMismatchedIDs)
+
+	assert.ErrorIs(t, store.DeleteByFilter(t.Context(), nil), vectorstores.ErrEmptyFilter)
+	assert.ErrorIs(t, store.DeleteByFilter(t.Context(), map[string]any{}), vectorstores.ErrEmptyFilter)
+
+	// nothing to delete, so the index is never called
+	assert.NoError(t, store.Delete(t.Context(), nil))
+}
+
 func TestEdgeCases(t *testing.T) {
 	t.Parallel()
 
@@ -733,3 +754,26 @@ func TestDocumentProcessing(t *testing.T) {
 		assert.Equal(t, "content", store.textKey)
 	})
 }
+
+func TestCreateProtoStructFilterTranslatesFilter(t *testing.T) {
+	t.Parallel()
+
+	store := Store{}
+	filter, err := store.createProtoStructFilter(vectorstores.Or(
+		vectorstores.And(vectorstores.Gt("year", 2020), vectorstores.Exists("author")),
+		vectorstores.Contains("tags", "go"),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"$or": []any{
+		map[string]any{"$and": []any{
+			map[string]any{"year": map[string]any{"$gt": float64(2020)}},
+			map[string]any{"author": map[string]any{"$exists": true}},
+		}},
+		map[string]any{"tags": map[string]any{"$in": []any{"go"}}},
+	}}, filter.AsMap())
+
+	_, err = store.createProtoStructFilter(vectorstores.Not(vectorstores.Eq("year", 2020)))
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+	_, err = store.createProtoStructFilter(vectorstores.Contains("tags", 1))
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+}
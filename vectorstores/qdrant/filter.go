@@ -0,0 +1,104 @@
+package qdrant
+
+import (
+	"math"
+
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+)
+
+// filterCondition returns the Qdrant filter for the filters given to
+// WithFilters. A vectorstores.Filter is translated, other filters are
+// expected to be Qdrant filters already and are returned as is.
+func filterCondition(filters any) (any, error) {
+	f, ok := vectorstores.AsFilter(filters)
+	if !ok {
+		return filters, nil
+	}
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	condition, err := translateFilter(f)
+	if err != nil {
+		return nil, err
+	}
+	// The top level of a Qdrant filter must be a clause.
+	return map[string]any{"must": []any{condition}}, nil
+}
+
+// translateFilter translates f into a Qdrant condition. Keys are payload
+// keys, which hold the document metadata. Exists matches keys that are set
+// to a value other than null or an empty array, as Qdrant's is_empty does.
+//
+//nolint:cyclop
+func translateFilter(f vectorstores.Filter) (any, error) {
+	switch f.Operator {
+	case vectorstores.FilterAnd, vectorstores.FilterOr, vectorstores.FilterNot:
+		conditions := make([]any, 0, len(f.Filters))
+		for _, operand := range f.Filters {
+			condition, err := translateFilter(operand)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition)
+		}
+		clause := map[vectorstores.FilterOperator]string{
+			vectorstores.FilterAnd: "must",
+			vectorstores.FilterOr:  "should",
+			vectorstores.FilterNot: "must_not",
+		}[f.Operator]
+		return map[string]any{clause: conditions}, nil
+	case vectorstores.FilterEq, vectorstores.FilterContains:
+		// A match on an array payload matches any of its elements.
+		return matchCondition(f.Key, f.Value), nil
+	case vectorstores.FilterNe:
+		return map[string]any{"must_not": []any{matchCondition(f.Key, f.Value)}}, nil
+	case vectorstores.FilterIn, vectorstores.FilterNin:
+		condition := anyCondition(f.Key, f.Values())
+		if f.Operator == vectorstores.FilterNin {
+			return map[string]any{"must_not": []any{condition}}, nil
+		}
+		return condition, nil
+	case vectorstores.FilterGt, vectorstores.FilterGte, vectorstores.FilterLt, vectorstores.FilterLte:
+		if _, ok := vectorstores.FilterNumber(f.Value); !ok {
+			return nil, &vectorstores.UnsupportedFilterError{
+				Store: "qdrant", Operator: f.Operator, Reason: "only numbers can be compared",
+			}
+		}
+		return map[string]any{"key": f.Key, "range": map[string]any{string(f.Operator): f.Value}}, nil
+	case vectorstores.FilterExists:
+		return map[string]any{"must_not": []any{map[string]any{"is_empty": map[string]any{"key": f.Key}}}}, nil
+	default:
+		return nil, &vectorstores.UnsupportedFilterError{Store: "qdrant", Operator: f.Operator}
+	}
+}
+
+// matchCondition matches a payload value. Qdrant only matches keywords,
+// integers and booleans exactly, so other numbers use a closed range.
+func matchCondition(key string, value any) map[string]any {
+	if n, ok := vectorstores.FilterNumber(value); ok && n != math.Trunc(n) {
+		return map[string]any{"key": key, "range": map[string]any{"gte": n, "lte": n}}
+	}
+	return map[string]any{"key": key, "match": map[string]any{"value": value}}
+}
+
+// anyCondition matches one of values, with a single match if they are all
+// keywords or all integers.
+func anyCondition(key string, values []any) map[string]any {
+	var keywords, integers int
+	for _, v := range values {
+		if n, ok := vectorstores.FilterNumber(v); ok && n == math.Trunc(n) {
+			integers++
+		} else if _, ok := v.(string); ok {
+			keywords++
+		}
+	}
+	if keywords == len(values) || integers == len(values) {
+		return map[string]any{"key": key, "match": map[string]any{"any": values}}
+	}
+
+	conditions := make([]any, 0, len(values))
+	for _, v := range values {
+		conditions = append(conditions, matchCondition(key, v))
+	}
+	return map[string]any{"should": conditions}
+}
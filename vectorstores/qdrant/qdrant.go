@@ -8,6 +8,8 @@ import (
 	"github.com/sayerxofficial/langchaingo/embeddings"
 	"github.com/sayerxofficial/langchaingo/schema"
 	"github.com/sayerxofficial/langchaingo/vectorstores"
+
+	"github.com/google/uuid"
 )
 
 type Store struct {
@@ -18,7 +20,15 @@ type Store struct {
 	contentKey     string
 }
 
-var _ vectorstores.VectorStore = Store{}
+var (
+	_ vectorstores.VectorStore   = Store{}
+	_ vectorstores.Deleter       = Store{}
+	_ vectorstores.FilterDeleter = Store{}
+	_ vectorstores.Upserter      = Store{}
+
+	_ vectorstores.EmbeddingSearcher            = Store{}
+	_ vectorstores.MaxMarginalRelevanceSearcher = Store{}
+)
 
 func New(opts ...Option) (Store, error) {
 	s, err := applyClientOptions(opts...)
@@ -32,6 +42,64 @@ func (s Store) AddDocuments(ctx context.Context,
 	docs []schema.Document,
 	_ ...vectorstores.Option,
 ) ([]string, error) {
+	ids := make([]string, len(docs))
+	for i := range ids {
+		ids[i] = uuid.NewString()
+	}
+	return s.upsertDocuments(ctx, ids, docs)
+}
+
+// UpsertDocuments adds documents to the collection under the given ids,
+// replacing the points already stored with the same ids. Ids that are not
+// UUIDs are mapped to a UUID derived from them, since Qdrant only accepts
+// UUIDs and integers as point ids.
+func (s Store) UpsertDocuments(ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	_ ...vectorstores.Option,
+) ([]string, error) {
+	if len(ids) != len(docs) {
+		return nil, vectorstores.ErrMismatchedIDs
+	}
+
+	pointIDs := make([]string, len(ids))
+	for i, id := range ids {
+		pointIDs[i] = vectorstores.StableUUID("", id)
+	}
+	return s.upsertDocuments(ctx, pointIDs, docs)
+}
+
+// Delete removes the points with the given ids from the collection.
+func (s Store) Delete(ctx context.Context, ids []string, _ ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	pointIDs := make([]string, len(ids))
+	for i, id := range ids {
+		pointIDs[i] = vectorstores.StableUUID("", id)
+	}
+	return s.deletePoints(ctx, &s.qdrantURL, deleteBody{Points: pointIDs})
+}
+
+// DeleteByFilter removes the points matching the filter from the collection.
+// The filter is a Qdrant filter or a vectorstores.Filter, like the ones given
+// to WithFilters.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, _ ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrEmptyFilter
+	}
+	if m, ok := filter.(map[string]any); ok && len(m) == 0 {
+		return vectorstores.ErrEmptyFilter
+	}
+	condition, err := filterCondition(filter)
+	if err != nil {
+		return err
+	}
+	return s.deletePoints(ctx, &s.qdrantURL, deleteBody{Filter: condition})
+}
+
+func (s Store) upsertDocuments(ctx context.Context, ids []string, docs []schema.Document) ([]string, error) {
 	if len(docs) == 0 {
 		return []string{}, nil
 	}
@@ -62,16 +130,48 @@ func (s Store) AddDocuments(ctx context.Context,
 		metadatas = append(metadatas, metadata)
 	}
 
-	return s.upsertPoints(ctx, &s.qdrantURL, vectors, metadatas)
+	return s.upsertPoints(ctx, &s.qdrantURL, ids, vectors, metadatas)
 }
 
 func (s Store) SimilaritySearch(ctx context.Context,
 	query string, numDocuments int,
 	options ...vectorstores.Option,
 ) ([]schema.Document, error) {
+	result, err := s.similaritySearch(ctx, query, numDocuments, false, options...)
+	if err != nil {
+		return nil, err
+	}
+	return result.Documents, nil
+}
+
+// SimilaritySearchWithEmbeddings performs a similarity search like
+// SimilaritySearch, and also returns the vectors of the query and of the
+// points found, for callers that select among the results themselves, for
+// example with vectorstores.MaxMarginalRelevance.
+func (s Store) SimilaritySearchWithEmbeddings(ctx context.Context,
+	query string, numDocuments int,
+	options ...vectorstores.Option,
+) (vectorstores.EmbeddingSearchResult, error) {
+	return s.similaritySearch(ctx, query, numDocuments, true, options...)
+}
+
+// MaxMarginalRelevanceSearch searches with the maximal marginal relevance of
+// the Qdrant query API, available since Qdrant 1.15: the fetchK points most
+// similar to the query are the candidates, and the diversity of the query is
+// 1 - lambda. See vectorstores.MaxMarginalRelevanceSearch.
+func (s Store) MaxMarginalRelevanceSearch(ctx context.Context,
+	query string, k, fetchK int, lambda float32,
+	options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	if lambda < 0 || lambda > 1 {
+		return nil, vectorstores.ErrInvalidLambda
+	}
 	opts := s.getOptions(options...)
 
-	filters := s.getFilters(opts)
+	filters, err := filterCondition(s.getFilters(opts))
+	if err != nil {
+		return nil, err
+	}
 
 	scoreThreshold,
 		err := s.getScoreThreshold(opts)
@@ -85,7 +185,45 @@ func (s Store) SimilaritySearch(ctx context.Context,
 		return nil, err
 	}
 
-	return s.searchPoints(ctx, &s.qdrantURL, vector, numDocuments, scoreThreshold, filters)
+	return s.queryPoints(ctx, &s.qdrantURL, nearestQuery{
+		Nearest: vector,
+		MMR:     &mmrParams{Diversity: 1 - lambda, CandidatesLimit: max(fetchK, k)},
+	}, k, scoreThreshold, filters)
+}
+
+func (s Store) similaritySearch(ctx context.Context,
+	query string, numDocuments int,
+	withVectors bool,
+	options ...vectorstores.Option,
+) (vectorstores.EmbeddingSearchResult, error) {
+	var result vectorstores.EmbeddingSearchResult
+	opts := s.getOptions(options...)
+
+	filters, err := filterCondition(s.getFilters(opts))
+	if err != nil {
+		return result, err
+	}
+
+	scoreThreshold,
+		err := s.getScoreThreshold(opts)
+	if err != nil {
+		return result, err
+	}
+
+	vector,
+		err := s.embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return result, err
+	}
+
+	docs, vectors, err := s.searchPoints(ctx, &s.qdrantURL, vector, numDocuments, scoreThreshold, filters, withVectors)
+	if err != nil {
+		return result, err
+	}
+	result.Query = vector
+	result.Documents = docs
+	result.Embeddings = vectors
+	return result, nil
 }
 
 func (s Store) getScoreThreshold(opts vectorstores.Options) (float32, error) {
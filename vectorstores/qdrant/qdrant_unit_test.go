@@ -888,3 +888,218 @@ func TestDoRequest_Unit(t *testing.T) { //nolint:funlen // comprehensive test
 		})
 	}
 }
+
+func TestStore_DeleteAndUpsert_Unit(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	var (
+		paths   []string
+		deletes []map[string]any
+		upserts []upsertBody
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/delete") {
+			var req map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			deletes = append(deletes, req)
+		} else {
+			var req upsertBody
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			upserts = append(upserts, req)
+		}
+		w.WriteHeader(http.StatusOK)
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{"status": "ok"}))
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	store, err := New(
+		WithURL(*serverURL),
+		WithCollectionName("test-collection"),
+		WithEmbedder(&testEmbedder{}),
+	)
+	require.NoError(t, err)
+
+	const existing = "5c56c793-69f3-4fbf-87e6-c4bf54c28c26"
+	ids, err := store.UpsertDocuments(ctx, []string{existing, "a.md#0"}, []schema.Document{
+		{PageContent: "doc1"},
+		{PageContent: "doc2"},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	assert.Equal(t, existing, ids[0])
+	assert.Equal(t, vectorstores.StableUUID("", "a.md#0"), ids[1])
+	assert.Equal(t, ids, upserts[0].Batch.IDs)
+
+	_, err = store.UpsertDocuments(ctx, []string{"a.md#0"}, nil)
+	require.ErrorIs(t, err, vectorstores.ErrMismatchedIDs)
+
+	require.NoError(t, store.Delete(ctx, []string{"a.md#0"}))
+	filter := map[string]any{"must": []any{map[string]any{"key": "source", "match": map[string]any{"value": "a.md"}}}}
+	require.NoError(t, store.DeleteByFilter(ctx, filter))
+	require.ErrorIs(t, store.DeleteByFilter(ctx, nil), vectorstores.ErrEmptyFilter)
+
+	assert.Equal(t, []string{
+		"PUT /collections/test-collection/points",
+		"POST /collections/test-collection/points/delete",
+		"POST /collections/test-collection/points/delete",
+	}, paths)
+	assert.Equal(t, []any{ids[1]}, deletes[0]["points"])
+	assert.Equal(t, filter["must"], deletes[1]["filter"].(map[string]any)["must"])
+	assert.NotContains(t, deletes[1], "points")
+}
+
+func TestFilterCondition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filter   any
+		expected string
+	}{
+		{
+			name:     "native filter",
+			filter:   map[string]any{"must": []any{}},
+			expected: `{"must":[]}`,
+		},
+		{
+			name:     "eq",
+			filter:   vectorstores.Eq("source", "a.md"),
+			expected: `{"must":[{"key":"source","match":{"value":"a.md"}}]}`,
+		},
+		{
+			name:     "eq float",
+			filter:   vectorstores.Eq("score", 0.5),
+			expected: `{"must":[{"key":"score","range":{"gte":0.5,"lte":0.5}}]}`,
+		},
+		{
+			name:     "ne",
+			filter:   vectorstores.Ne("source", "a.md"),
+			expected: `{"must":[{"must_not":[{"key":"source","match":{"value":"a.md"}}]}]}`,
+		},
+		{
+			name:     "in",
+			filter:   vectorstores.In("year", 2023, 2024),
+			expected: `{"must":[{"key":"year","match":{"any":[2023,2024]}}]}`,
+		},
+		{
+			name:   "nin mixed types",
+			filter: vectorstores.Nin("year", "2023", 2024),
+			expected: `{"must":[{"must_not":[{"should":[` +
+				`{"key":"year","match":{"value":"2023"}},{"key":"year","match":{"value":2024}}]}]}]}`,
+		},
+		{
+			name: "and or not",
+			filter: vectorstores.And(
+				vectorstores.Gte("year", 2020),
+				vectorstores.Or(vectorstores.Exists("draft"), vectorstores.Not(vectorstores.Contains("tags", "go"))),
+			),
+			expected: `{"must":[{"must":[{"key":"year","range":{"gte":2020}},{"should":[` +
+				`{"must_not":[{"is_empty":{"key":"draft"}}]},{"must_not":[{"key":"tags","match":{"value":"go"}}]}]}]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			condition, err := filterCondition(tt.filter)
+			require.NoError(t, err)
+			data, err := json.Marshal(condition)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(data))
+		})
+	}
+
+	_, err := filterCondition(vectorstores.Lt("source", "b"))
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+	_, err = filterCondition(vectorstores.In("year"))
+	require.ErrorIs(t, err, vectorstores.ErrInvalidFilter)
+}
+
+func TestStore_SimilaritySearchWithEmbeddings_Unit(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req searchBody
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.True(t, req.WithVector)
+		assert.Equal(t, 3, req.Limit)
+
+		w.WriteHeader(http.StatusOK)
+		assert.NoError(t, json.NewEncoder(w).Encode(searchResponse{
+			Result: []result{
+				{Score: 1, Payload: map[string]any{"content": "tokyo"}, Vector: []float32{1, 0}},
+				{Score: 1, Payload: map[string]any{"content": "tokyo again"}, Vector: []float32{1, 0}},
+				{Score: 0.7, Payload: map[string]any{"content": "kyoto"}, Vector: []float32{0.7, 0.7}},
+			},
+		}))
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	store, err := New(
+		WithURL(*serverURL),
+		WithCollectionName("test-collection"),
+		WithEmbedder(&testEmbedder{embedFn: func(context.Context, []string) ([][]float32, error) {
+			return [][]float32{{1, 0}}, nil
+		}}),
+	)
+	require.NoError(t, err)
+
+	result, err := store.SimilaritySearchWithEmbeddings(ctx, "tokyo", 3)
+	require.NoError(t, err)
+	assert.Equal(t, []float32{1, 0}, result.Query)
+	require.Len(t, result.Documents, 3)
+	assert.Equal(t, "kyoto", result.Documents[2].PageContent)
+	assert.Equal(t, [][]float32{{1, 0}, {1, 0}, {0.7, 0.7}}, result.Embeddings)
+}
+
+func TestStore_MaxMarginalRelevanceSearch_Unit(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/collections/test-collection/points/query", r.URL.Path)
+
+		var req queryBody
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, []float32{1, 0}, req.Query.Nearest)
+		assert.Equal(t, &mmrParams{Diversity: 0.75, CandidatesLimit: 3}, req.Query.MMR)
+		assert.Equal(t, 2, req.Limit)
+		assert.True(t, req.WithPayload)
+
+		var response queryResponse
+		response.Result.Points = []result{
+			{Score: 1, Payload: map[string]any{"content": "tokyo"}},
+			{Score: 0.7, Payload: map[string]any{"content": "kyoto"}},
+		}
+		w.WriteHeader(http.StatusOK)
+		assert.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	store, err := New(
+		WithURL(*serverURL),
+		WithCollectionName("test-collection"),
+		WithEmbedder(&testEmbedder{embedFn: func(context.Context, []string) ([][]float32, error) {
+			return [][]float32{{1, 0}}, nil
+		}}),
+	)
+	require.NoError(t, err)
+
+	docs, err := vectorstores.MaxMarginalRelevanceSearch(ctx, store, "tokyo", 2, 3, 0.25)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, "tokyo", docs[0].PageContent)
+	assert.Equal(t, "kyoto", docs[1].PageContent)
+
+	_, err = store.MaxMarginalRelevanceSearch(ctx, "tokyo", 2, 3, 2)
+	require.ErrorIs(t, err, vectorstores.ErrInvalidLambda)
+}
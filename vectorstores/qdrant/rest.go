@@ -11,22 +11,16 @@ import (
 
 	"github.com/sayerxofficial/langchaingo/httputil"
 	"github.com/sayerxofficial/langchaingo/schema"
-
-	"github.com/google/uuid"
 )
 
 // upsertPoints updates or inserts points into the Qdrant collection.
 func (s Store) upsertPoints(
 	ctx context.Context,
 	baseURL *url.URL,
+	ids []string,
 	vectors [][]float32,
 	payloads []map[string]interface{},
 ) ([]string, error) {
-	ids := make([]string, len(vectors))
-	for i := range ids {
-		ids[i] = uuid.NewString()
-	}
-
 	payload := upsertBody{
 		Batch: upsertBatch{
 			IDs:      ids,
@@ -57,7 +51,36 @@ func (s Store) upsertPoints(
 		newAPIError("upserting vectors", body)
 }
 
+// deletePoints deletes the points selected by ids or by a filter from the
+// Qdrant collection.
+func (s Store) deletePoints(
+	ctx context.Context,
+	baseURL *url.URL,
+	payload deleteBody,
+) error {
+	url := baseURL.JoinPath("collections", s.collectionName, "points", "delete")
+	body,
+		status,
+		err := DoRequest(
+		ctx, *url,
+		s.apiKey,
+		http.MethodPost,
+		payload,
+	)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if status == http.StatusOK {
+		return nil
+	}
+
+	return newAPIError("deleting points", body)
+}
+
 // searchPoints queries the Qdrant collection for points based on the provided parameters.
+// The vectors of the points are returned too if withVector is set.
 func (s Store) searchPoints(
 	ctx context.Context,
 	baseURL *url.URL,
@@ -65,10 +88,11 @@ func (s Store) searchPoints(
 	numVectors int,
 	scoreThreshold float32,
 	filter any,
-) ([]schema.Document, error) {
+	withVector bool,
+) ([]schema.Document, [][]float32, error) {
 	payload := searchBody{
 		WithPayload: true,
-		WithVector:  false,
+		WithVector:  withVector,
 		Vector:      vector,
 		Limit:       numVectors,
 		Filter:      filter,
@@ -88,12 +112,12 @@ func (s Store) searchPoints(
 		payload,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer body.Close()
 
 	if statusCode != http.StatusOK {
-		return nil, newAPIError("querying collection", body)
+		return nil, nil, newAPIError("querying collection", body)
 	}
 
 	var response searchResponse
@@ -101,13 +125,67 @@ func (s Store) searchPoints(
 	decoder := json.NewDecoder(body)
 	err = decoder.Decode(&response)
 	if err != nil {
+		return nil, nil, err
+	}
+	return s.toDocuments(response.Result, withVector)
+}
+
+// queryPoints queries the Qdrant collection with the query API, which
+// selects the points by maximal marginal relevance when mmr is set.
+func (s Store) queryPoints(
+	ctx context.Context,
+	baseURL *url.URL,
+	query nearestQuery,
+	numVectors int,
+	scoreThreshold float32,
+	filter any,
+) ([]schema.Document, error) {
+	payload := queryBody{
+		Query:          query,
+		Filter:         filter,
+		Limit:          numVectors,
+		ScoreThreshold: scoreThreshold,
+		WithPayload:    true,
+	}
+
+	url := baseURL.JoinPath("collections", s.collectionName, "points", "query")
+	body,
+		statusCode,
+		err := DoRequest(
+		ctx, *url,
+		s.apiKey,
+		http.MethodPost,
+		payload,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if statusCode != http.StatusOK {
+		return nil, newAPIError("querying collection", body)
+	}
+
+	var response queryResponse
+	if err := json.NewDecoder(body).Decode(&response); err != nil {
 		return nil, err
 	}
-	docs := make([]schema.Document, len(response.Result))
-	for i, match := range response.Result {
+	docs, _, err := s.toDocuments(response.Result.Points, false)
+	return docs, err
+}
+
+// toDocuments turns the points found into documents, and returns their
+// vectors too if withVector is set.
+func (s Store) toDocuments(points []result, withVector bool) ([]schema.Document, [][]float32, error) {
+	docs := make([]schema.Document, len(points))
+	var vectors [][]float32
+	if withVector {
+		vectors = make([][]float32, len(points))
+	}
+	for i, match := range points {
 		pageContent, ok := match.Payload[s.contentKey].(string)
 		if !ok {
-			return nil, fmt.Errorf("payload does not contain content key '%s'", s.contentKey)
+			return nil, nil, fmt.Errorf("payload does not contain content key '%s'", s.contentKey)
 		}
 		delete(match.Payload, s.contentKey)
 
@@ -118,9 +196,12 @@ func (s Store) searchPoints(
 		}
 
 		docs[i] = doc
+		if withVector {
+			vectors[i] = match.Vector
+		}
 	}
 
-	return docs, nil
+	return docs, vectors, nil
 }
 
 // doRequest performs an HTTP request to the Qdrant API.
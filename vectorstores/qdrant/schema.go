@@ -15,9 +15,15 @@ type upsertBody struct {
 	Batch upsertBatch `json:"batch"`
 }
 
+type deleteBody struct {
+	Points []string `json:"points,omitempty"`
+	Filter any      `json:"filter,omitempty"`
+}
+
 type result struct {
 	Score   float32                `json:"score"`
 	Payload map[string]interface{} `json:"payload"`
+	Vector  []float32              `json:"vector"`
 }
 
 type searchResponse struct {
@@ -32,3 +38,27 @@ type searchBody struct {
 	WithVector     bool      `json:"with_vector"`
 	WithPayload    bool      `json:"with_payload"`
 }
+
+type mmrParams struct {
+	Diversity       float32 `json:"diversity"`
+	CandidatesLimit int     `json:"candidates_limit"`
+}
+
+type nearestQuery struct {
+	Nearest []float32  `json:"nearest"`
+	MMR     *mmrParams `json:"mmr,omitempty"`
+}
+
+type queryBody struct {
+	Query          nearestQuery `json:"query"`
+	Filter         any          `json:"filter"`
+	Limit          int          `json:"limit"`
+	ScoreThreshold float32      `json:"score_threshold"`
+	WithPayload    bool         `json:"with_payload"`
+}
+
+type queryResponse struct {
+	Result struct {
+		Points []result `json:"points"`
+	} `json:"result"`
+}
@@ -0,0 +1,168 @@
+package redisvector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+)
+
+// fieldKind is the type of an index field, as used in FT.CREATE.
+type fieldKind string
+
+const (
+	tagKind     fieldKind = "TAG"
+	textKind    fieldKind = "TEXT"
+	numericKind fieldKind = "NUMERIC"
+)
+
+// fieldKind returns the type of the field named name, or an empty string if
+// the schema has no such field.
+func (s *IndexSchema) fieldKind(name string) fieldKind {
+	if s == nil {
+		return ""
+	}
+	for _, f := range s.Tag {
+		if f.Name == name || f.As == name {
+			return tagKind
+		}
+	}
+	for _, f := range s.Text {
+		if f.Name == name || f.As == name {
+			return textKind
+		}
+	}
+	for _, f := range s.Numeric {
+		if f.Name == name || f.As == name {
+			return numericKind
+		}
+	}
+	return ""
+}
+
+// queryFilter translates a vectorstores.Filter into a redis search query.
+// Values are matched according to the type of their field in the index
+// schema. Fields missing from the schema are numeric for numbers, and text
+// otherwise like the fields of a schema generated from metadata. Text fields
+// are full-text indexed, so they only match words and can't be compared for
+// equality: filter on tag fields instead.
+type queryFilter struct {
+	schema *IndexSchema
+}
+
+//nolint:cyclop
+func (q queryFilter) translate(f vectorstores.Filter) (string, error) {
+	switch f.Operator {
+	case vectorstores.FilterAnd, vectorstores.FilterOr, vectorstores.FilterNot:
+		queries := make([]string, 0, len(f.Filters))
+		for _, operand := range f.Filters {
+			query, err := q.translate(operand)
+			if err != nil {
+				return "", err
+			}
+			queries = append(queries, query)
+		}
+		switch f.Operator { //nolint:exhaustive
+		case vectorstores.FilterAnd:
+			return "(" + strings.Join(queries, " ") + ")", nil
+		case vectorstores.FilterOr:
+			return "(" + strings.Join(queries, " | ") + ")", nil
+		default:
+			return "-" + queries[0], nil
+		}
+	case vectorstores.FilterEq:
+		return q.match(f.Operator, f.Key, []any{f.Value})
+	case vectorstores.FilterNe:
+		query, err := q.match(f.Operator, f.Key, []any{f.Value})
+		return "-" + query, err
+	case vectorstores.FilterIn:
+		return q.match(f.Operator, f.Key, f.Values())
+	case vectorstores.FilterNin:
+		query, err := q.match(f.Operator, f.Key, f.Values())
+		return "-" + query, err
+	case vectorstores.FilterGt, vectorstores.FilterGte, vectorstores.FilterLt, vectorstores.FilterLte:
+		n, ok := vectorstores.FilterNumber(f.Value)
+		if !ok || q.kind(f.Key, f.Value) != numericKind {
+			return "", &vectorstores.UnsupportedFilterError{
+				Store: "redis", Operator: f.Operator, Reason: "only numeric fields can be compared",
+			}
+		}
+		value := formatNumber(n)
+		ranges := map[vectorstores.FilterOperator]string{
+			vectorstores.FilterGt:  "(" + value + " +inf",
+			vectorstores.FilterGte: value + " +inf",
+			vectorstores.FilterLt:  "-inf (" + value,
+			vectorstores.FilterLte: "-inf " + value,
+		}
+		return fmt.Sprintf("@%s:[%s]", escape(f.Key), ranges[f.Operator]), nil
+	case vectorstores.FilterContains:
+		if q.schema.fieldKind(f.Key) != tagKind {
+			return "", &vectorstores.UnsupportedFilterError{
+				Store: "redis", Operator: f.Operator, Reason: "only tag fields hold lists",
+			}
+		}
+		return q.match(f.Operator, f.Key, []any{f.Value})
+	default:
+		return "", &vectorstores.UnsupportedFilterError{Store: "redis", Operator: f.Operator}
+	}
+}
+
+// match returns a query matching documents whose field key has one of values.
+func (q queryFilter) match(op vectorstores.FilterOperator, key string, values []any) (string, error) {
+	field := escape(key)
+	switch q.kind(key, values[0]) {
+	case tagKind:
+		tags := make([]string, 0, len(values))
+		for _, v := range values {
+			tags = append(tags, escape(fmt.Sprint(v)))
+		}
+		return fmt.Sprintf("@%s:{%s}", field, strings.Join(tags, " | ")), nil
+	case numericKind:
+		ranges := make([]string, 0, len(values))
+		for _, v := range values {
+			n, ok := vectorstores.FilterNumber(v)
+			if !ok {
+				return "", &vectorstores.UnsupportedFilterError{
+					Store: "redis", Operator: op, Reason: "numeric fields only match numbers",
+				}
+			}
+			ranges = append(ranges, fmt.Sprintf("@%s:[%s %s]", field, formatNumber(n), formatNumber(n)))
+		}
+		if len(ranges) == 1 {
+			return ranges[0], nil
+		}
+		return "(" + strings.Join(ranges, " | ") + ")", nil
+	default:
+		return "", &vectorstores.UnsupportedFilterError{
+			Store: "redis", Operator: op, Reason: "text fields only match words, index " + key + " as a tag field",
+		}
+	}
+}
+
+func (q queryFilter) kind(key string, value any) fieldKind {
+	if kind := q.schema.fieldKind(key); kind != "" {
+		return kind
+	}
+	if _, ok := vectorstores.FilterNumber(value); ok {
+		return numericKind
+	}
+	return textKind
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// escape escapes the characters that have a meaning in a query, in tags and
+// field names.
+func escape(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(",.<>{}[]\"':;!@#$%^&*()-+=~|/\\ ", r) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
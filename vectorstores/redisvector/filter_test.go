@@ -0,0 +1,65 @@
+package redisvector
+
+import (
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFiltersTranslatesFilter(t *testing.T) {
+	t.Parallel()
+
+	s := Store{indexSchema: &IndexSchema{
+		Tag:     []TagField{{Name: "tags"}, {Name: "doc-type"}},
+		Text:    []TextField{{Name: "title"}},
+		Numeric: []NumericField{{Name: "year"}},
+	}}
+
+	tests := []struct {
+		name   string
+		filter vectorstores.Filter
+		query  string
+	}{
+		{"tag", vectorstores.Eq("doc-type", "e-book"), `@doc\-type:{e\-book}`},
+		{"numeric", vectorstores.Ne("year", 1965), `-@year:[1965 1965]`},
+		{"tag in", vectorstores.In("tags", "sci-fi", "novel"), `@tags:{sci\-fi | novel}`},
+		{"numeric in", vectorstores.Nin("year", 1965, 1.5), `-(@year:[1965 1965] | @year:[1.5 1.5])`},
+		{"range", vectorstores.Gt("year", 1960), `@year:[(1960 +inf]`},
+		{"contains", vectorstores.Contains("tags", "novel"), `@tags:{novel}`},
+		{"unknown numeric field", vectorstores.Lte("pages", 400), `@pages:[-inf 400]`},
+		{
+			"and or not",
+			vectorstores.And(
+				vectorstores.Lt("year", 2000),
+				vectorstores.Or(vectorstores.Eq("doc-type", "pdf"), vectorstores.Not(vectorstores.Eq("tags", "novel"))),
+			),
+			`(@year:[-inf (2000] (@doc\-type:{pdf} | -@tags:{novel}))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			query, err := s.getFilters(vectorstores.Options{Filters: tt.filter})
+			require.NoError(t, err)
+			assert.Equal(t, tt.query, query)
+		})
+	}
+
+	_, err := s.getFilters(vectorstores.Options{Filters: vectorstores.Exists("title")})
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+	_, err = s.getFilters(vectorstores.Options{Filters: vectorstores.Gt("title", "A")})
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+	_, err = s.getFilters(vectorstores.Options{Filters: vectorstores.Contains("title", "Dune")})
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+
+	// Text fields, and string values of fields missing from the schema, are
+	// full-text indexed and can't be matched exactly.
+	_, err = s.getFilters(vectorstores.Options{Filters: vectorstores.Eq("title", "Dune")})
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+	_, err = s.getFilters(vectorstores.Options{Filters: vectorstores.In("author", "Herbert", "Asimov")})
+	require.ErrorIs(t, err, vectorstores.ErrUnsupportedFilter)
+}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/sayerxofficial/langchaingo/schema"
 
@@ -22,6 +23,9 @@ type RedisClient interface {
 	CreateIndexIfNotExists(ctx context.Context, index string, schema *IndexSchema) error
 	AddDocWithHash(ctx context.Context, prefix string, doc schema.Document) (string, error)
 	AddDocsWithHash(ctx context.Context, prefix string, docs []schema.Document) ([]string, error)
+	UpsertDocsWithHash(ctx context.Context, docIDs []string, docs []schema.Document) error
+	DeleteDocs(ctx context.Context, docIDs []string) error
+	SearchDocIDs(ctx context.Context, index, query string, limit int) ([]string, error)
 	// TODO AddDocsWithJSON
 	Search(ctx context.Context, search IndexVectorSearch) (int64, []schema.Document, error)
 }
@@ -99,6 +103,47 @@ func (c RueidisClient) AddDocsWithHash(ctx context.Context, prefix string, docs
 	return docIDs, errors.Join(errs...)
 }
 
+// UpsertDocsWithHash saves the documents under the given keys, replacing the
+// hashes already stored under them.
+func (c RueidisClient) UpsertDocsWithHash(ctx context.Context, docIDs []string, docs []schema.Document) error {
+	cmds := make([]rueidis.Completed, 0, len(docs)*2)
+	for i, doc := range docs {
+		cmds = append(cmds, c.client.B().Del().Key(docIDs[i]).Build(), c.hsetCMD(docIDs[i], doc))
+	}
+	errs := make([]error, 0, len(docs))
+	for _, res := range c.client.DoMulti(ctx, cmds...) {
+		if res.Error() != nil {
+			errs = append(errs, res.Error())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DeleteDocs deletes the hashes stored under the given keys.
+func (c RueidisClient) DeleteDocs(ctx context.Context, docIDs []string) error {
+	if len(docIDs) == 0 {
+		return nil
+	}
+	return c.client.Do(ctx, c.client.B().Del().Key(docIDs...).Build()).Error()
+}
+
+// SearchDocIDs returns the keys of at most limit documents of the index
+// matching the query.
+func (c RueidisClient) SearchDocIDs(ctx context.Context, index, query string, limit int) ([]string, error) {
+	cmd := c.client.B().FtSearch().Index(index).Query(query).Nocontent().
+		Limit().OffsetNum(0, int64(limit)).Dialect(2).Build()
+	_, docs, err := c.client.Do(ctx, cmd).AsFtSearch()
+	if err != nil {
+		return nil, err
+	}
+
+	docIDs := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		docIDs = append(docIDs, doc.Key)
+	}
+	return docIDs, nil
+}
+
 func (c RueidisClient) Search(ctx context.Context, search IndexVectorSearch) (int64, []schema.Document, error) {
 	cmds := search.AsCommand()
 	// fmt.Println(strings.Join(cmds, " "))
@@ -111,6 +156,11 @@ func (c RueidisClient) Search(ctx context.Context, search IndexVectorSearch) (in
 }
 
 func (c RueidisClient) generateHSetCMD(prefix string, doc schema.Document) (string, rueidis.Completed) {
+	docID := getDocIDWithMetaData(prefix, doc.Metadata)
+	return docID, c.hsetCMD(docID, doc)
+}
+
+func (c RueidisClient) hsetCMD(docID string, doc schema.Document) rueidis.Completed {
 	kvs := make([]string, 0, len(doc.Metadata)*2)
 	for k, v := range doc.Metadata {
 		kvs = append(kvs, k)
@@ -127,8 +177,7 @@ func (c RueidisClient) generateHSetCMD(prefix string, doc schema.Document) (stri
 			kvs = append(kvs, fmt.Sprintf("%v", v))
 		}
 	}
-	docID := getDocIDWithMetaData(prefix, doc.Metadata)
-	return docID, c.client.B().Arbitrary("Hmset").Keys(docID).Args(kvs...).Build()
+	return c.client.B().Arbitrary("Hmset").Keys(docID).Args(kvs...).Build()
 }
 
 // getPrefix get prefix with index name.
@@ -151,6 +200,15 @@ func getDocIDWithMetaData(prefix string, meta map[string]any) string {
 	return fmt.Sprintf("%s:%v", prefix, uuid.New().String())
 }
 
+// getDocID returns the key of the document with the given id, which is
+// either a key returned by AddDocuments or an id to prefix.
+func getDocID(prefix, id string) string {
+	if strings.HasPrefix(id, prefix+":") {
+		return id
+	}
+	return fmt.Sprintf("%s:%s", prefix, id)
+}
+
 func convertFTSearchResIntoDocSchema(docs []rueidis.FtSearchDoc) []schema.Document {
 	res := make([]schema.Document, 0, len(docs))
 	for _, doc := range docs {
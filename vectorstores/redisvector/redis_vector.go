@@ -35,7 +35,15 @@ type Store struct {
 	schemaGenerator        *schemaGenerator
 }
 
-var _ vectorstores.VectorStore = &Store{}
+var (
+	_ vectorstores.VectorStore   = &Store{}
+	_ vectorstores.Deleter       = &Store{}
+	_ vectorstores.FilterDeleter = &Store{}
+	_ vectorstores.Upserter      = &Store{}
+)
+
+// deleteBatchSize is the number of documents DeleteByFilter deletes at once.
+const deleteBatchSize = 1000
 
 // New creates a new Store with options.
 func New(ctx context.Context, opts ...Option) (*Store, error) {
@@ -81,34 +89,94 @@ func (s *Store) AddDocuments(ctx context.Context, docs []schema.Document, _ ...v
 		return nil, err
 	}
 
-	indexSchema, err := generateSchemaWithMetadata(docs[0].Metadata)
+	if err := s.ensureIndex(ctx, docs); err != nil {
+		return nil, err
+	}
+
+	docIDs, err := s.client.AddDocsWithHash(ctx, getPrefix(s.indexName), docs)
 	if err != nil {
 		return nil, err
 	}
 
-	if s.indexSchema == nil {
-		s.indexSchema = indexSchema
+	return docIDs, nil
+}
+
+// UpsertDocuments saves the documents under the given ids, replacing the documents already
+// saved with the same ids, and returns their keys. An id is prefixed with `doc:{index_name}`
+// unless it already is, so the keys returned by AddDocuments can be given as ids.
+func (s *Store) UpsertDocuments(
+	ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	_ ...vectorstores.Option,
+) ([]string, error) {
+	if len(ids) != len(docs) {
+		return nil, vectorstores.ErrMismatchedIDs
+	}
+	if len(docs) == 0 {
+		return []string{}, nil
 	}
 
-	if s.createIndexIfNotExists && !s.client.CheckIndexExists(ctx, s.indexName) {
-		if err := s.client.CreateIndexIfNotExists(ctx, s.indexName, indexSchema); err != nil {
-			return nil, err
-		}
+	if err := s.appendDocumentsWithVectors(ctx, docs); err != nil {
+		return nil, err
 	}
 
-	docIDs, err := s.client.AddDocsWithHash(ctx, getPrefix(s.indexName), docs)
-	if err != nil {
+	if err := s.ensureIndex(ctx, docs); err != nil {
 		return nil, err
 	}
 
+	docIDs := make([]string, len(ids))
+	for i, id := range ids {
+		docIDs[i] = getDocID(getPrefix(s.indexName), id)
+	}
+	if err := s.client.UpsertDocsWithHash(ctx, docIDs, docs); err != nil {
+		return nil, err
+	}
 	return docIDs, nil
 }
 
+// Delete deletes the documents with the given ids, either keys returned by AddDocuments
+// or ids given to UpsertDocuments.
+func (s *Store) Delete(ctx context.Context, ids []string, _ ...vectorstores.Option) error {
+	docIDs := make([]string, len(ids))
+	for i, id := range ids {
+		docIDs[i] = getDocID(getPrefix(s.indexName), id)
+	}
+	return s.client.DeleteDocs(ctx, docIDs)
+}
+
+// DeleteByFilter deletes the documents matching the filter, a redis search query
+// (eg: @title:Dune) or a vectorstores.Filter like the ones given to WithFilters.
+func (s *Store) DeleteByFilter(ctx context.Context, filter any, _ ...vectorstores.Option) error {
+	query, err := s.getFilters(vectorstores.Options{Filters: filter})
+	if err != nil {
+		return err
+	}
+	if query == "" || query == "*" {
+		return vectorstores.ErrEmptyFilter
+	}
+
+	for {
+		docIDs, err := s.client.SearchDocIDs(ctx, s.indexName, query, deleteBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(docIDs) == 0 {
+			return nil
+		}
+		if err := s.client.DeleteDocs(ctx, docIDs); err != nil {
+			return err
+		}
+	}
+}
+
 // SimilaritySearch similarity search docs with `ScoreThreshold` `Filters` `Embedder`
 // Support options:
 //
 //	WithScoreThreshold:
 //	WithFilters: filter string should match redis search pre-filter query pattern.(eg: @title:Dune)
+//		or be a vectorstores.Filter, which is translated to such a query. Exact matches
+//		need tag or numeric fields: text fields are full-text indexed.
 //		ref: https://redis.io/docs/latest/develop/interact/search-and-query/advanced-concepts/vectors/#pre-filter-query-attributes-hybrid-approach
 //	WithEmbedder: if set, it will embed query string with this embedder; otherwise embed with vector's embedder
 //
@@ -179,8 +247,15 @@ func (s Store) getScoreThreshold(opts vectorstores.Options) (float32, error) {
 	return opts.ScoreThreshold, nil
 }
 
-// getFilters return metadata filters.
+// getFilters return metadata filters, as a redis search query or a
+// vectorstores.Filter translated to one.
 func (s Store) getFilters(opts vectorstores.Options) (string, error) {
+	if f, ok := vectorstores.AsFilter(opts.Filters); ok {
+		if err := f.Validate(); err != nil {
+			return "", err
+		}
+		return queryFilter{schema: s.indexSchema}.translate(f)
+	}
 	if opts.Filters != nil {
 		if filters, ok := opts.Filters.(string); ok {
 			return filters, nil
@@ -190,6 +265,26 @@ func (s Store) getFilters(opts vectorstores.Options) (string, error) {
 	return "", nil
 }
 
+// ensureIndex sets the index schema from the metadata of the documents if it
+// is not set yet, and creates the index if needed.
+func (s *Store) ensureIndex(ctx context.Context, docs []schema.Document) error {
+	indexSchema, err := generateSchemaWithMetadata(docs[0].Metadata)
+	if err != nil {
+		return err
+	}
+
+	if s.indexSchema == nil {
+		s.indexSchema = indexSchema
+	}
+
+	if s.createIndexIfNotExists && !s.client.CheckIndexExists(ctx, s.indexName) {
+		if err := s.client.CreateIndexIfNotExists(ctx, s.indexName, indexSchema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // append content & content_vector into doc.Metadata.
 func (s Store) appendDocumentsWithVectors(ctx context.Context, docs []schema.Document) error {
 	if len(docs) == 0 {
@@ -566,3 +566,66 @@ func createOpenAILLMAndEmbedder(t *testing.T) (*openai.LLM, *embeddings.Embedder
 	require.NoError(t, err)
 	return llm, e
 }
+
+// keywordEmbedder embeds texts by counting a few keywords, so that tests
+// that don't check relevance can run without an embedding API.
+type keywordEmbedder struct{}
+
+func (keywordEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = keywordEmbedder{}.EmbedQuery(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (keywordEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return []float32{
+		float32(strings.Count(text, "city")) + 0.1,
+		float32(strings.Count(text, "vegetable")) + 0.1,
+		float32(strings.Count(text, "fruit")) + 0.1,
+	}, nil
+}
+
+func TestDeleteAndUpsert(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	redisURL := getTestURIs(t)
+
+	index := "test_delete_and_upsert"
+	vector, err := redisvector.New(ctx,
+		redisvector.WithConnectionURL(redisURL),
+		redisvector.WithIndexName(index, true),
+		redisvector.WithEmbedder(keywordEmbedder{}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, vector.DropIndex(context.Background(), index, true)) //nolint:usetesting
+	})
+
+	docIDs, err := vector.UpsertDocuments(ctx, []string{"a", "b", "c"}, []schema.Document{
+		{PageContent: "tokyo is a city", Metadata: map[string]any{"source": "cities"}},
+		{PageContent: "potato is a vegetable", Metadata: map[string]any{"source": "food"}},
+		{PageContent: "apple is a fruit", Metadata: map[string]any{"source": "food"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "doc:"+index+":a", docIDs[0])
+
+	_, err = vector.UpsertDocuments(ctx, docIDs[:1], []schema.Document{
+		{PageContent: "kyoto is a city", Metadata: map[string]any{"source": "cities"}},
+	})
+	require.NoError(t, err)
+
+	docs, err := vector.SimilaritySearch(ctx, "city", 3)
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+	assert.Equal(t, "kyoto is a city", docs[0].PageContent)
+
+	require.NoError(t, vector.DeleteByFilter(ctx, "@source:food"))
+	require.NoError(t, vector.Delete(ctx, []string{"a"}))
+
+	docs, err = vector.SimilaritySearch(ctx, "city", 3)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+}
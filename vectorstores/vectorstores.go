@@ -2,9 +2,12 @@ package vectorstores
 
 import (
 	"context"
+	"errors"
 
 	"github.com/sayerxofficial/langchaingo/callbacks"
 	"github.com/sayerxofficial/langchaingo/schema"
+
+	"github.com/google/uuid"
 )
 
 // VectorStore is the interface for saving and querying documents in the
@@ -14,12 +17,66 @@ type VectorStore interface {
 	SimilaritySearch(ctx context.Context, query string, numDocuments int, options ...Option) ([]schema.Document, error) //nolint:lll
 }
 
+var (
+	// ErrMismatchedIDs is returned by UpsertDocuments when the number of ids
+	// does not match the number of documents.
+	ErrMismatchedIDs = errors.New("number of ids does not match number of documents")
+	// ErrEmptyFilter is returned by DeleteByFilter when the filter is empty,
+	// so that a missing filter never deletes a whole collection.
+	ErrEmptyFilter = errors.New("filter must not be empty")
+)
+
+// Deleter is implemented by vector stores that can delete documents by the
+// ids returned from AddDocuments or given to UpsertDocuments. Ids that are
+// not in the store are ignored.
+type Deleter interface {
+	Delete(ctx context.Context, ids []string, options ...Option) error
+}
+
+// FilterDeleter is implemented by vector stores that can delete every
+// document matching a metadata filter. The filter has the same format as the
+// one given to WithFilters for the same store.
+type FilterDeleter interface {
+	DeleteByFilter(ctx context.Context, filter any, options ...Option) error
+}
+
+// Upserter is implemented by vector stores that can store documents under
+// caller-supplied ids, replacing the documents already stored with the same
+// ids. This is useful to re-index the chunks of a source that changed. The
+// Deduplicater option is not applied, and the ids the documents were stored
+// under are returned. Stores that only accept UUIDs derive a stable UUID from
+// ids that are not one with StableUUID.
+type Upserter interface {
+	UpsertDocuments(ctx context.Context, ids []string, docs []schema.Document, options ...Option) ([]string, error)
+}
+
+// StableUUID returns id if it is a UUID, and a UUID derived from scope and id
+// otherwise. Stores that only accept UUIDs use it for the ids given to
+// UpsertDocuments and Delete, with the collection or name space the documents
+// are stored in as scope, so that the same id names different documents in
+// different collections.
+func StableUUID(scope, id string) string {
+	if parsed, err := uuid.Parse(id); err == nil {
+		return parsed.String()
+	}
+	space := uuid.NameSpaceURL
+	if scope != "" {
+		space = uuid.NewSHA1(space, []byte(scope))
+	}
+	return uuid.NewSHA1(space, []byte(id)).String()
+}
+
 // Retriever is a retriever for vector stores.
 type Retriever struct {
 	CallbacksHandler callbacks.Handler
 	v                VectorStore
 	numDocs          int
 	options          []Option
+
+	// mmr is set by ToMMRRetriever to search with maximal marginal relevance.
+	mmr    bool
+	fetchK int
+	lambda float32
 }
 
 var _ schema.Retriever = Retriever{}
@@ -30,7 +87,13 @@ func (r Retriever) GetRelevantDocuments(ctx context.Context, query string) ([]sc
 		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
 	}
 
-	docs, err := r.v.SimilaritySearch(ctx, query, r.numDocs, r.options...)
+	var docs []schema.Document
+	var err error
+	if r.mmr {
+		docs, err = MaxMarginalRelevanceSearch(ctx, r.v, query, r.numDocs, r.fetchK, r.lambda, r.options...)
+	} else {
+		docs, err = r.v.SimilaritySearch(ctx, query, r.numDocs, r.options...)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -51,3 +114,17 @@ func ToRetriever(vectorStore VectorStore, numDocuments int, options ...Option) R
 		options: options,
 	}
 }
+
+// ToMMRRetriever takes a vector store and returns a retriever using maximal
+// marginal relevance search to retrieve numDocuments documents out of the
+// fetchK most similar ones. See MaxMarginalRelevanceSearch.
+func ToMMRRetriever(vectorStore VectorStore, numDocuments, fetchK int, lambda float32, options ...Option) Retriever {
+	return Retriever{
+		v:       vectorStore,
+		numDocs: numDocuments,
+		options: options,
+		mmr:     true,
+		fetchK:  fetchK,
+		lambda:  lambda,
+	}
+}
@@ -0,0 +1,25 @@
+package vectorstores_test
+
+import (
+	"testing"
+
+	"github.com/sayerxofficial/langchaingo/vectorstores"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStableUUID(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.NewString()
+	assert.Equal(t, id, vectorstores.StableUUID("docs", id))
+
+	derived := vectorstores.StableUUID("docs", "a.md#0")
+	assert.Equal(t, derived, vectorstores.StableUUID("docs", "a.md#0"))
+	assert.NotEqual(t, derived, vectorstores.StableUUID("notes", "a.md#0"))
+	assert.NotEqual(t, derived, vectorstores.StableUUID("docs", "a.md#1"))
+
+	_, err := uuid.Parse(derived)
+	assert.NoError(t, err)
+}
@@ -62,7 +62,12 @@ type Store struct {
 	additionalFields []string
 }
 
-var _ vectorstores.VectorStore = Store{}
+var (
+	_ vectorstores.VectorStore   = Store{}
+	_ vectorstores.Deleter       = Store{}
+	_ vectorstores.FilterDeleter = Store{}
+	_ vectorstores.Upserter      = Store{}
+)
 
 // New creates a new Store with options.
 // When using weaviate,
@@ -95,7 +100,6 @@ func (s Store) AddDocuments(ctx context.Context,
 	options ...vectorstores.Option,
 ) ([]string, error) {
 	opts := s.getOptions(options...)
-	nameSpace := s.getNameSpace(opts)
 
 	docs = s.deduplicate(ctx, opts, docs)
 
@@ -105,6 +109,88 @@ func (s Store) AddDocuments(ctx context.Context,
 		return nil, nil
 	}
 
+	ids := make([]string, len(docs))
+	for i := range docs {
+		ids[i] = uuid.New().String()
+	}
+	if err := s.addObjects(ctx, opts, ids, docs); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UpsertDocuments creates vector embeddings from the documents using the embedder
+// and upserts the vectors to the weaviate index under the given ids, replacing the
+// objects already stored with the same ids. Ids that are not UUIDs are mapped to a
+// UUID derived from the name space and the id, since weaviate only accepts UUIDs as
+// object ids.
+func (s Store) UpsertDocuments(ctx context.Context,
+	ids []string,
+	docs []schema.Document,
+	options ...vectorstores.Option,
+) ([]string, error) {
+	if len(ids) != len(docs) {
+		return nil, vectorstores.ErrMismatchedIDs
+	}
+	if len(docs) == 0 {
+		return []string{}, nil
+	}
+
+	opts := s.getOptions(options...)
+	nameSpace := s.getNameSpace(opts)
+	objectIDs := make([]string, len(ids))
+	for i, id := range ids {
+		objectIDs[i] = vectorstores.StableUUID(nameSpace, id)
+	}
+	if err := s.addObjects(ctx, opts, objectIDs, docs); err != nil {
+		return nil, err
+	}
+	return objectIDs, nil
+}
+
+// Delete deletes the objects with the given ids from the name space of the index.
+func (s Store) Delete(ctx context.Context, ids []string, options ...vectorstores.Option) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	opts := s.getOptions(options...)
+	nameSpace := s.getNameSpace(opts)
+	objectIDs := make([]string, len(ids))
+	for i, id := range ids {
+		objectIDs[i] = vectorstores.StableUUID(nameSpace, id)
+	}
+
+	whereBuilder, err := s.createWhereBuilder(nameSpace,
+		filters.Where().WithPath([]string{"id"}).WithOperator(filters.ContainsAny).WithValueText(objectIDs...))
+	if err != nil {
+		return err
+	}
+	return s.deleteObjects(ctx, whereBuilder)
+}
+
+// DeleteByFilter deletes the objects matching the filter, a `*filters.WhereBuilder`
+// like the one given to WithFilters, from the name space of the index.
+func (s Store) DeleteByFilter(ctx context.Context, filter any, options ...vectorstores.Option) error {
+	if filter == nil {
+		return vectorstores.ErrEmptyFilter
+	}
+
+	opts := s.getOptions(options...)
+	whereBuilder, err := s.createWhereBuilder(s.getNameSpace(opts), filter)
+	if err != nil {
+		return err
+	}
+	return s.deleteObjects(ctx, whereBuilder)
+}
+
+func (s Store) addObjects(ctx context.Context,
+	opts vectorstores.Options,
+	ids []string,
+	docs []schema.Document,
+) error {
+	nameSpace := s.getNameSpace(opts)
+
 	texts := make([]string, 0, len(docs))
 	for _, doc := range docs {
 		texts = append(texts, doc.PageContent)
@@ -112,11 +198,11 @@ func (s Store) AddDocuments(ctx context.Context,
 
 	vectors, err := opts.Embedder.EmbedDocuments(ctx, texts)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if len(vectors) != len(docs) {
-		return nil, ErrEmbedderWrongNumberVectors
+		return ErrEmbedderWrongNumberVectors
 	}
 
 	metadatas := make([]map[string]any, 0, len(docs))
@@ -132,21 +218,30 @@ func (s Store) AddDocuments(ctx context.Context,
 	}
 
 	objects := make([]*models.Object, 0, len(docs))
-	ids := make([]string, len(docs))
 	for i := range docs {
-		id := strfmt.UUID(uuid.New().String())
-		ids[i] = id.String()
 		objects = append(objects, &models.Object{
 			Class:      s.indexName,
-			ID:         id,
+			ID:         strfmt.UUID(ids[i]),
 			Vector:     vectors[i],
 			Properties: metadatas[i],
 		})
 	}
-	if _, err := s.client.Batch().ObjectsBatcher().WithObjects(objects...).Do(ctx); err != nil {
-		return nil, err
+	_, err = s.client.Batch().ObjectsBatcher().WithObjects(objects...).Do(ctx)
+	return err
+}
+
+func (s Store) deleteObjects(ctx context.Context, whereBuilder *filters.WhereBuilder) error {
+	res, err := s.client.Batch().ObjectsBatchDeleter().
+		WithClassName(s.indexName).
+		WithWhere(whereBuilder).
+		Do(ctx)
+	if err != nil {
+		return err
 	}
-	return ids, nil
+	if res != nil && res.Results != nil && res.Results.Failed > 0 {
+		return fmt.Errorf("%w: failed to delete %d objects", ErrInvalidResponse, res.Results.Failed)
+	}
+	return nil
 }
 
 func (s Store) SimilaritySearch(
@@ -354,6 +354,68 @@ func TestDeduplicater(t *testing.T) {
 	require.Equal(t, "vegetable", docs[0].Metadata["type"])
 }
 
+func TestDeleteAndUpsert(t *testing.T) {
+	ctx := t.Context()
+	t.Parallel()
+	if testing.Short() {
+		t.Skip("skipping Weaviate tests in short mode")
+	}
+
+	scheme, host := getWeaviateTestContainerSchemeAndHost(t)
+	e := createOpenAIEmbedder(t)
+
+	store, err := New(
+		WithScheme(scheme),
+		WithHost(host),
+		WithEmbedder(e),
+		WithNameSpace(uuid.New().String()),
+		WithIndexName(randomizedCamelCaseClass()),
+		WithQueryAttrs([]string{"type"}),
+	)
+	require.NoError(t, err)
+
+	err = createTestClass(ctx, store)
+	require.NoError(t, err)
+
+	ids, err := store.UpsertDocuments(ctx, []string{"tokyo", "potato"}, []schema.Document{
+		{PageContent: "tokyo", Metadata: map[string]any{
+			"type": "city",
+		}},
+		{PageContent: "potato", Metadata: map[string]any{
+			"type": "vegetable",
+		}},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+
+	_, err = store.UpsertDocuments(ctx, []string{"tokyo"}, []schema.Document{
+		{PageContent: "kyoto", Metadata: map[string]any{
+			"type": "city",
+		}},
+	})
+	require.NoError(t, err)
+
+	docs, err := store.MetadataSearch(ctx, 3)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	err = store.DeleteByFilter(ctx, filters.Where().
+		WithPath([]string{"type"}).
+		WithOperator(filters.Equal).
+		WithValueString("vegetable"))
+	require.NoError(t, err)
+
+	docs, err = store.MetadataSearch(ctx, 3)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Equal(t, "kyoto", docs[0].PageContent)
+
+	require.NoError(t, store.Delete(ctx, []string{"tokyo"}))
+	docs, err = store.MetadataSearch(ctx, 3)
+	require.NoError(t, err)
+	require.Empty(t, docs)
+}
+
 func TestSimilaritySearchWithInvalidScoreThreshold(t *testing.T) {
 	ctx := t.Context()
 	t.Parallel()